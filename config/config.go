@@ -3,14 +3,19 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/kelseyhightower/envconfig"
 )
 
 type Configs struct {
-	APP app
-	DB  DB
+	APP   app
+	DB    DB
+	Auth  Auth
+	Purge Purge
+
+	Attachment Attachment
 }
 
 type DB struct {
@@ -21,11 +26,42 @@ type DB struct {
 	Name     string
 }
 
+type Auth struct {
+	Secret     string        `required:"true"`
+	AccessTTL  time.Duration `envconfig:"ACCESS_TTL" default:"15m"`
+	RefreshTTL time.Duration `envconfig:"REFRESH_TTL" default:"168h"`
+
+	// AdminEmail and AdminPassword seed an admin account on startup when set
+	AdminEmail    string `envconfig:"ADMIN_EMAIL"`
+	AdminPassword string `envconfig:"ADMIN_PASSWORD"`
+}
+
+// Purge controls how long soft deleted rows stay in the trash before they are
+// removed for good and how often that is checked.
+type Purge struct {
+	Retention time.Duration `default:"720h"`
+	Interval  time.Duration `default:"1h"`
+}
+
+// Attachment limits the files uploaded to tasks, the content type is detected
+// from the file itself.
+type Attachment struct {
+	Dir          string   `default:"data/attachments"`
+	MaxSize      int64    `envconfig:"MAX_SIZE" default:"10485760"`
+	AllowedTypes []string `envconfig:"ALLOWED_TYPES" default:"image/png,image/jpeg,image/gif,image/webp,application/pdf,text/plain"`
+}
+
 type app struct {
-	Port string
-	Path string
+	Port  string
+	Path  string
+	Store string `default:"postgres"`
 }
 
+const (
+	StorePostgres = "postgres"
+	StoreMemory   = "memory"
+)
+
 func New() (cfg Configs, err error) {
 	root, err := os.Getwd()
 	if err != nil {
@@ -45,5 +81,17 @@ func New() (cfg Configs, err error) {
 		return
 	}
 
+	if err = envconfig.Process("AUTH", &cfg.Auth); err != nil {
+		return
+	}
+
+	if err = envconfig.Process("PURGE", &cfg.Purge); err != nil {
+		return
+	}
+
+	if err = envconfig.Process("ATTACHMENT", &cfg.Attachment); err != nil {
+		return
+	}
+
 	return
 }
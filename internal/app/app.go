@@ -2,15 +2,17 @@ package app
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"os/signal"
 	"project-management/config"
+	"project-management/internal/domain/attachment"
 	"project-management/internal/handler"
 	"project-management/internal/repository"
-	"project-management/internal/repository/postgres"
 	"project-management/internal/service/management"
 	"project-management/pkg/log"
 	"project-management/pkg/server"
+	"project-management/pkg/token"
 	"syscall"
 	"time"
 )
@@ -24,28 +26,63 @@ func Run() {
 		return
 	}
 
-	db, err := postgres.New(configs.DB)
-	if err != nil {
-		logger.Err(err).Stack().Msg("failed to connect to database")
+	var store repository.Configuration
+	switch configs.APP.Store {
+	case config.StorePostgres:
+		store = repository.WithPostgresStore(configs.DB)
+	case config.StoreMemory:
+		store = repository.WithMemoryStore()
+	default:
+		err = fmt.Errorf("unknown APP_STORE %q, use %s or %s", configs.APP.Store, config.StorePostgres, config.StoreMemory)
+		logger.Err(err).Stack().Msg("failed to load configurations")
 		return
 	}
-	defer db.Close()
 
-	repositories, err := repository.New(repository.WithPostgresStore(configs.DB))
+	repositories, err := repository.New(store, repository.WithLocalBlobStore(configs.Attachment.Dir))
 	if err != nil {
 		logger.Err(err).Stack().Msg("failed to create repositories")
 		return
 	}
+	defer repositories.Close()
+
+	tokenManager := token.New(configs.Auth.Secret, configs.Auth.AccessTTL, configs.Auth.RefreshTTL)
 
 	managementService := management.New(
 		management.WithProjectRepository(repositories.Project),
 		management.WithTaskRepository(repositories.Task),
 		management.WithUserRepository(repositories.User),
+		management.WithProjectMemberRepository(repositories.ProjectMember),
+		management.WithTaskWorkflowRepository(repositories.TaskWorkflow),
+		management.WithTaskDependencyRepository(repositories.TaskDependency),
+		management.WithAuditRepository(repositories.Audit),
+		management.WithCommentRepository(repositories.Comment),
+		management.WithLabelRepository(repositories.Label),
+		management.WithSprintRepository(repositories.Sprint),
+		management.WithMilestoneRepository(repositories.Milestone),
+		management.WithTxManager(repositories.Tx),
+		management.WithAttachmentRepository(repositories.Attachment),
+		management.WithBlobStore(repositories.Blob, attachment.Limits{
+			MaxSize:      configs.Attachment.MaxSize,
+			AllowedTypes: configs.Attachment.AllowedTypes,
+		}),
+		management.WithTokenManager(tokenManager),
 	)
 
+	if configs.Auth.AdminEmail != "" {
+		if err := managementService.SeedAdmin(context.Background(), configs.Auth.AdminEmail, configs.Auth.AdminPassword); err != nil {
+			logger.Err(err).Stack().Msg("failed to seed admin")
+			return
+		}
+	}
+
+	purgeCtx, stopPurge := context.WithCancel(context.Background())
+	defer stopPurge()
+	go managementService.RunPurge(purgeCtx, configs.Purge.Retention, configs.Purge.Interval)
+
 	handler := handler.New(
 		handler.Dependencies{
 			ManagementService: managementService,
+			TokenManager:      tokenManager,
 		},
 		handler.WithHTTPHandler())
 
@@ -67,6 +104,7 @@ func Run() {
 
 	<-shutdown
 	logger.Info().Msg("shutting down server")
+	stopPurge()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
@@ -0,0 +1,36 @@
+package attachment
+
+import (
+	"time"
+)
+
+type Response struct {
+	ID          string `json:"id"`
+	TaskID      string `json:"task_id"`
+	Name        string `json:"name"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+	UploaderID  string `json:"uploader_id"`
+	CreatedAt   string `json:"created_at"`
+}
+
+func ParseFromEntity(data Entity) Response {
+	return Response{
+		ID:          data.ID,
+		TaskID:      data.TaskID,
+		Name:        data.Name,
+		ContentType: data.ContentType,
+		Size:        data.Size,
+		UploaderID:  data.UploaderID,
+		CreatedAt:   data.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func ParseFromEntities(data []Entity) []Response {
+	res := make([]Response, len(data))
+	for i, a := range data {
+		res[i] = ParseFromEntity(a)
+	}
+
+	return res
+}
@@ -0,0 +1,64 @@
+package attachment
+
+import (
+	"mime"
+	"time"
+)
+
+// Entity is the metadata of a file attached to a task, the content itself is
+// kept in a BlobStore under Key.
+type Entity struct {
+	ID          string
+	TaskID      string    `db:"task_id"`
+	Name        string    `db:"name"`
+	ContentType string    `db:"content_type"`
+	Size        int64     `db:"size"`
+	UploaderID  string    `db:"uploader_id"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+func (e Entity) Key() string {
+	return e.TaskID + "/" + e.ID
+}
+
+// Limits restrict what can be uploaded. The content type is detected from the
+// uploaded bytes, the one claimed by the client is ignored.
+type Limits struct {
+	MaxSize      int64
+	AllowedTypes []string
+}
+
+func (l Limits) Allows(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	for _, allowed := range l.AllowedTypes {
+		if allowed == mediaType {
+			return true
+		}
+	}
+
+	return false
+}
+
+var (
+	ErrNotFound       = &AttachmentError{"attachment not found"}
+	ErrBlobNotFound   = &AttachmentError{"attachment content not found"}
+	ErrTooLarge       = &AttachmentError{"attachment is too large"}
+	ErrTypeNotAllowed = &AttachmentError{"attachment type is not allowed"}
+	ErrInvalidName    = &AttachmentError{"attachment name is invalid"}
+)
+
+type AttachmentError struct {
+	message string
+}
+
+func (e *AttachmentError) Error() string {
+	return e.message
+}
+
+func (e *AttachmentError) Is(err error) bool {
+	return e == err
+}
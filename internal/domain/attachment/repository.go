@@ -0,0 +1,26 @@
+package attachment
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+type Repository interface {
+	Create(ctx context.Context, data Entity) (id string, err error)
+	Get(ctx context.Context, id string) (Entity, error)
+	List(ctx context.Context, taskID string) ([]Entity, error)
+	Delete(ctx context.Context, id string) error
+	// Expired lists the attachments of tasks deleted before the given time,
+	// their content has to be removed when the tasks are purged.
+	Expired(ctx context.Context, before time.Time) ([]Entity, error)
+}
+
+// BlobStore keeps the content of attachments. Keys are slash separated paths
+// made of ids only.
+type BlobStore interface {
+	// Put stores the content read from r under key and returns its size
+	Put(ctx context.Context, key string, r io.Reader) (int64, error)
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
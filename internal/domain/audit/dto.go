@@ -0,0 +1,56 @@
+package audit
+
+import (
+	"net/url"
+	"project-management/internal/domain"
+	"time"
+)
+
+type Response struct {
+	ID         int64   `json:"id"`
+	ActorID    string  `json:"actor_id"`
+	EntityType string  `json:"entity"`
+	EntityID   string  `json:"entity_id"`
+	Action     string  `json:"action"`
+	Changes    Changes `json:"changes"`
+	CreatedAt  string  `json:"created_at"`
+}
+
+func ParseFromEntry(e Entry) Response {
+	return Response{
+		ID:         e.ID,
+		ActorID:    e.ActorID,
+		EntityType: e.EntityType,
+		EntityID:   e.EntityID,
+		Action:     e.Action,
+		Changes:    e.Changes,
+		CreatedAt:  e.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// ParseFilter reads the entity, id and since query parameters. since accepts
+// either a date or an RFC 3339 timestamp.
+func ParseFilter(q url.Values) (f Filter, errs []domain.ErrorResponse) {
+	f.EntityType = q.Get("entity")
+	if f.EntityType != "" && !IsValidEntityType(f.EntityType) {
+		errs = append(errs, domain.ErrorResponse{Message: "entity must be user, project or task", Field: "entity"})
+	}
+
+	f.EntityID = q.Get("id")
+	if f.EntityID != "" && f.EntityType == "" {
+		errs = append(errs, domain.ErrorResponse{Message: "id requires entity", Field: "id"})
+	}
+
+	if since := q.Get("since"); since != "" {
+		t, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			t, err = time.Parse(domain.DateLayout, since)
+		}
+		if err != nil {
+			errs = append(errs, domain.ErrorResponse{Message: "invalid since format", Field: "since"})
+		}
+		f.Since = t
+	}
+
+	return
+}
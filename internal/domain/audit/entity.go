@@ -0,0 +1,125 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+const (
+	EntityUser    = "user"
+	EntityProject = "project"
+	EntityTask    = "task"
+)
+
+const (
+	ActionCreate  = "create"
+	ActionUpdate  = "update"
+	ActionDelete  = "delete"
+	ActionRestore = "restore"
+
+	ActionAddMember    = "add_member"
+	ActionRemoveMember = "remove_member"
+)
+
+func IsValidEntityType(entityType string) bool {
+	return entityType == EntityUser || entityType == EntityProject || entityType == EntityTask
+}
+
+// Entry records a single change of a user, project or task. ActorID is empty
+// for changes the application makes on its own, such as seeding the admin.
+type Entry struct {
+	ID         int64     `db:"id"`
+	ActorID    string    `db:"actor_id"`
+	EntityType string    `db:"entity_type"`
+	EntityID   string    `db:"entity_id"`
+	Action     string    `db:"action"`
+	Changes    Changes   `db:"changes"`
+	CreatedAt  time.Time `db:"created_at"`
+}
+
+// Change holds the value of a field before and after the operation, Old is
+// nil for created and New for deleted entities.
+type Change struct {
+	Old any `json:"old"`
+	New any `json:"new"`
+}
+
+// Changes maps field names to their change and is stored as JSON.
+type Changes map[string]Change
+
+// method of [driver.Valuer] interface
+func (c Changes) Value() (interface{}, error) {
+	return json.Marshal(c)
+}
+
+// method of [sql.Scanner] interface
+func (c *Changes) Scan(val interface{}) error {
+	var data []byte
+	switch v := val.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("expected json, got %T", val)
+	}
+
+	return json.Unmarshal(data, c)
+}
+
+// Diff compares the JSON representation of two values and returns the fields
+// that differ. Either value may be nil, which records every field of the other
+// one as created or deleted.
+func Diff(before, after any) (Changes, error) {
+	oldFields, err := fields(before)
+	if err != nil {
+		return nil, err
+	}
+
+	newFields, err := fields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := Changes{}
+	for k, v := range oldFields {
+		if n, ok := newFields[k]; !ok || !reflect.DeepEqual(v, n) {
+			changes[k] = Change{Old: v, New: newFields[k]}
+		}
+	}
+	for k, v := range newFields {
+		if _, ok := oldFields[k]; !ok {
+			changes[k] = Change{New: v}
+		}
+	}
+
+	return changes, nil
+}
+
+func fields(v any) (res map[string]any, err error) {
+	res = map[string]any{}
+	if v == nil {
+		return
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(data, &res)
+
+	// the version counts writes, it is not a change of its own
+	delete(res, "version")
+
+	return
+}
+
+// Filter narrows the audit log, zero fields match everything.
+type Filter struct {
+	EntityType string
+	EntityID   string
+	Since      time.Time
+}
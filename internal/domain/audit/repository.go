@@ -0,0 +1,11 @@
+package audit
+
+import (
+	"context"
+	"project-management/internal/domain"
+)
+
+type Repository interface {
+	Record(ctx context.Context, e Entry) error
+	List(ctx context.Context, filter Filter, page domain.PageRequest) (domain.Page[Entry], error)
+}
@@ -0,0 +1,45 @@
+package auth
+
+import (
+	"project-management/internal/domain"
+)
+
+type LoginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+func (l *LoginRequest) Validate() []domain.ErrorResponse {
+	var errs []domain.ErrorResponse
+
+	if l.Email == "" {
+		errs = append(errs, domain.ErrorResponse{Message: "email is required", Field: "email"})
+	}
+
+	if l.Password == "" {
+		errs = append(errs, domain.ErrorResponse{Message: "password is required", Field: "password"})
+	}
+
+	return errs
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+func (r *RefreshRequest) Validate() []domain.ErrorResponse {
+	var errs []domain.ErrorResponse
+
+	if r.RefreshToken == "" {
+		errs = append(errs, domain.ErrorResponse{Message: "refresh_token is required", Field: "refresh_token"})
+	}
+
+	return errs
+}
+
+type TokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+}
@@ -0,0 +1,20 @@
+package auth
+
+var (
+	ErrInvalidCredentials = &AuthError{"invalid email or password"}
+	ErrInvalidToken       = &AuthError{"invalid token"}
+	ErrUnauthenticated    = &AuthError{"authentication required"}
+	ErrForbidden          = &AuthError{"access denied"}
+)
+
+type AuthError struct {
+	message string
+}
+
+func (e *AuthError) Error() string {
+	return e.message
+}
+
+func (e *AuthError) Is(err error) bool {
+	return e == err
+}
@@ -0,0 +1,86 @@
+package comment
+
+import (
+	"project-management/internal/domain"
+	"strings"
+	"time"
+)
+
+type Request struct {
+	Body     string `json:"body"`
+	ParentID string `json:"parent_id"`
+}
+
+func (r Request) Validate() []domain.ErrorResponse {
+	return validateBody(r.Body)
+}
+
+type UpdateRequest struct {
+	Body string `json:"body"`
+}
+
+func (r UpdateRequest) Validate() []domain.ErrorResponse {
+	return validateBody(r.Body)
+}
+
+func validateBody(body string) (errs []domain.ErrorResponse) {
+	switch {
+	case strings.TrimSpace(body) == "":
+		errs = append(errs, domain.ErrorResponse{Message: "body is required", Field: "body"})
+	case len(body) > 10000:
+		errs = append(errs, domain.ErrorResponse{Message: "body must be at most 10000 characters", Field: "body"})
+	}
+
+	return
+}
+
+// Response is a comment with its replies. Deleted comments that still have
+// replies are kept with an empty body so the thread stays readable.
+type Response struct {
+	ID        string     `json:"id"`
+	TaskID    string     `json:"task_id"`
+	ParentID  string     `json:"parent_id,omitempty"`
+	AuthorID  string     `json:"author_id"`
+	Body      string     `json:"body"`
+	CreatedAt string     `json:"created_at"`
+	UpdatedAt string     `json:"updated_at"`
+	Deleted   bool       `json:"deleted,omitempty"`
+	Replies   []Response `json:"replies"`
+}
+
+func ParseFromEntity(data Entity) Response {
+	return Response{
+		ID:        data.ID,
+		TaskID:    data.TaskID,
+		ParentID:  data.ParentID,
+		AuthorID:  data.AuthorID,
+		Body:      data.Body,
+		CreatedAt: data.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: data.UpdatedAt.Format(time.RFC3339),
+		Deleted:   data.DeletedAt != nil,
+		Replies:   []Response{},
+	}
+}
+
+// ParseThreads nests the comments of a task under the comments they reply to.
+// The comments are expected in the order they were written, which is the order
+// the threads and their replies are returned in.
+func ParseThreads(data []Entity) []Response {
+	children := map[string][]Entity{}
+	for _, c := range data {
+		children[c.ParentID] = append(children[c.ParentID], c)
+	}
+
+	var build func(parentID string) []Response
+	build = func(parentID string) []Response {
+		res := []Response{}
+		for _, c := range children[parentID] {
+			r := ParseFromEntity(c)
+			r.Replies = build(c.ID)
+			res = append(res, r)
+		}
+		return res
+	}
+
+	return build("")
+}
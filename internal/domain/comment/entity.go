@@ -0,0 +1,37 @@
+package comment
+
+import (
+	"time"
+)
+
+// Entity is a comment on a task. Replies name the comment they answer in
+// ParentID, top level comments leave it empty.
+type Entity struct {
+	ID        string
+	TaskID    string     `db:"task_id"`
+	ParentID  string     `db:"parent_id"`
+	AuthorID  string     `db:"author_id"`
+	Body      string     `db:"body"`
+	CreatedAt time.Time  `db:"created_at"`
+	UpdatedAt time.Time  `db:"updated_at"`
+	DeletedAt *time.Time `db:"deleted_at"`
+}
+
+var (
+	ErrNotFound       = &CommentError{"comment not found"}
+	ErrParentNotFound = &CommentError{"the comment replied to does not belong to the task"}
+	ErrNotAuthor      = &CommentError{"only the author can change the comment"}
+	ErrDeleted        = &CommentError{"the comment is deleted"}
+)
+
+type CommentError struct {
+	message string
+}
+
+func (e *CommentError) Error() string {
+	return e.message
+}
+
+func (e *CommentError) Is(err error) bool {
+	return e == err
+}
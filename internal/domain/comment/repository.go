@@ -0,0 +1,16 @@
+package comment
+
+import (
+	"context"
+)
+
+type Repository interface {
+	Create(ctx context.Context, data Entity) (id string, err error)
+	Get(ctx context.Context, id string) (Entity, error)
+	// List returns the comments of the task in the order they were written
+	List(ctx context.Context, taskID string) ([]Entity, error)
+	Update(ctx context.Context, id, body string) error
+	// Delete removes the comment, a comment with replies is only emptied and
+	// marked deleted so that the replies keep their place in the thread.
+	Delete(ctx context.Context, id string) error
+}
@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// DeletedScope tells repositories which soft deleted rows a read returns.
+// Writes never touch deleted rows apart from restoring them.
+type DeletedScope int
+
+const (
+	ExcludeDeleted DeletedScope = iota
+	IncludeDeleted
+	OnlyDeleted
+)
+
+var ErrNotDeleted = errors.New("not in the trash")
+
+type deletedScopeKey struct{}
+
+func WithDeletedScope(ctx context.Context, scope DeletedScope) context.Context {
+	return context.WithValue(ctx, deletedScopeKey{}, scope)
+}
+
+// DeletedScopeFromContext defaults to hiding deleted rows.
+func DeletedScopeFromContext(ctx context.Context) DeletedScope {
+	scope, _ := ctx.Value(deletedScopeKey{}).(DeletedScope)
+	return scope
+}
+
+// FormatTime renders an optional time such as the deletion time of a row for
+// responses, empty when it is not set.
+func FormatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+
+	return t.Format(time.RFC3339)
+}
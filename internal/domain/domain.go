@@ -27,8 +27,13 @@ func (o *OnlyDate) Value() (interface{}, error) {
 	return nt, nil
 }
 
-// method of [sql.Scanner] interface
+// method of [sql.Scanner] interface, NULL scans as an empty date
 func (o *OnlyDate) Scan(val interface{}) error {
+	if val == nil {
+		*o = ""
+		return nil
+	}
+
 	nt, ok := val.(time.Time)
 	if !ok {
 		return fmt.Errorf("expected time.Time, got %T", val)
@@ -43,6 +48,13 @@ func (o *OnlyDate) String() string {
 	return string(*o)
 }
 
+// Ranked is an entity found by full-text search together with its relevance,
+// a higher rank is a better match.
+type Ranked[T any] struct {
+	Entity T
+	Rank   float64
+}
+
 func GenerateID() string {
 	bytes := make([]byte, 12)
 	rand.Read(bytes)
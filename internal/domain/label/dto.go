@@ -0,0 +1,77 @@
+package label
+
+import (
+	"project-management/internal/domain"
+	"strings"
+)
+
+type Request struct {
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+func (r *Request) Validate() []domain.ErrorResponse {
+	var errs []domain.ErrorResponse
+
+	if strings.TrimSpace(r.Name) == "" {
+		errs = append(errs, domain.ErrorResponse{Message: "name is required", Field: "name"})
+	}
+
+	if len(r.Name) > 32 {
+		errs = append(errs, domain.ErrorResponse{Message: "name must be at most 32 characters", Field: "name"})
+	}
+
+	if r.Color != "" && !color.MatchString(r.Color) {
+		errs = append(errs, domain.ErrorResponse{Message: "color must be written as #rrggbb", Field: "color"})
+	}
+
+	return errs
+}
+
+type UpdateRequest struct {
+	Name  string `json:"name,omitempty"`
+	Color string `json:"color,omitempty"`
+}
+
+func (r *UpdateRequest) Validate() []domain.ErrorResponse {
+	var errs []domain.ErrorResponse
+
+	if r.Name != "" && strings.TrimSpace(r.Name) == "" {
+		errs = append(errs, domain.ErrorResponse{Message: "name must not be blank", Field: "name"})
+	}
+
+	if len(r.Name) > 32 {
+		errs = append(errs, domain.ErrorResponse{Message: "name must be at most 32 characters", Field: "name"})
+	}
+
+	if r.Color != "" && !color.MatchString(r.Color) {
+		errs = append(errs, domain.ErrorResponse{Message: "color must be written as #rrggbb", Field: "color"})
+	}
+
+	return errs
+}
+
+type Response struct {
+	ID        string `json:"id"`
+	ProjectID string `json:"project_id"`
+	Name      string `json:"name"`
+	Color     string `json:"color"`
+}
+
+func ParseFromEntity(data Entity) Response {
+	return Response{
+		ID:        data.ID,
+		ProjectID: data.ProjectID,
+		Name:      data.Name,
+		Color:     data.Color,
+	}
+}
+
+func ParseFromEntities(data []Entity) []Response {
+	res := make([]Response, len(data))
+	for i, l := range data {
+		res[i] = ParseFromEntity(l)
+	}
+
+	return res
+}
@@ -0,0 +1,36 @@
+package label
+
+import (
+	"regexp"
+)
+
+// Entity is a label of a project, it can be put on any task of that project.
+// Names are unique within the project.
+type Entity struct {
+	ID        string
+	ProjectID string `db:"project_id"`
+	Name      string `db:"name"`
+	Color     string `db:"color"`
+}
+
+const DefaultColor = "#808080"
+
+var color = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+var (
+	ErrExists       = &LabelError{"label already exists in the project"}
+	ErrNotFound     = &LabelError{"label not found"}
+	ErrOtherProject = &LabelError{"label belongs to another project"}
+)
+
+type LabelError struct {
+	message string
+}
+
+func (e *LabelError) Error() string {
+	return e.message
+}
+
+func (e *LabelError) Is(err error) bool {
+	return e == err
+}
@@ -0,0 +1,22 @@
+package label
+
+import (
+	"context"
+)
+
+type Repository interface {
+	Create(ctx context.Context, data Entity) (id string, err error)
+	Get(ctx context.Context, id string) (Entity, error)
+	List(ctx context.Context, projectID string) ([]Entity, error)
+	// Update changes the non empty fields of data
+	Update(ctx context.Context, id string, data Entity) error
+	// Delete removes the label from the project and all of its tasks
+	Delete(ctx context.Context, id string) error
+
+	TaskLabels(ctx context.Context, taskID string) ([]Entity, error)
+	// Attach puts the label on the task, attaching it twice is not an error
+	Attach(ctx context.Context, taskID, labelID string) error
+	Detach(ctx context.Context, taskID, labelID string) error
+	// DetachAll takes every label off the task, used when it changes project
+	DetachAll(ctx context.Context, taskID string) error
+}
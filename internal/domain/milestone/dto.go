@@ -0,0 +1,87 @@
+package milestone
+
+import (
+	"project-management/internal/domain"
+	"strings"
+	"time"
+)
+
+type Request struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	DueDate     string `json:"due_date"`
+}
+
+func (r *Request) Validate() []domain.ErrorResponse {
+	var errs []domain.ErrorResponse
+
+	if strings.TrimSpace(r.Title) == "" {
+		errs = append(errs, domain.ErrorResponse{Message: "title is required", Field: "title"})
+	}
+
+	if len(r.Title) > 100 {
+		errs = append(errs, domain.ErrorResponse{Message: "title must be at most 100 characters", Field: "title"})
+	}
+
+	if len(r.Description) > 500 {
+		errs = append(errs, domain.ErrorResponse{Message: "description must be at most 500 characters", Field: "description"})
+	}
+
+	if _, err := time.Parse(domain.DateLayout, r.DueDate); err != nil {
+		errs = append(errs, domain.ErrorResponse{Message: "invalid due_date format", Field: "due_date"})
+	}
+
+	return errs
+}
+
+type UpdateRequest struct {
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	DueDate     string `json:"due_date,omitempty"`
+}
+
+func (r *UpdateRequest) Validate() []domain.ErrorResponse {
+	var errs []domain.ErrorResponse
+
+	if r.Title != "" && strings.TrimSpace(r.Title) == "" {
+		errs = append(errs, domain.ErrorResponse{Message: "title must not be blank", Field: "title"})
+	}
+
+	if len(r.Title) > 100 {
+		errs = append(errs, domain.ErrorResponse{Message: "title must be at most 100 characters", Field: "title"})
+	}
+
+	if len(r.Description) > 500 {
+		errs = append(errs, domain.ErrorResponse{Message: "description must be at most 500 characters", Field: "description"})
+	}
+
+	if _, err := time.Parse(domain.DateLayout, r.DueDate); r.DueDate != "" && err != nil {
+		errs = append(errs, domain.ErrorResponse{Message: "invalid due_date format", Field: "due_date"})
+	}
+
+	return errs
+}
+
+// LinkRequest puts a task on a milestone, an empty MilestoneID unlinks it.
+type LinkRequest struct {
+	MilestoneID string `json:"milestone_id"`
+}
+
+type Response struct {
+	ID          string    `json:"id"`
+	ProjectID   string    `json:"project_id"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	DueDate     string    `json:"due_date"`
+	Progress    *Progress `json:"progress,omitempty"`
+}
+
+func ParseFromEntity(data Entity) Response {
+	return Response{
+		ID:          data.ID,
+		ProjectID:   data.ProjectID,
+		Title:       data.Title,
+		Description: data.Description,
+		DueDate:     data.DueDate.String(),
+	}
+}
@@ -0,0 +1,32 @@
+package milestone
+
+import (
+	"project-management/internal/domain"
+)
+
+// Entity is a target date of a project that tasks of the project can be
+// linked to.
+type Entity struct {
+	ID          string
+	ProjectID   string          `db:"project_id"`
+	Title       string          `db:"title"`
+	Description string          `db:"description"`
+	DueDate     domain.OnlyDate `db:"due_date"`
+}
+
+var (
+	ErrNotFound     = &MilestoneError{"milestone not found"}
+	ErrOtherProject = &MilestoneError{"milestone belongs to another project"}
+)
+
+type MilestoneError struct {
+	message string
+}
+
+func (e *MilestoneError) Error() string {
+	return e.message
+}
+
+func (e *MilestoneError) Is(err error) bool {
+	return e == err
+}
@@ -0,0 +1,21 @@
+package milestone
+
+import (
+	"context"
+	"project-management/internal/domain/task"
+)
+
+type Repository interface {
+	Create(ctx context.Context, data Entity) (id string, err error)
+	Get(ctx context.Context, id string) (Entity, error)
+	// List returns the milestones of the project ordered by due date
+	List(ctx context.Context, projectID string) ([]Entity, error)
+	// Update changes the non empty fields of data
+	Update(ctx context.Context, id string, data Entity) error
+	// Delete removes the milestone, its tasks are unlinked
+	Delete(ctx context.Context, id string) error
+
+	// Link puts the task on the milestone, an empty milestoneID unlinks it.
+	// The events are stored in the same transaction.
+	Link(ctx context.Context, taskID, milestoneID string, events ...task.Event) error
+}
@@ -0,0 +1,59 @@
+package milestone
+
+import (
+	"project-management/internal/domain"
+	"time"
+)
+
+const (
+	StatusCompleted = "completed"
+	StatusOnTrack   = "on_track"
+	StatusAtRisk    = "at_risk"
+	StatusOverdue   = "overdue"
+)
+
+// AtRiskWindow is how close to its due date a milestone with open tasks is
+// considered at risk.
+const AtRiskWindow = 7 * 24 * time.Hour
+
+// Progress sums up the tasks linked to a milestone.
+type Progress struct {
+	Total   int    `json:"total_tasks"`
+	Done    int    `json:"done_tasks"`
+	Percent int    `json:"percent"`
+	Status  string `json:"status"`
+}
+
+// Evaluate computes the progress of a milestone due at due with total linked
+// tasks of which done are finished. A milestone is completed once it has
+// tasks and all of them are done, overdue when tasks are still open after the
+// due date and at risk when they are within AtRiskWindow of it.
+func Evaluate(due domain.OnlyDate, total, done int, now time.Time) Progress {
+	p := Progress{Total: total, Done: done}
+
+	if total > 0 {
+		p.Percent = done * 100 / total
+	}
+
+	dueAt, err := time.Parse(domain.DateLayout, string(due))
+	if err != nil {
+		p.Status = StatusOnTrack
+		return p
+	}
+
+	// the milestone is due at the end of its due date
+	left := dueAt.AddDate(0, 0, 1).Sub(now)
+
+	switch {
+	case total > 0 && done == total:
+		p.Status = StatusCompleted
+	case left <= 0:
+		p.Status = StatusOverdue
+	case left <= AtRiskWindow:
+		p.Status = StatusAtRisk
+	default:
+		p.Status = StatusOnTrack
+	}
+
+	return p
+}
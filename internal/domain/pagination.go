@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// PageRequest selects a slice of an ordered listing. Rows are always ordered by
+// their creation date and then by id, so a cursor stays stable while new rows
+// are inserted. Cursor and Offset are mutually exclusive.
+type PageRequest struct {
+	Limit  int
+	Offset int
+	Cursor string
+}
+
+func (p *PageRequest) Validate() []ErrorResponse {
+	var errs []ErrorResponse
+
+	if p.Limit == 0 {
+		p.Limit = DefaultPageLimit
+	}
+
+	if p.Limit < 0 || p.Limit > MaxPageLimit {
+		errs = append(errs, ErrorResponse{Message: "limit must be between 1 and 100", Field: "limit"})
+	}
+
+	if p.Offset < 0 {
+		errs = append(errs, ErrorResponse{Message: "offset must not be negative", Field: "offset"})
+	}
+
+	if p.Cursor != "" {
+		if p.Offset != 0 {
+			errs = append(errs, ErrorResponse{Message: "cursor and offset can not be used together", Field: "cursor"})
+		}
+
+		if _, err := DecodeCursor(p.Cursor); err != nil {
+			errs = append(errs, ErrorResponse{Message: "invalid cursor", Field: "cursor"})
+		}
+	}
+
+	return errs
+}
+
+type Page[T any] struct {
+	Items      []T    `json:"items"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	Total      int    `json:"total"`
+}
+
+func MapPage[T, R any](p Page[T], parse func(T) R) Page[R] {
+	res := Page[R]{
+		Items:      make([]R, 0, len(p.Items)),
+		NextCursor: p.NextCursor,
+		Total:      p.Total,
+	}
+
+	for _, item := range p.Items {
+		res.Items = append(res.Items, parse(item))
+	}
+
+	return res
+}
+
+// Cursor points at the last row of the previous page.
+type Cursor struct {
+	Date string
+	ID   string
+}
+
+func EncodeCursor(date, id string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(date + "|" + id))
+}
+
+func DecodeCursor(s string) (c Cursor, err error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, ErrInvalidCursor
+	}
+
+	date, id, ok := strings.Cut(string(raw), "|")
+	if !ok || id == "" {
+		return c, ErrInvalidCursor
+	}
+
+	if _, err = time.Parse(DateLayout, date); err != nil {
+		return c, ErrInvalidCursor
+	}
+
+	return Cursor{Date: date, ID: id}, nil
+}
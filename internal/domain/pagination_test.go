@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+)
+
+func TestCursor(t *testing.T) {
+	tests := []struct {
+		name   string
+		cursor string
+		want   Cursor
+		err    error
+	}{
+		{
+			name:   "round trip",
+			cursor: EncodeCursor("2024-01-02", "abc"),
+			want:   Cursor{Date: "2024-01-02", ID: "abc"},
+		},
+		{
+			name:   "id holding the separator",
+			cursor: EncodeCursor("2024-01-02", "a|b"),
+			want:   Cursor{Date: "2024-01-02", ID: "a|b"},
+		},
+		{
+			name:   "not base64",
+			cursor: "not a cursor!",
+			err:    ErrInvalidCursor,
+		},
+		{
+			name:   "padded base64",
+			cursor: base64.URLEncoding.EncodeToString([]byte("2024-01-02|ab")),
+			err:    ErrInvalidCursor,
+		},
+		{
+			name:   "missing separator",
+			cursor: base64.RawURLEncoding.EncodeToString([]byte("2024-01-02")),
+			err:    ErrInvalidCursor,
+		},
+		{
+			name:   "missing id",
+			cursor: EncodeCursor("2024-01-02", ""),
+			err:    ErrInvalidCursor,
+		},
+		{
+			name:   "invalid date",
+			cursor: EncodeCursor("02.01.2024", "abc"),
+			err:    ErrInvalidCursor,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeCursor(tt.cursor)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("got error %v, want %v", err, tt.err)
+			}
+			if got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPageRequestValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		page  PageRequest
+		limit int
+		errs  int
+	}{
+		{name: "default limit", page: PageRequest{}, limit: DefaultPageLimit},
+		{name: "limit too large", page: PageRequest{Limit: MaxPageLimit + 1}, limit: MaxPageLimit + 1, errs: 1},
+		{name: "negative offset", page: PageRequest{Limit: 1, Offset: -1}, limit: 1, errs: 1},
+		{name: "cursor", page: PageRequest{Limit: 1, Cursor: EncodeCursor("2024-01-02", "a")}, limit: 1},
+		{name: "cursor with offset", page: PageRequest{Limit: 1, Offset: 1, Cursor: EncodeCursor("2024-01-02", "a")}, limit: 1, errs: 1},
+		{name: "invalid cursor", page: PageRequest{Limit: 1, Cursor: "x"}, limit: 1, errs: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := tt.page.Validate()
+			if len(errs) != tt.errs {
+				t.Errorf("got errors %+v, want %d", errs, tt.errs)
+			}
+			if tt.page.Limit != tt.limit {
+				t.Errorf("got limit %d, want %d", tt.page.Limit, tt.limit)
+			}
+		})
+	}
+}
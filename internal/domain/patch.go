@@ -0,0 +1,46 @@
+package domain
+
+import "encoding/json"
+
+// Nullable is a field of a JSON Merge Patch (RFC 7396). Set tells whether the
+// patch has the field at all and Null whether it is null.
+type Nullable[T comparable] struct {
+	Value T
+	Set   bool
+	Null  bool
+}
+
+// UnmarshalJSON is only called for fields present in the document, null
+// included, so absent fields keep Set false.
+func (n *Nullable[T]) UnmarshalJSON(data []byte) error {
+	n.Set = true
+
+	if string(data) == "null" {
+		n.Null = true
+		return nil
+	}
+
+	return json.Unmarshal(data, &n.Value)
+}
+
+// Cleared reports whether the patch clears the field with null.
+func (n Nullable[T]) Cleared() bool {
+	return n.Set && n.Null
+}
+
+// Empty reports whether the patch sets the field to the zero value of T, which
+// validation rejects for the fields where it means nothing.
+func (n Nullable[T]) Empty() bool {
+	var zero T
+	return n.Set && !n.Null && n.Value == zero
+}
+
+// Ptr returns the value the patch sets, nil when it leaves the field alone or
+// clears it.
+func (n Nullable[T]) Ptr() *T {
+	if !n.Set || n.Null {
+		return nil
+	}
+
+	return &n.Value
+}
@@ -5,10 +5,11 @@ import (
 	"time"
 )
 
+// Request creates a project, the planned end in finished_at is optional.
 type Request struct {
 	Title       string `json:"title"`
 	Description string `json:"description"`
-	FinishedAt  string `json:"finished_at"`
+	FinishedAt  string `json:"finished_at,omitempty"`
 	StartedAt   string `json:"started_at"`
 	ManagerID   string `json:"manager_id"`
 }
@@ -35,7 +36,7 @@ func (p *Request) Validate() []domain.ErrorResponse {
 		errs = append(errs, domain.ErrorResponse{Message: "invalid started_at format", Field: "started_at"})
 	}
 
-	if _, err := time.Parse(domain.DateLayout, p.FinishedAt); err != nil {
+	if _, err := time.Parse(domain.DateLayout, p.FinishedAt); p.FinishedAt != "" && err != nil {
 		errs = append(errs, domain.ErrorResponse{Message: "invalid finished_at format", Field: "finished_at"})
 	}
 
@@ -60,13 +61,74 @@ func (p *UpdateRequest) Validate() []domain.ErrorResponse {
 	return errs
 }
 
+// PatchRequest is a JSON Merge Patch (RFC 7396) of a project. Absent fields
+// stay as they are, null clears the description, the planned end and the
+// manager. The title can not be cleared and only the description can be
+// empty.
+type PatchRequest struct {
+	Title       domain.Nullable[string] `json:"title" swaggertype:"string"`
+	Description domain.Nullable[string] `json:"description" swaggertype:"string"`
+	FinishedAt  domain.Nullable[string] `json:"finished_at" swaggertype:"string"`
+	ManagerID   domain.Nullable[string] `json:"manager_id" swaggertype:"string"`
+}
+
+func (p *PatchRequest) Validate() []domain.ErrorResponse {
+	var errs []domain.ErrorResponse
+
+	if p.Title.Cleared() || p.Title.Empty() {
+		errs = append(errs, domain.ErrorResponse{Message: "title can not be null or empty", Field: "title"})
+	}
+
+	if p.FinishedAt.Empty() {
+		errs = append(errs, domain.ErrorResponse{Message: "finished_at can not be empty, null clears it", Field: "finished_at"})
+	}
+
+	if p.ManagerID.Empty() {
+		errs = append(errs, domain.ErrorResponse{Message: "manager_id can not be empty, null clears it", Field: "manager_id"})
+	}
+
+	update := p.Update()
+
+	return append(errs, update.Validate()...)
+}
+
+// Update returns the values the patch sets.
+func (p *PatchRequest) Update() UpdateRequest {
+	return UpdateRequest{
+		Title:       p.Title.Value,
+		Description: p.Description.Value,
+		FinishedAt:  p.FinishedAt.Value,
+		ManagerID:   p.ManagerID.Value,
+	}
+}
+
+// Clear returns the fields the patch empties. An empty description is stored
+// the same way as a cleared one.
+func (p *PatchRequest) Clear() []Field {
+	var fields []Field
+
+	if p.Description.Cleared() || p.Description.Empty() {
+		fields = append(fields, FieldDescription)
+	}
+	if p.FinishedAt.Cleared() {
+		fields = append(fields, FieldFinishedAt)
+	}
+	if p.ManagerID.Cleared() {
+		fields = append(fields, FieldManagerID)
+	}
+
+	return fields
+}
+
 type Response struct {
 	ID          string `json:"id"`
 	Title       string `json:"title"`
 	Description string `json:"description"`
-	FinishedAt  string `json:"finished_at"`
+	FinishedAt  string `json:"finished_at,omitempty"`
 	StartedAt   string `json:"started_at"`
 	ManagerID   string `json:"manager_id"`
+	DeletedAt   string `json:"deleted_at,omitempty"`
+	Version     int64  `json:"version"`
 }
 
 func ParseFromEntity(p Entity) Response {
@@ -77,6 +139,8 @@ func ParseFromEntity(p Entity) Response {
 		FinishedAt:  p.FinishedAt.String(),
 		StartedAt:   p.StartedAt.String(),
 		ManagerID:   p.ManagerID,
+		DeletedAt:   domain.FormatTime(p.DeletedAt),
+		Version:     p.Version,
 	}
 }
 
@@ -87,3 +151,44 @@ func ParseFromEntities(projects []Entity) []Response {
 	}
 	return responses
 }
+
+type MemberRequest struct {
+	UserID string `json:"user_id"`
+	Role   string `json:"role"`
+}
+
+func (m *MemberRequest) Validate() []domain.ErrorResponse {
+	var errs []domain.ErrorResponse
+
+	if m.UserID == "" {
+		errs = append(errs, domain.ErrorResponse{Message: "user_id is required", Field: "user_id"})
+	}
+
+	if !IsValidMemberRole(m.Role) {
+		errs = append(errs, domain.ErrorResponse{Message: "invalid role", Field: "role"})
+	}
+
+	return errs
+}
+
+type MemberResponse struct {
+	UserID   string `json:"user_id"`
+	Role     string `json:"role"`
+	JoinedAt string `json:"joined_at"`
+}
+
+func ParseFromMember(m Member) MemberResponse {
+	return MemberResponse{
+		UserID:   m.UserID,
+		Role:     m.Role,
+		JoinedAt: m.JoinedAt.Format(time.RFC3339),
+	}
+}
+
+func ParseFromMembers(members []Member) []MemberResponse {
+	responses := []MemberResponse{}
+	for _, m := range members {
+		responses = append(responses, ParseFromMember(m))
+	}
+	return responses
+}
@@ -1,6 +1,9 @@
 package project
 
-import "project-management/internal/domain"
+import (
+	"project-management/internal/domain"
+	"time"
+)
 
 type Entity struct {
 	ID          string
@@ -9,12 +12,54 @@ type Entity struct {
 	StartedAt   domain.OnlyDate `db:"started_at"`
 	FinishedAt  domain.OnlyDate `db:"finished_at"`
 	ManagerID   string          `db:"manager_id"`
+	DeletedAt   *time.Time      `db:"deleted_at"`
+	// Version is bumped by every write, an update carrying a version only
+	// applies to that version of the project
+	Version int64 `db:"version"`
+}
+
+// Field names a project field that a patch clears, the others can not be
+// empty.
+type Field string
+
+const (
+	FieldDescription Field = "description"
+	FieldFinishedAt  Field = "finished_at"
+	FieldManagerID   Field = "manager_id"
+)
+
+const (
+	MemberRoleManager   = "manager"
+	MemberRoleDeveloper = "developer"
+	MemberRoleViewer    = "viewer"
+)
+
+// Member is a user working on a project. Viewers may only read, the other
+// roles may also change the tasks of the project.
+type Member struct {
+	ProjectID string    `db:"project_id"`
+	UserID    string    `db:"user_id"`
+	Role      string    `db:"role"`
+	JoinedAt  time.Time `db:"joined_at"`
+}
+
+func (m Member) CanEditTasks() bool {
+	return m.Role == MemberRoleManager || m.Role == MemberRoleDeveloper
+}
+
+func IsValidMemberRole(role string) bool {
+	return role == MemberRoleManager || role == MemberRoleDeveloper || role == MemberRoleViewer
 }
 
 var (
 	ErrExists   = &ProjectError{"project already exists"}
 	ErrNotFound = &ProjectError{"project not found"}
 	ErrSearch   = &ProjectError{"project search error"}
+
+	ErrMemberExists   = &ProjectError{"user is already a member of the project"}
+	ErrMemberNotFound = &ProjectError{"project member not found"}
+	ErrNotMember      = &ProjectError{"user is not a member of the project"}
+	ErrRemoveManager  = &ProjectError{"the project manager can not be removed from the project"}
 )
 
 func IsValidFilter(filter string) bool {
@@ -1,12 +1,45 @@
 package project
 
-import "context"
+import (
+	"context"
+	"project-management/internal/domain"
+	"time"
+)
 
 type Repository interface {
 	Create(context.Context, Entity) (string, error)
-	Search(ctx context.Context, filter, value string) ([]Entity, error)
-	List(ctx context.Context) ([]Entity, error)
+	Search(ctx context.Context, filter, value string, page domain.PageRequest) (domain.Page[Entity], error)
+	List(ctx context.Context, page domain.PageRequest) (domain.Page[Entity], error)
 	Get(ctx context.Context, id string) (Entity, error)
+	// ManagedBy returns the live projects the user manages and locks them
+	// until the unit of work ctx runs in ends
+	ManagedBy(ctx context.Context, userID string) ([]Entity, error)
+	// Lock and LockShared hold the live project until the unit of work ctx
+	// runs in ends. Lock waits for and keeps out every other lock on the
+	// project, shared locks do not wait for one another.
+	Lock(ctx context.Context, id string) error
+	LockShared(ctx context.Context, id string) error
+	// Update and Delete only apply to the given version of the project, they
+	// return domain.ErrVersionConflict once it has moved on. The version of
+	// the update is that of p, zero skips the check.
 	Update(ctx context.Context, id string, p Entity) error
-	Delete(ctx context.Context, id string) error
+	// Patch works like Update and also empties the fields in clear
+	Patch(ctx context.Context, id string, p Entity, clear []Field) error
+	// Delete moves the row to the trash, Restore takes it out again and Purge
+	// removes rows that were deleted before the given time for good. The tasks
+	// of a project follow it into the trash and out again.
+	Delete(ctx context.Context, id string, version int64) error
+	Restore(ctx context.Context, id string) error
+	Purge(ctx context.Context, before time.Time) (int64, error)
+	FullTextSearch(ctx context.Context, query string, limit int) ([]domain.Ranked[Entity], error)
+}
+
+type MemberRepository interface {
+	AddMember(ctx context.Context, m Member) error
+	// SetMember adds the member or gives an existing one the role of m, the
+	// member keeps the date it joined at
+	SetMember(ctx context.Context, m Member) error
+	GetMember(ctx context.Context, projectID, userID string) (Member, error)
+	ListMembers(ctx context.Context, projectID string) ([]Member, error)
+	RemoveMember(ctx context.Context, projectID, userID string) error
 }
@@ -0,0 +1,80 @@
+package search
+
+import (
+	"project-management/internal/domain"
+)
+
+const (
+	TypeTask    = "task"
+	TypeProject = "project"
+	TypeUser    = "user"
+
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+type Request struct {
+	Query string
+	Types []string
+	Limit int
+}
+
+func (r *Request) Validate() []domain.ErrorResponse {
+	var errs []domain.ErrorResponse
+
+	if r.Query == "" {
+		errs = append(errs, domain.ErrorResponse{Message: "q is required", Field: "q"})
+	}
+
+	if len(r.Query) > 200 {
+		errs = append(errs, domain.ErrorResponse{Message: "q must be less than 200 characters", Field: "q"})
+	}
+
+	for _, t := range r.Types {
+		if t != TypeTask && t != TypeProject && t != TypeUser {
+			errs = append(errs, domain.ErrorResponse{Message: "invalid type", Field: "type"})
+			break
+		}
+	}
+
+	if len(r.Types) == 0 {
+		r.Types = []string{TypeTask, TypeProject, TypeUser}
+	}
+
+	if r.Limit == 0 {
+		r.Limit = DefaultLimit
+	}
+
+	if r.Limit < 0 || r.Limit > MaxLimit {
+		errs = append(errs, domain.ErrorResponse{Message: "limit must be between 1 and 100", Field: "limit"})
+	}
+
+	return errs
+}
+
+func (r *Request) Includes(t string) bool {
+	for _, v := range r.Types {
+		if v == t {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Result is a single hit, Title and Description hold the task or project title
+// and description, or the user name and email.
+type Result struct {
+	Type        string  `json:"type"`
+	ID          string  `json:"id"`
+	Title       string  `json:"title"`
+	Description string  `json:"description"`
+	Rank        float64 `json:"rank"`
+}
+
+type Response struct {
+	Query   string         `json:"query"`
+	Total   int            `json:"total"`
+	Counts  map[string]int `json:"counts"`
+	Results []Result       `json:"results"`
+}
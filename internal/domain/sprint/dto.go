@@ -0,0 +1,128 @@
+package sprint
+
+import (
+	"project-management/internal/domain"
+	"strings"
+	"time"
+)
+
+type Request struct {
+	Name    string `json:"name"`
+	Goal    string `json:"goal"`
+	StartAt string `json:"start_at"`
+	EndAt   string `json:"end_at"`
+}
+
+func (r *Request) Validate() []domain.ErrorResponse {
+	var errs []domain.ErrorResponse
+
+	if strings.TrimSpace(r.Name) == "" {
+		errs = append(errs, domain.ErrorResponse{Message: "name is required", Field: "name"})
+	}
+
+	if len(r.Name) > 100 {
+		errs = append(errs, domain.ErrorResponse{Message: "name must be at most 100 characters", Field: "name"})
+	}
+
+	if len(r.Goal) > 500 {
+		errs = append(errs, domain.ErrorResponse{Message: "goal must be at most 500 characters", Field: "goal"})
+	}
+
+	start, err := time.Parse(domain.DateLayout, r.StartAt)
+	if err != nil {
+		errs = append(errs, domain.ErrorResponse{Message: "invalid start_at format", Field: "start_at"})
+	}
+
+	end, err := time.Parse(domain.DateLayout, r.EndAt)
+	if err != nil {
+		errs = append(errs, domain.ErrorResponse{Message: "invalid end_at format", Field: "end_at"})
+	}
+
+	if !start.IsZero() && !end.IsZero() && end.Before(start) {
+		errs = append(errs, domain.ErrorResponse{Message: "end_at must not be before start_at", Field: "end_at"})
+	}
+
+	return errs
+}
+
+type UpdateRequest struct {
+	Name    string `json:"name,omitempty"`
+	Goal    string `json:"goal,omitempty"`
+	StartAt string `json:"start_at,omitempty"`
+	EndAt   string `json:"end_at,omitempty"`
+}
+
+func (r *UpdateRequest) Validate() []domain.ErrorResponse {
+	var errs []domain.ErrorResponse
+
+	if r.Name != "" && strings.TrimSpace(r.Name) == "" {
+		errs = append(errs, domain.ErrorResponse{Message: "name must not be blank", Field: "name"})
+	}
+
+	if len(r.Name) > 100 {
+		errs = append(errs, domain.ErrorResponse{Message: "name must be at most 100 characters", Field: "name"})
+	}
+
+	if len(r.Goal) > 500 {
+		errs = append(errs, domain.ErrorResponse{Message: "goal must be at most 500 characters", Field: "goal"})
+	}
+
+	if _, err := time.Parse(domain.DateLayout, r.StartAt); r.StartAt != "" && err != nil {
+		errs = append(errs, domain.ErrorResponse{Message: "invalid start_at format", Field: "start_at"})
+	}
+
+	if _, err := time.Parse(domain.DateLayout, r.EndAt); r.EndAt != "" && err != nil {
+		errs = append(errs, domain.ErrorResponse{Message: "invalid end_at format", Field: "end_at"})
+	}
+
+	return errs
+}
+
+// CloseRequest names the sprint taking over the unfinished tasks, they go
+// back to the backlog when NextSprintID is empty.
+type CloseRequest struct {
+	NextSprintID string `json:"next_sprint_id"`
+}
+
+// ScheduleRequest puts a task into a sprint, an empty SprintID moves it back
+// to the backlog.
+type ScheduleRequest struct {
+	SprintID string `json:"sprint_id"`
+}
+
+type Response struct {
+	ID        string `json:"id"`
+	ProjectID string `json:"project_id"`
+	Name      string `json:"name"`
+	Goal      string `json:"goal"`
+	StartAt   string `json:"start_at"`
+	EndAt     string `json:"end_at"`
+	State     string `json:"state"`
+}
+
+// CloseResponse lists the unfinished tasks moved out of the closed sprint.
+type CloseResponse struct {
+	NextSprintID string   `json:"next_sprint_id"`
+	MovedTaskIDs []string `json:"moved_task_ids"`
+}
+
+func ParseFromEntity(data Entity) Response {
+	return Response{
+		ID:        data.ID,
+		ProjectID: data.ProjectID,
+		Name:      data.Name,
+		Goal:      data.Goal,
+		StartAt:   data.StartAt.String(),
+		EndAt:     data.EndAt.String(),
+		State:     data.State,
+	}
+}
+
+func ParseFromEntities(data []Entity) []Response {
+	res := make([]Response, len(data))
+	for i, s := range data {
+		res[i] = ParseFromEntity(s)
+	}
+
+	return res
+}
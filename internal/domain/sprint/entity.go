@@ -0,0 +1,46 @@
+package sprint
+
+import (
+	"project-management/internal/domain"
+)
+
+// Entity is an iteration of a project. A sprint is planned, then active and
+// finally closed, a project has at most one active sprint at a time.
+type Entity struct {
+	ID        string
+	ProjectID string          `db:"project_id"`
+	Name      string          `db:"name"`
+	Goal      string          `db:"goal"`
+	StartAt   domain.OnlyDate `db:"start_at"`
+	EndAt     domain.OnlyDate `db:"end_at"`
+	State     string          `db:"state"`
+}
+
+const (
+	StatePlanned = "planned"
+	StateActive  = "active"
+	StateClosed  = "closed"
+)
+
+var (
+	ErrNotFound      = &SprintError{"sprint not found"}
+	ErrOtherProject  = &SprintError{"sprint belongs to another project"}
+	ErrActiveExists  = &SprintError{"the project already has an active sprint"}
+	ErrNotPlanned    = &SprintError{"only a planned sprint can be started"}
+	ErrNotActive     = &SprintError{"only an active sprint can be closed"}
+	ErrClosed        = &SprintError{"sprint is closed"}
+	ErrInvalidDates  = &SprintError{"end_at must not be before start_at"}
+	ErrInvalidTarget = &SprintError{"unfinished tasks can only move to another open sprint of the project"}
+)
+
+type SprintError struct {
+	message string
+}
+
+func (e *SprintError) Error() string {
+	return e.message
+}
+
+func (e *SprintError) Is(err error) bool {
+	return e == err
+}
@@ -0,0 +1,28 @@
+package sprint
+
+import (
+	"context"
+	"project-management/internal/domain/task"
+)
+
+type Repository interface {
+	Create(ctx context.Context, data Entity) (id string, err error)
+	Get(ctx context.Context, id string) (Entity, error)
+	// List returns the sprints of the project ordered by start date
+	List(ctx context.Context, projectID string) ([]Entity, error)
+	// Update changes the non empty fields of data, starting a sprint while
+	// another one of the project is active fails with ErrActiveExists
+	Update(ctx context.Context, id string, data Entity) error
+	// Delete removes the sprint, its tasks go back to the backlog
+	Delete(ctx context.Context, id string) error
+	// Lock holds the sprint until the unit of work ctx runs in ends, tasks
+	// can only join it once the unit of work is done
+	Lock(ctx context.Context, id string) error
+
+	// Schedule puts the task into the sprint, an empty sprintID moves it to
+	// the backlog. The events are stored in the same transaction.
+	Schedule(ctx context.Context, taskID, sprintID string, events ...task.Event) error
+	// Close closes the sprint and moves the tasks to the sprint nextID, or to
+	// the backlog when it is empty, in one transaction.
+	Close(ctx context.Context, id, nextID string, taskIDs []string, events ...task.Event) error
+}
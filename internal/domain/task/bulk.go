@@ -0,0 +1,24 @@
+package task
+
+// Op is the kind of a bulk operation.
+type Op string
+
+const (
+	OpCreate Op = "create"
+	OpUpdate Op = "update"
+	OpDelete Op = "delete"
+)
+
+// MaxBulkOperations bounds the operations of a single bulk request.
+const MaxBulkOperations = 100
+
+// Write is one checked operation of a bulk request, ready to be stored. A
+// create stores Entity, an update patches the task ID with Entity and Clear
+// like Patch and a delete removes it like Delete with the version of Entity.
+type Write struct {
+	Op     Op
+	ID     string
+	Entity Entity
+	Clear  []Field
+	Events []Event
+}
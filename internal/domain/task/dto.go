@@ -1,6 +1,7 @@
 package task
 
 import (
+	"math"
 	"project-management/internal/domain"
 	"time"
 )
@@ -11,9 +12,15 @@ type Request struct {
 	Priority    string `json:"priority"`
 	Status      string `json:"status"`
 	AuthorID    string `json:"author_id"`
+	AssigneeID  string `json:"assignee_id"`
 	ProjectID   string `json:"project_id"`
-	CreatedAt   string `json:"created_at"`
-	DoneAt      string `json:"done_at"`
+	ParentID    string `json:"parent_id"`
+	SprintID    string `json:"sprint_id"`
+	MilestoneID string `json:"milestone_id"`
+	// CreatedAt defaults to the current date
+	CreatedAt     string   `json:"created_at"`
+	DueDate       string   `json:"due_date"`
+	EstimateHours *float64 `json:"estimate_hours" swaggertype:"number"`
 }
 
 type UpdateRequest struct {
@@ -23,7 +30,14 @@ type UpdateRequest struct {
 	Status      string `json:"status,omitempty"`
 	AuthorID    string `json:"author_id,omitempty"`
 	ProjectID   string `json:"project_id,omitempty"`
-	DoneAt      string `json:"done_at,omitempty"`
+	ParentID    string `json:"parent_id,omitempty"`
+	DueDate     string `json:"due_date,omitempty"`
+
+	EstimateHours *float64 `json:"estimate_hours,omitempty" swaggertype:"number"`
+
+	// AssigneeID is only set by merge patches, PUT hands tasks over through
+	// the assign endpoint.
+	AssigneeID string `json:"-"`
 }
 
 func (t *Request) Validate() []domain.ErrorResponse {
@@ -43,19 +57,23 @@ func (t *Request) Validate() []domain.ErrorResponse {
 		errs = append(errs, domain.ErrorResponse{Message: "description must be less than 200 characters", Field: "description"})
 	}
 
-	if _, err := time.Parse(domain.DateLayout, t.CreatedAt); err != nil {
+	if _, err := time.Parse(domain.DateLayout, t.CreatedAt); t.CreatedAt != "" && err != nil {
 		errs = append(errs, domain.ErrorResponse{Message: "invalid created_at format", Field: "created_at"})
 	}
 
-	if _, err := time.Parse(domain.DateLayout, t.DoneAt); err != nil {
-		errs = append(errs, domain.ErrorResponse{Message: "invalid done_at format", Field: "done_at"})
+	if _, err := time.Parse(domain.DateLayout, t.DueDate); t.DueDate != "" && err != nil {
+		errs = append(errs, domain.ErrorResponse{Message: "invalid due_date format", Field: "due_date"})
+	}
+
+	if !isValidEstimate(t.EstimateHours) {
+		errs = append(errs, domain.ErrorResponse{Message: "estimate_hours must be between 0 and 10000", Field: "estimate_hours"})
 	}
 
 	if !isValidPriority(t.Priority) {
 		errs = append(errs, domain.ErrorResponse{Message: "invalid priority value", Field: "priority"})
 	}
 
-	if !isValidStatus(t.Status) {
+	if t.Status != "" && !statusName.MatchString(t.Status) {
 		errs = append(errs, domain.ErrorResponse{Message: "invalid status value", Field: "status"})
 	}
 
@@ -71,13 +89,8 @@ func isValidPriority(priority string) bool {
 	return allowedPriorities[priority]
 }
 
-func isValidStatus(status string) bool {
-	allowedStatuses := map[string]bool{
-		"active":      true,
-		"in_progress": true,
-		"done":        true,
-	}
-	return allowedStatuses[status]
+func isValidEstimate(hours *float64) bool {
+	return hours == nil || *hours >= 0 && *hours <= MaxEstimateHours
 }
 
 func (t *UpdateRequest) Validate() []domain.ErrorResponse {
@@ -91,21 +104,201 @@ func (t *UpdateRequest) Validate() []domain.ErrorResponse {
 		errs = append(errs, domain.ErrorResponse{Message: "description must be less than 200 characters", Field: "description"})
 	}
 
-	if _, err := time.Parse(domain.DateLayout, t.DoneAt); t.DoneAt != "" && err != nil {
-		errs = append(errs, domain.ErrorResponse{Message: "invalid done_at format", Field: "done_at"})
+	if _, err := time.Parse(domain.DateLayout, t.DueDate); t.DueDate != "" && err != nil {
+		errs = append(errs, domain.ErrorResponse{Message: "invalid due_date format", Field: "due_date"})
+	}
+
+	if !isValidEstimate(t.EstimateHours) {
+		errs = append(errs, domain.ErrorResponse{Message: "estimate_hours must be between 0 and 10000", Field: "estimate_hours"})
 	}
 
 	if t.Priority != "" && !isValidPriority(t.Priority) {
 		errs = append(errs, domain.ErrorResponse{Message: "invalid priority value", Field: "priority"})
 	}
 
-	if t.Status != "" && !isValidStatus(t.Status) {
+	if t.Status != "" && !statusName.MatchString(t.Status) {
 		errs = append(errs, domain.ErrorResponse{Message: "invalid status value", Field: "status"})
 	}
 
 	return errs
 }
 
+// PatchRequest is a JSON Merge Patch (RFC 7396) of a task. Absent fields stay
+// as they are, null clears the description, assignee, parent, due date and
+// estimate. The other fields can not be cleared and only the description can
+// be empty.
+type PatchRequest struct {
+	Title       domain.Nullable[string] `json:"title" swaggertype:"string"`
+	Description domain.Nullable[string] `json:"description" swaggertype:"string"`
+	Priority    domain.Nullable[string] `json:"priority" swaggertype:"string"`
+	Status      domain.Nullable[string] `json:"status" swaggertype:"string"`
+	AuthorID    domain.Nullable[string] `json:"author_id" swaggertype:"string"`
+	AssigneeID  domain.Nullable[string] `json:"assignee_id" swaggertype:"string"`
+	ProjectID   domain.Nullable[string] `json:"project_id" swaggertype:"string"`
+	ParentID    domain.Nullable[string] `json:"parent_id" swaggertype:"string"`
+	DueDate     domain.Nullable[string] `json:"due_date" swaggertype:"string"`
+
+	EstimateHours domain.Nullable[float64] `json:"estimate_hours" swaggertype:"number"`
+}
+
+func (t *PatchRequest) Validate() []domain.ErrorResponse {
+	var errs []domain.ErrorResponse
+
+	required := []struct {
+		field   Field
+		cleared bool
+	}{
+		{FieldTitle, t.Title.Cleared() || t.Title.Empty()},
+		{FieldPriority, t.Priority.Cleared() || t.Priority.Empty()},
+		{FieldStatus, t.Status.Cleared() || t.Status.Empty()},
+		{FieldAuthorID, t.AuthorID.Cleared() || t.AuthorID.Empty()},
+		{FieldProjectID, t.ProjectID.Cleared() || t.ProjectID.Empty()},
+	}
+	for _, r := range required {
+		if r.cleared {
+			errs = append(errs, domain.ErrorResponse{Message: string(r.field) + " can not be null or empty", Field: string(r.field)})
+		}
+	}
+
+	optional := []struct {
+		field Field
+		empty bool
+	}{
+		{FieldAssigneeID, t.AssigneeID.Empty()},
+		{FieldParentID, t.ParentID.Empty()},
+		{FieldDueDate, t.DueDate.Empty()},
+	}
+	for _, o := range optional {
+		if o.empty {
+			errs = append(errs, domain.ErrorResponse{Message: string(o.field) + " can not be empty, null clears it", Field: string(o.field)})
+		}
+	}
+
+	update := t.Update()
+
+	return append(errs, update.Validate()...)
+}
+
+// Update returns the values the patch sets.
+func (t *PatchRequest) Update() UpdateRequest {
+	return UpdateRequest{
+		Title:       t.Title.Value,
+		Description: t.Description.Value,
+		Priority:    t.Priority.Value,
+		Status:      t.Status.Value,
+		AuthorID:    t.AuthorID.Value,
+		ProjectID:   t.ProjectID.Value,
+		ParentID:    t.ParentID.Value,
+		DueDate:     t.DueDate.Value,
+
+		EstimateHours: t.EstimateHours.Ptr(),
+		AssigneeID:    t.AssigneeID.Value,
+	}
+}
+
+// Clear returns the fields the patch empties. An empty description is stored
+// the same way as a cleared one.
+func (t *PatchRequest) Clear() []Field {
+	var fields []Field
+
+	clearable := []struct {
+		field   Field
+		cleared bool
+	}{
+		{FieldDescription, t.Description.Cleared() || t.Description.Empty()},
+		{FieldAssigneeID, t.AssigneeID.Cleared()},
+		{FieldParentID, t.ParentID.Cleared()},
+		{FieldDueDate, t.DueDate.Cleared()},
+		{FieldEstimateHours, t.EstimateHours.Cleared()},
+	}
+	for _, c := range clearable {
+		if c.cleared {
+			fields = append(fields, c.field)
+		}
+	}
+
+	return fields
+}
+
+const (
+	BulkAllOrNothing = "all_or_nothing"
+	BulkPerItem      = "per_item"
+)
+
+// BulkRequest runs several operations in one transaction. In the
+// all_or_nothing mode, the default, a failing operation cancels the others,
+// per_item keeps the operations that succeed.
+type BulkRequest struct {
+	Mode       string          `json:"mode,omitempty" enums:"all_or_nothing,per_item"`
+	Operations []BulkOperation `json:"operations"`
+}
+
+func (b *BulkRequest) Validate() []domain.ErrorResponse {
+	var errs []domain.ErrorResponse
+
+	if b.Mode != "" && b.Mode != BulkAllOrNothing && b.Mode != BulkPerItem {
+		errs = append(errs, domain.ErrorResponse{Message: "mode must be all_or_nothing or per_item", Field: "mode"})
+	}
+
+	if len(b.Operations) == 0 || len(b.Operations) > MaxBulkOperations {
+		errs = append(errs, domain.ErrorResponse{Message: "operations must hold between 1 and 100 operations", Field: "operations"})
+	}
+
+	return errs
+}
+
+// BulkOperation creates the task in Create, patches the task ID with Update
+// or deletes it. Version is the version of the task an update or delete is
+// based on, zero for any.
+type BulkOperation struct {
+	Op      Op            `json:"op" enums:"create,update,delete"`
+	ID      string        `json:"id,omitempty"`
+	Version int64         `json:"version,omitempty"`
+	Create  *Request      `json:"create,omitempty"`
+	Update  *PatchRequest `json:"update,omitempty"`
+}
+
+func (o *BulkOperation) Validate() []domain.ErrorResponse {
+	switch {
+	case o.Op == OpCreate && o.Create == nil:
+		return []domain.ErrorResponse{{Message: "create is required", Field: "create"}}
+	case o.Op == OpCreate:
+		return o.Create.Validate()
+	case (o.Op == OpUpdate || o.Op == OpDelete) && o.ID == "":
+		return []domain.ErrorResponse{{Message: "id is required", Field: "id"}}
+	case o.Op == OpUpdate && o.Update == nil:
+		return []domain.ErrorResponse{{Message: "update is required", Field: "update"}}
+	case o.Op == OpUpdate:
+		return o.Update.Validate()
+	case o.Op == OpDelete:
+		return nil
+	default:
+		return []domain.ErrorResponse{{Message: "op must be create, update or delete", Field: "op"}}
+	}
+}
+
+// BulkResult is the outcome of one operation, Errors lists what is wrong with
+// an invalid one.
+type BulkResult struct {
+	Index  int                    `json:"index"`
+	Op     Op                     `json:"op"`
+	ID     string                 `json:"id,omitempty"`
+	Error  string                 `json:"error,omitempty"`
+	Errors []domain.ErrorResponse `json:"errors,omitempty"`
+}
+
+type BulkResponse struct {
+	Succeeded int          `json:"succeeded"`
+	Failed    int          `json:"failed"`
+	Results   []BulkResult `json:"results"`
+}
+
+// AssignRequest hands a task to another member of its project, an empty
+// assignee_id unassigns it.
+type AssignRequest struct {
+	AssigneeID string `json:"assignee_id"`
+}
+
 type Response struct {
 	ID          string `json:"id"`
 	Title       string `json:"title"`
@@ -113,9 +306,19 @@ type Response struct {
 	Priority    string `json:"priority"`
 	Status      string `json:"status"`
 	AuthorID    string `json:"author_id"`
+	AssigneeID  string `json:"assignee_id"`
 	ProjectID   string `json:"project_id"`
+	ParentID    string `json:"parent_id,omitempty"`
+	SprintID    string `json:"sprint_id,omitempty"`
+	MilestoneID string `json:"milestone_id,omitempty"`
 	CreatedAt   string `json:"created_at"`
-	DoneAt      string `json:"done_at"`
+	DueDate     string `json:"due_date,omitempty"`
+	// Overdue is set for open tasks past their due date
+	Overdue       bool     `json:"overdue"`
+	EstimateHours *float64 `json:"estimate_hours,omitempty" swaggertype:"number"`
+	CompletedAt   string   `json:"completed_at,omitempty"`
+	DeletedAt     string   `json:"deleted_at,omitempty"`
+	Version       int64    `json:"version"`
 }
 
 func ParseFromEntity(t Entity) Response {
@@ -126,9 +329,19 @@ func ParseFromEntity(t Entity) Response {
 		Priority:    t.Priority,
 		Status:      t.Status,
 		AuthorID:    t.AuthorID,
+		AssigneeID:  t.AssigneeID,
 		ProjectID:   t.ProjectID,
+		ParentID:    t.ParentID,
+		SprintID:    t.SprintID,
+		MilestoneID: t.MilestoneID,
 		CreatedAt:   t.CreatedAt.String(),
-		DoneAt:      t.DoneAt.String(),
+		DueDate:     t.DueDate.String(),
+		Overdue:     t.Overdue(time.Now().UTC()),
+
+		EstimateHours: t.EstimateHours,
+		CompletedAt:   domain.FormatTime(t.CompletedAt),
+		DeletedAt:     domain.FormatTime(t.DeletedAt),
+		Version:       t.Version,
 	}
 }
 
@@ -139,3 +352,94 @@ func ParseFromEntities(tasks []Entity) []Response {
 	}
 	return responses
 }
+
+type EventResponse struct {
+	Field     string `json:"field"`
+	OldValue  string `json:"old_value"`
+	NewValue  string `json:"new_value"`
+	ActorID   string `json:"actor_id"`
+	CreatedAt string `json:"created_at"`
+}
+
+func ParseFromEvent(e Event) EventResponse {
+	return EventResponse{
+		Field:     string(e.Field),
+		OldValue:  e.OldValue,
+		NewValue:  e.NewValue,
+		ActorID:   e.ActorID,
+		CreatedAt: e.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func ParseFromEvents(events []Event) []EventResponse {
+	responses := []EventResponse{}
+	for _, e := range events {
+		responses = append(responses, ParseFromEvent(e))
+	}
+	return responses
+}
+
+type DependencyRequest struct {
+	BlockerID string `json:"blocker_id"`
+}
+
+func (r *DependencyRequest) Validate() []domain.ErrorResponse {
+	var errs []domain.ErrorResponse
+
+	if r.BlockerID == "" {
+		errs = append(errs, domain.ErrorResponse{Message: "blocker_id is required", Field: "blocker_id"})
+	}
+
+	return errs
+}
+
+type DependenciesResponse struct {
+	BlockedBy []Response `json:"blocked_by"`
+	Blocks    []Response `json:"blocks"`
+}
+
+// TreeResponse is a task with its subtasks. Progress is the rolled up
+// completion in percent: 100 for done tasks, the average of the subtasks for
+// open tasks that have some and 0 for open tasks without.
+type TreeResponse struct {
+	Response
+	Progress int            `json:"progress"`
+	Subtasks []TreeResponse `json:"subtasks"`
+}
+
+// ParseTree nests descendants below root, done tells whether a task is in a
+// terminal status of its workflow.
+func ParseTree(root Entity, descendants []Entity, done func(Entity) bool) TreeResponse {
+	children := map[string][]Entity{}
+	for _, t := range descendants {
+		children[t.ParentID] = append(children[t.ParentID], t)
+	}
+
+	var build func(t Entity) (TreeResponse, float64)
+	build = func(t Entity) (TreeResponse, float64) {
+		node := TreeResponse{Response: ParseFromEntity(t), Subtasks: []TreeResponse{}}
+
+		var sum float64
+		for _, child := range children[t.ID] {
+			sub, progress := build(child)
+			node.Subtasks = append(node.Subtasks, sub)
+			sum += progress
+		}
+
+		var progress float64
+		switch {
+		case done(t):
+			progress = 100
+		case len(node.Subtasks) > 0:
+			progress = sum / float64(len(node.Subtasks))
+		}
+
+		node.Progress = int(math.Round(progress))
+
+		return node, progress
+	}
+
+	tree, _ := build(root)
+
+	return tree
+}
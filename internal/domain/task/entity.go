@@ -2,6 +2,7 @@ package task
 
 import (
 	"project-management/internal/domain"
+	"time"
 )
 
 type Entity struct {
@@ -11,24 +12,55 @@ type Entity struct {
 	Priority    string
 	Status      string
 	AuthorID    string          `db:"author_id"`
+	AssigneeID  string          `db:"assignee_id"`
 	ProjectID   string          `db:"project_id"`
+	ParentID    string          `db:"parent_id"`
+	SprintID    string          `db:"sprint_id"`
+	MilestoneID string          `db:"milestone_id"`
 	CreatedAt   domain.OnlyDate `db:"created_at"`
-	DoneAt      domain.OnlyDate `db:"done_at"`
+	DueDate     domain.OnlyDate `db:"due_date"`
+	// EstimateHours is nil when the task has no estimate
+	EstimateHours *float64 `db:"estimate_hours"`
+	// CompletedAt is set when the task enters a terminal status of its
+	// workflow and cleared when it is reopened
+	CompletedAt *time.Time `db:"completed_at"`
+	DeletedAt   *time.Time `db:"deleted_at"`
+	// Version is bumped by every write, an update carrying a version only
+	// applies to that version of the task
+	Version int64 `db:"version"`
 }
 
+// Overdue reports whether the task is still open after its due date.
+func (t Entity) Overdue(now time.Time) bool {
+	return t.DueDate != "" && t.CompletedAt == nil && string(t.DueDate) < now.Format(domain.DateLayout)
+}
+
+// MaxEstimateHours bounds the estimate of a single task.
+const MaxEstimateHours = 10000
+
 var (
 	ErrExists   = &TaskError{"task already exists"}
 	ErrNotFound = &TaskError{"task not found"}
 	ErrSearch   = &TaskError{"task search error"}
-)
 
-func IsValidFilter(filter string) bool {
-	if filter == "" && filter != "title" && filter != "priority" && filter != "status" && filter != "author_id" && filter != "project_id" {
-		return false
-	}
+	ErrProjectDeleted = &TaskError{"the project of the task is deleted, restore it first"}
 
-	return true
-}
+	ErrSelfDependency     = &TaskError{"a task can not block itself"}
+	ErrDependencyCycle    = &TaskError{"the dependency would create a cycle"}
+	ErrDependencyExists   = &TaskError{"the task is already blocked by that task"}
+	ErrDependencyNotFound = &TaskError{"the task is not blocked by that task"}
+	ErrBlocked            = &TaskError{"the task has blockers that are not done"}
+
+	ErrParentNotFound = &TaskError{"parent task not found"}
+	ErrParentCycle    = &TaskError{"a task can not be moved under itself or one of its subtasks"}
+	ErrParentProject  = &TaskError{"a subtask has to be in the project of its parent"}
+	ErrSubtasksMoved  = &TaskError{"a task with subtasks can not be moved to another project"}
+
+	ErrBulkInvalid   = &TaskError{"the operation is invalid"}
+	ErrBulkAborted   = &TaskError{"not applied, another operation of the batch failed"}
+	ErrBulkDuplicate = &TaskError{"a task can only be changed once per batch"}
+	ErrBulkMove      = &TaskError{"tasks can not be moved to another project in a batch"}
+)
 
 type TaskError struct {
 	message string
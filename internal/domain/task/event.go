@@ -0,0 +1,115 @@
+package task
+
+import (
+	"strconv"
+	"time"
+)
+
+// Event records the change of a single task field. ActorID is empty when the
+// user who made the change has been deleted since.
+type Event struct {
+	ID        int64     `db:"id"`
+	TaskID    string    `db:"task_id"`
+	Field     Field     `db:"field"`
+	OldValue  string    `db:"old_value"`
+	NewValue  string    `db:"new_value"`
+	ActorID   string    `db:"actor_id"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// Diff lists the fields an update changes. Like the repositories it treats
+// empty fields of the update as unchanged.
+func Diff(current, update Entity, actorID string, at time.Time) []Event {
+	var events []Event
+
+	add := func(field Field, before, after string) {
+		if after == "" || after == before {
+			return
+		}
+
+		events = append(events, Event{
+			TaskID:    current.ID,
+			Field:     field,
+			OldValue:  before,
+			NewValue:  after,
+			ActorID:   actorID,
+			CreatedAt: at,
+		})
+	}
+
+	add(FieldTitle, current.Title, update.Title)
+	add(FieldDescription, current.Description, update.Description)
+	add(FieldPriority, current.Priority, update.Priority)
+	add(FieldStatus, current.Status, update.Status)
+	add(FieldAuthorID, current.AuthorID, update.AuthorID)
+	add(FieldAssigneeID, current.AssigneeID, update.AssigneeID)
+	add(FieldProjectID, current.ProjectID, update.ProjectID)
+	add(FieldParentID, current.ParentID, update.ParentID)
+	add(FieldDueDate, string(current.DueDate), string(update.DueDate))
+	add(FieldEstimateHours, formatHours(current.EstimateHours), formatHours(update.EstimateHours))
+
+	return events
+}
+
+// ClearEvents records the fields a patch empties, fields that are empty
+// already are no change.
+func ClearEvents(current Entity, fields []Field, actorID string, at time.Time) []Event {
+	values := map[Field]string{
+		FieldDescription:   current.Description,
+		FieldAssigneeID:    current.AssigneeID,
+		FieldParentID:      current.ParentID,
+		FieldDueDate:       string(current.DueDate),
+		FieldEstimateHours: formatHours(current.EstimateHours),
+	}
+
+	var events []Event
+	for _, f := range fields {
+		if e, ok := change(current.ID, f, values[f], "", actorID, at); ok {
+			events = append(events, e)
+		}
+	}
+
+	return events
+}
+
+// AssignEvent records a change of the assignee, unlike Diff an empty
+// assignee is a change as well.
+func AssignEvent(current Entity, assigneeID, actorID string, at time.Time) (Event, bool) {
+	return change(current.ID, FieldAssigneeID, current.AssigneeID, assigneeID, actorID, at)
+}
+
+// ScheduleEvent records a move of the task to another sprint, an empty
+// sprint is the backlog.
+func ScheduleEvent(current Entity, sprintID, actorID string, at time.Time) (Event, bool) {
+	return change(current.ID, FieldSprintID, current.SprintID, sprintID, actorID, at)
+}
+
+// MilestoneEvent records a change of the milestone, an empty milestone
+// unlinks the task.
+func MilestoneEvent(current Entity, milestoneID, actorID string, at time.Time) (Event, bool) {
+	return change(current.ID, FieldMilestoneID, current.MilestoneID, milestoneID, actorID, at)
+}
+
+// formatHours renders an estimate for the history, empty when there is none.
+func formatHours(hours *float64) string {
+	if hours == nil {
+		return ""
+	}
+
+	return strconv.FormatFloat(*hours, 'f', -1, 64)
+}
+
+func change(taskID string, field Field, before, after, actorID string, at time.Time) (Event, bool) {
+	if before == after {
+		return Event{}, false
+	}
+
+	return Event{
+		TaskID:    taskID,
+		Field:     field,
+		OldValue:  before,
+		NewValue:  after,
+		ActorID:   actorID,
+		CreatedAt: at,
+	}, true
+}
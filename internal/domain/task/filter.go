@@ -0,0 +1,205 @@
+package task
+
+import (
+	"fmt"
+	"net/url"
+	"project-management/internal/domain"
+	"sort"
+	"strings"
+	"time"
+)
+
+type Field string
+
+const (
+	FieldTitle       Field = "title"
+	FieldDescription Field = "description"
+	FieldPriority    Field = "priority"
+	FieldStatus      Field = "status"
+	FieldAuthorID    Field = "author_id"
+	FieldAssigneeID  Field = "assignee_id"
+	FieldProjectID   Field = "project_id"
+	FieldParentID    Field = "parent_id"
+	FieldSprintID    Field = "sprint_id"
+	FieldMilestoneID Field = "milestone_id"
+	FieldCreatedAt   Field = "created_at"
+	FieldDueDate     Field = "due_date"
+	FieldCompletedAt Field = "completed_at"
+
+	// FieldLabel matches the names of the labels put on the task
+	FieldLabel Field = "label"
+	// FieldOverdue matches open tasks past their due date for "true" and
+	// every other task for "false"
+	FieldOverdue Field = "overdue"
+
+	// FieldEstimateHours is recorded in the task history but not filterable
+	FieldEstimateHours Field = "estimate_hours"
+)
+
+type Operator string
+
+const (
+	OpEq       Operator = "eq"
+	OpIn       Operator = "in"
+	OpGte      Operator = "gte"
+	OpLte      Operator = "lte"
+	OpContains Operator = "contains"
+
+	// OpAll matches when every value is present, it only makes sense for
+	// fields holding several values such as labels
+	OpAll Operator = "all"
+)
+
+// fieldOperators lists the operators every filterable field accepts.
+var fieldOperators = map[Field][]Operator{
+	FieldTitle:       {OpEq, OpContains},
+	FieldDescription: {OpEq, OpContains},
+	FieldPriority:    {OpEq, OpIn},
+	FieldStatus:      {OpEq, OpIn},
+	FieldAuthorID:    {OpEq, OpIn},
+	FieldAssigneeID:  {OpEq, OpIn},
+	FieldProjectID:   {OpEq, OpIn},
+	FieldParentID:    {OpEq, OpIn},
+	FieldSprintID:    {OpEq, OpIn},
+	FieldMilestoneID: {OpEq, OpIn},
+	FieldCreatedAt:   {OpEq, OpGte, OpLte},
+	FieldDueDate:     {OpEq, OpGte, OpLte},
+	FieldCompletedAt: {OpEq, OpGte, OpLte},
+	FieldLabel:       {OpEq, OpIn, OpAll},
+	FieldOverdue:     {OpEq},
+}
+
+// Condition restricts Field with Operator. Every operator but OpIn and OpAll
+// takes exactly one value.
+type Condition struct {
+	Field    Field
+	Operator Operator
+	Values   []string
+}
+
+func Equals(field Field, value string) Condition {
+	return Condition{Field: field, Operator: OpEq, Values: []string{value}}
+}
+
+// Filter is a conjunction of conditions.
+type Filter struct {
+	Conditions []Condition
+}
+
+func (f Filter) IsEmpty() bool {
+	return len(f.Conditions) == 0
+}
+
+func (f Filter) With(c ...Condition) Filter {
+	return Filter{Conditions: append(append([]Condition{}, f.Conditions...), c...)}
+}
+
+// ParseFilter reads conditions from query parameters, the following forms are
+// understood:
+//
+//	status=done                  equality
+//	status=active&status=done    any of the values
+//	status[in]=active,done       any of the values
+//	created_at[gte]=2024-01-01   also written as created_at>=2024-01-01
+//	created_at[lte]=2024-01-31   also written as created_at<=2024-01-31
+//	title[contains]=login        case insensitive substring
+//	label[all]=bug,backend       all of the values
+//
+// Keys in skip are ignored, so pagination parameters can share the query.
+func ParseFilter(q url.Values, skip map[string]bool) (f Filter, errs []domain.ErrorResponse) {
+	keys := make([]string, 0, len(q))
+	for k := range q {
+		if !skip[k] {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		field, op := parseKey(k)
+
+		values := q[k]
+		switch {
+		case op == OpIn, op == OpAll:
+			values = strings.Split(values[0], ",")
+		case op == "" && len(values) > 1:
+			op = OpIn
+		case op == "":
+			op = OpEq
+		}
+
+		c := Condition{Field: field, Operator: op, Values: values}
+		if err := c.validate(); err != nil {
+			errs = append(errs, *err)
+			continue
+		}
+
+		f.Conditions = append(f.Conditions, c)
+	}
+
+	return
+}
+
+func parseKey(k string) (Field, Operator) {
+	if name, op, ok := strings.Cut(k, "["); ok && strings.HasSuffix(op, "]") {
+		return Field(name), Operator(strings.TrimSuffix(op, "]"))
+	}
+
+	// "created_at>=2024-01-01" reaches us as key "created_at>" and value "2024-01-01"
+	if name, ok := strings.CutSuffix(k, ">"); ok {
+		return Field(name), OpGte
+	}
+	if name, ok := strings.CutSuffix(k, "<"); ok {
+		return Field(name), OpLte
+	}
+
+	return Field(k), ""
+}
+
+func (c Condition) validate() *domain.ErrorResponse {
+	field := string(c.Field)
+
+	ops, ok := fieldOperators[c.Field]
+	if !ok {
+		return &domain.ErrorResponse{Message: "unknown filter", Field: field}
+	}
+
+	allowed := false
+	for _, op := range ops {
+		allowed = allowed || op == c.Operator
+	}
+	if !allowed {
+		return &domain.ErrorResponse{Message: fmt.Sprintf("operator %q is not supported", c.Operator), Field: field}
+	}
+
+	if len(c.Values) == 0 || (c.Operator != OpIn && c.Operator != OpAll && len(c.Values) > 1) {
+		return &domain.ErrorResponse{Message: "invalid number of values", Field: field}
+	}
+
+	for _, v := range c.Values {
+		if v == "" {
+			return &domain.ErrorResponse{Message: "value is required", Field: field}
+		}
+
+		switch c.Field {
+		case FieldCreatedAt, FieldDueDate, FieldCompletedAt:
+			if _, err := time.Parse(domain.DateLayout, v); err != nil {
+				return &domain.ErrorResponse{Message: "invalid date format", Field: field}
+			}
+		case FieldOverdue:
+			if v != "true" && v != "false" {
+				return &domain.ErrorResponse{Message: "overdue must be true or false", Field: field}
+			}
+		case FieldPriority:
+			if !isValidPriority(v) {
+				return &domain.ErrorResponse{Message: "invalid priority value", Field: field}
+			}
+		case FieldStatus:
+			if !statusName.MatchString(v) {
+				return &domain.ErrorResponse{Message: "invalid status value", Field: field}
+			}
+		}
+	}
+
+	return nil
+}
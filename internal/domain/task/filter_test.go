@@ -0,0 +1,131 @@
+package task
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+
+	"project-management/internal/domain"
+)
+
+func TestParseFilter(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  []Condition
+		errs  []domain.ErrorResponse
+	}{
+		{
+			name:  "equality",
+			query: "status=done",
+			want:  []Condition{{FieldStatus, OpEq, []string{"done"}}},
+		},
+		{
+			name:  "repeated key is any of the values",
+			query: "status=active&status=done",
+			want:  []Condition{{FieldStatus, OpIn, []string{"active", "done"}}},
+		},
+		{
+			name:  "in splits the values",
+			query: "priority[in]=low,high",
+			want:  []Condition{{FieldPriority, OpIn, []string{"low", "high"}}},
+		},
+		{
+			name:  "all splits the values",
+			query: "label[all]=bug,backend",
+			want:  []Condition{{FieldLabel, OpAll, []string{"bug", "backend"}}},
+		},
+		{
+			name:  "bracket comparisons",
+			query: "created_at[gte]=2024-01-01&due_date[lte]=2024-01-31",
+			want: []Condition{
+				{FieldCreatedAt, OpGte, []string{"2024-01-01"}},
+				{FieldDueDate, OpLte, []string{"2024-01-31"}},
+			},
+		},
+		{
+			name:  "comparison signs",
+			query: "created_at>=2024-01-01&completed_at<=2024-01-31",
+			want: []Condition{
+				{FieldCompletedAt, OpLte, []string{"2024-01-31"}},
+				{FieldCreatedAt, OpGte, []string{"2024-01-01"}},
+			},
+		},
+		{
+			name:  "contains",
+			query: "title[contains]=login",
+			want:  []Condition{{FieldTitle, OpContains, []string{"login"}}},
+		},
+		{
+			name:  "skipped keys",
+			query: "limit=10&cursor=abc&overdue=true",
+			want:  []Condition{{FieldOverdue, OpEq, []string{"true"}}},
+		},
+		{
+			name:  "unknown field",
+			query: "color=red",
+			errs:  []domain.ErrorResponse{{Message: "unknown filter", Field: "color"}},
+		},
+		{
+			name:  "unsupported operator",
+			query: "title[gte]=a",
+			errs:  []domain.ErrorResponse{{Message: `operator "gte" is not supported`, Field: "title"}},
+		},
+		{
+			name:  "several values for a single value operator",
+			query: "title[contains]=a&title[contains]=b",
+			errs:  []domain.ErrorResponse{{Message: "invalid number of values", Field: "title"}},
+		},
+		{
+			name:  "empty value",
+			query: "status[in]=active,",
+			errs:  []domain.ErrorResponse{{Message: "value is required", Field: "status"}},
+		},
+		{
+			name:  "invalid date",
+			query: "due_date[gte]=01.01.2024",
+			errs:  []domain.ErrorResponse{{Message: "invalid date format", Field: "due_date"}},
+		},
+		{
+			name:  "invalid overdue",
+			query: "overdue=yes",
+			errs:  []domain.ErrorResponse{{Message: "overdue must be true or false", Field: "overdue"}},
+		},
+		{
+			name:  "invalid priority",
+			query: "priority=urgent",
+			errs:  []domain.ErrorResponse{{Message: "invalid priority value", Field: "priority"}},
+		},
+		{
+			name:  "invalid status",
+			query: "status=Not Done",
+			errs:  []domain.ErrorResponse{{Message: "invalid status value", Field: "status"}},
+		},
+		{
+			name:  "valid conditions are kept next to errors",
+			query: "color=red&status=done",
+			want:  []Condition{{FieldStatus, OpEq, []string{"done"}}},
+			errs:  []domain.ErrorResponse{{Message: "unknown filter", Field: "color"}},
+		},
+	}
+
+	skip := map[string]bool{"limit": true, "cursor": true}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			f, errs := ParseFilter(q, skip)
+
+			if !reflect.DeepEqual(f.Conditions, tt.want) {
+				t.Errorf("got conditions %+v, want %+v", f.Conditions, tt.want)
+			}
+			if !reflect.DeepEqual(errs, tt.errs) {
+				t.Errorf("got errors %+v, want %+v", errs, tt.errs)
+			}
+		})
+	}
+}
@@ -1,12 +1,66 @@
 package task
 
-import "context"
+import (
+	"context"
+	"project-management/internal/domain"
+	"time"
+)
 
 type Repository interface {
-	List(ctx context.Context) ([]Entity, error)
-	Search(ctx context.Context, filter, value string) ([]Entity, error)
+	List(ctx context.Context, filter Filter, page domain.PageRequest) (domain.Page[Entity], error)
+	Search(ctx context.Context, filter Filter, page domain.PageRequest) (domain.Page[Entity], error)
 	Get(ctx context.Context, id string) (Entity, error)
 	Create(ctx context.Context, Entity Entity) (string, error)
-	Update(ctx context.Context, id string, Entity Entity) error
-	Delete(ctx context.Context, id string) error
+	// Update and Assign store the events in the same transaction as the
+	// change. Update and Delete only apply to the given version of the task,
+	// they return domain.ErrVersionConflict once it has moved on. The version
+	// of the update is that of the entity, zero skips the check.
+	Update(ctx context.Context, id string, Entity Entity, events ...Event) error
+	// Patch works like Update and also empties the description, parent, due
+	// date or estimate named in clear
+	Patch(ctx context.Context, id string, Entity Entity, clear []Field, events ...Event) error
+	// Delete moves the row to the trash, Restore takes it out again and Purge
+	// removes rows that were deleted before the given time for good
+	Delete(ctx context.Context, id string, version int64) error
+	Restore(ctx context.Context, id string) error
+	// Bulk stores the writes in one transaction and returns the error of each
+	// write. Atomic stops at the first failing write and stores nothing,
+	// otherwise the writes that succeed are kept.
+	Bulk(ctx context.Context, writes []Write, atomic bool) ([]error, error)
+	Purge(ctx context.Context, before time.Time) (int64, error)
+	Assign(ctx context.Context, id, assigneeID string, events ...Event) error
+	History(ctx context.Context, id string) ([]Event, error)
+	// Subtasks returns every task below the task, however deeply nested
+	Subtasks(ctx context.Context, id string) ([]Entity, error)
+	// OwnedBy returns the live tasks the user authored or is assigned to and
+	// locks them until the unit of work ctx runs in ends
+	OwnedBy(ctx context.Context, userID string) ([]Entity, error)
+	// Lock holds the live tasks among the given ones until the unit of work
+	// ctx runs in ends, so the checks of a change stay true until it is
+	// stored. Missing tasks are left for Get to report.
+	Lock(ctx context.Context, ids ...string) error
+	FullTextSearch(ctx context.Context, query string, limit int) ([]domain.Ranked[Entity], error)
+}
+
+// WorkflowRepository stores the workflows configured per project, GetWorkflow
+// returns ErrWorkflowNotFound for projects that use the default one.
+type WorkflowRepository interface {
+	GetWorkflow(ctx context.Context, projectID string) (Workflow, error)
+	SaveWorkflow(ctx context.Context, projectID string, w Workflow) error
+}
+
+// DependencyRepository stores "blocker blocks blocked" relations between
+// tasks. Deleted tasks neither block nor are blocked, unless the deleted scope
+// of the context includes them.
+type DependencyRepository interface {
+	AddDependency(ctx context.Context, blockerID, blockedID string) error
+	RemoveDependency(ctx context.Context, blockerID, blockedID string) error
+	// Lock holds the live tasks and keeps other units of work from changing
+	// the dependencies until the unit of work ctx runs in ends, so a cycle
+	// check stays true until the dependency is stored
+	Lock(ctx context.Context, taskIDs ...string) error
+	// Blockers lists the tasks blocking the task
+	Blockers(ctx context.Context, taskID string) ([]Entity, error)
+	// Dependents lists the tasks the task blocks
+	Dependents(ctx context.Context, taskID string) ([]Entity, error)
 }
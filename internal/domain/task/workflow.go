@@ -0,0 +1,145 @@
+package task
+
+import (
+	"fmt"
+	"regexp"
+
+	"project-management/internal/domain"
+)
+
+const (
+	StatusActive     = "active"
+	StatusInProgress = "in_progress"
+	StatusReview     = "review"
+	StatusDone       = "done"
+)
+
+var (
+	ErrWorkflowNotFound     = &TaskError{"workflow not found"}
+	ErrUnknownStatus        = &TaskError{"status is not part of the project workflow"}
+	ErrInvalidTransition    = &TaskError{"status transition is not allowed by the project workflow"}
+	ErrTransitionRestricted = &TaskError{"status transition is allowed to project managers only"}
+	ErrStatusInUse          = &TaskError{"status is still used by tasks of the project"}
+)
+
+// statusName restricts status names to something that is safe to use in
+// query strings and as a postgres value.
+var statusName = regexp.MustCompile(`^[a-z][a-z0-9_]{0,31}$`)
+
+// Transition is an allowed move between two statuses. Manager only
+// transitions may be performed by admins and project managers.
+type Transition struct {
+	From        string `json:"from"`
+	To          string `json:"to"`
+	ManagerOnly bool   `json:"manager_only"`
+}
+
+// Workflow is the state machine a task status follows within a project. New
+// tasks start in the initial status, entering a terminal status marks the task
+// done.
+type Workflow struct {
+	Statuses    []string     `json:"statuses"`
+	Initial     string       `json:"initial"`
+	Terminal    []string     `json:"terminal"`
+	Transitions []Transition `json:"transitions"`
+}
+
+// DefaultWorkflow is used by projects that did not configure their own.
+func DefaultWorkflow() Workflow {
+	return Workflow{
+		Statuses: []string{StatusActive, StatusInProgress, StatusReview, StatusDone},
+		Initial:  StatusActive,
+		Terminal: []string{StatusDone},
+		Transitions: []Transition{
+			{From: StatusActive, To: StatusInProgress},
+			{From: StatusInProgress, To: StatusActive},
+			{From: StatusInProgress, To: StatusReview},
+			{From: StatusReview, To: StatusInProgress},
+			{From: StatusReview, To: StatusDone},
+			{From: StatusDone, To: StatusActive, ManagerOnly: true},
+		},
+	}
+}
+
+func (w Workflow) HasStatus(status string) bool {
+	for _, s := range w.Statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+func (w Workflow) IsTerminal(status string) bool {
+	for _, s := range w.Terminal {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// CanTransition checks a status change, manager tells whether the actor may
+// use manager only transitions. Staying in the same status is always allowed.
+func (w Workflow) CanTransition(from, to string, manager bool) error {
+	if !w.HasStatus(to) {
+		return ErrUnknownStatus
+	}
+
+	if from == to {
+		return nil
+	}
+
+	for _, t := range w.Transitions {
+		if t.From != from || t.To != to {
+			continue
+		}
+
+		if t.ManagerOnly && !manager {
+			return ErrTransitionRestricted
+		}
+		return nil
+	}
+
+	return ErrInvalidTransition
+}
+
+func (w Workflow) Validate() []domain.ErrorResponse {
+	var errs []domain.ErrorResponse
+
+	if len(w.Statuses) == 0 {
+		errs = append(errs, domain.ErrorResponse{Message: "at least one status is required", Field: "statuses"})
+	}
+
+	seen := map[string]bool{}
+	for _, s := range w.Statuses {
+		if !statusName.MatchString(s) {
+			errs = append(errs, domain.ErrorResponse{Message: fmt.Sprintf("invalid status name %q", s), Field: "statuses"})
+		}
+		if seen[s] {
+			errs = append(errs, domain.ErrorResponse{Message: fmt.Sprintf("duplicate status %q", s), Field: "statuses"})
+		}
+		seen[s] = true
+	}
+
+	if !seen[w.Initial] {
+		errs = append(errs, domain.ErrorResponse{Message: "initial status must be one of the statuses", Field: "initial"})
+	}
+
+	if len(w.Terminal) == 0 {
+		errs = append(errs, domain.ErrorResponse{Message: "at least one terminal status is required", Field: "terminal"})
+	}
+	for _, s := range w.Terminal {
+		if !seen[s] {
+			errs = append(errs, domain.ErrorResponse{Message: fmt.Sprintf("terminal status %q is not one of the statuses", s), Field: "terminal"})
+		}
+	}
+
+	for _, t := range w.Transitions {
+		if !seen[t.From] || !seen[t.To] || t.From == t.To {
+			errs = append(errs, domain.ErrorResponse{Message: fmt.Sprintf("invalid transition %q -> %q", t.From, t.To), Field: "transitions"})
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,11 @@
+package domain
+
+import "context"
+
+// TxManager runs a unit of work: the repositories called with the context
+// handed to fn share one transaction that is committed when fn returns nil
+// and rolled back otherwise. A unit of work started inside another one joins
+// it.
+type TxManager interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
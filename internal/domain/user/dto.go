@@ -11,14 +11,18 @@ type Request struct {
 	Email            string `json:"email"`
 	Role             string `json:"role"`
 	RegistrationDate string `json:"registration_date"`
+	Password         string `json:"password"`
 }
 
 type UpdateRequest struct {
-	Name  string `json:"name,omitempty"`
-	Email string `json:"email,omitempty"`
-	Role  string `json:"role,omitempty"`
+	Name     string `json:"name,omitempty"`
+	Email    string `json:"email,omitempty"`
+	Role     string `json:"role,omitempty"`
+	Password string `json:"password,omitempty"`
 }
 
+const MinPasswordLength = 8
+
 func (u *Request) Validate() []domain.ErrorResponse {
 	var errs []domain.ErrorResponse
 
@@ -30,7 +34,7 @@ func (u *Request) Validate() []domain.ErrorResponse {
 		errs = append(errs, domain.ErrorResponse{Message: "invalid email address", Field: "email"})
 	}
 
-	if u.Role != "admin" && u.Role != "manager" {
+	if !IsValidRole(u.Role) {
 		errs = append(errs, domain.ErrorResponse{Message: "invalid role", Field: "role"})
 	}
 
@@ -38,6 +42,10 @@ func (u *Request) Validate() []domain.ErrorResponse {
 		errs = append(errs, domain.ErrorResponse{Message: "invalid registration_date format", Field: "registration_date"})
 	}
 
+	if len(u.Password) < MinPasswordLength {
+		errs = append(errs, domain.ErrorResponse{Message: "password must be at least 8 characters", Field: "password"})
+	}
+
 	return errs
 }
 
@@ -48,19 +56,67 @@ func (u *UpdateRequest) Validate() []domain.ErrorResponse {
 		errs = append(errs, domain.ErrorResponse{Message: "invalid email address", Field: "email"})
 	}
 
-	if u.Role != "" && u.Role != "admin" && u.Role != "manager" {
+	if u.Role != "" && !IsValidRole(u.Role) {
 		errs = append(errs, domain.ErrorResponse{Message: "invalid role", Field: "role"})
 	}
 
+	if u.Password != "" && len(u.Password) < MinPasswordLength {
+		errs = append(errs, domain.ErrorResponse{Message: "password must be at least 8 characters", Field: "password"})
+	}
+
 	return errs
 }
 
+// PatchRequest is a JSON Merge Patch (RFC 7396) of a user. Absent fields stay
+// as they are, none of the fields can be cleared.
+type PatchRequest struct {
+	Name     domain.Nullable[string] `json:"name" swaggertype:"string"`
+	Email    domain.Nullable[string] `json:"email" swaggertype:"string"`
+	Role     domain.Nullable[string] `json:"role" swaggertype:"string"`
+	Password domain.Nullable[string] `json:"password" swaggertype:"string"`
+}
+
+func (u *PatchRequest) Validate() []domain.ErrorResponse {
+	var errs []domain.ErrorResponse
+
+	required := []struct {
+		field   string
+		cleared bool
+	}{
+		{"name", u.Name.Cleared() || u.Name.Empty()},
+		{"email", u.Email.Cleared() || u.Email.Empty()},
+		{"role", u.Role.Cleared() || u.Role.Empty()},
+		{"password", u.Password.Cleared() || u.Password.Empty()},
+	}
+	for _, r := range required {
+		if r.cleared {
+			errs = append(errs, domain.ErrorResponse{Message: r.field + " can not be null or empty", Field: r.field})
+		}
+	}
+
+	update := u.Update()
+
+	return append(errs, update.Validate()...)
+}
+
+// Update returns the values the patch sets.
+func (u *PatchRequest) Update() UpdateRequest {
+	return UpdateRequest{
+		Name:     u.Name.Value,
+		Email:    u.Email.Value,
+		Role:     u.Role.Value,
+		Password: u.Password.Value,
+	}
+}
+
 type Response struct {
 	ID               string `json:"id"`
 	Name             string `json:"name"`
 	Email            string `json:"email"`
 	Role             string `json:"role"`
 	RegistrationDate string `json:"registration_date"`
+	DeletedAt        string `json:"deleted_at,omitempty"`
+	Version          int64  `json:"version"`
 }
 
 func ParseFromEntity(u Entity) Response {
@@ -70,6 +126,8 @@ func ParseFromEntity(u Entity) Response {
 		Email:            u.Email,
 		Role:             u.Role,
 		RegistrationDate: u.RegistrationDate.String(),
+		DeletedAt:        domain.FormatTime(u.DeletedAt),
+		Version:          u.Version,
 	}
 }
 
@@ -2,6 +2,7 @@ package user
 
 import (
 	"project-management/internal/domain"
+	"time"
 )
 
 type Entity struct {
@@ -10,12 +11,31 @@ type Entity struct {
 	Email            string
 	RegistrationDate domain.OnlyDate `db:"registration_date"`
 	Role             string
+	PasswordHash     string     `db:"password_hash"`
+	DeletedAt        *time.Time `db:"deleted_at"`
+	// Version is bumped by every write, an update carrying a version only
+	// applies to that version of the user
+	Version int64 `db:"version"`
+}
+
+const (
+	RoleAdmin     = "admin"
+	RoleManager   = "manager"
+	RoleDeveloper = "developer"
+)
+
+func IsValidRole(role string) bool {
+	return role == RoleAdmin || role == RoleManager || role == RoleDeveloper
 }
 
 var (
 	ErrExists   = &UserError{"user already exists"}
 	ErrNotFound = &UserError{"user not found"}
 	ErrSearch   = &UserError{"user search error"}
+
+	ErrOwnsWork       = &UserError{"the user still manages projects or owns open tasks, name a user to reassign them to"}
+	ErrReassignTarget = &UserError{"work can only be reassigned to another existing user"}
+	ErrEmailTaken     = &UserError{"another user has taken the email of the user meanwhile"}
 )
 
 func IsValidFilter(filter string) bool {
@@ -1,12 +1,28 @@
 package user
 
-import "context"
+import (
+	"context"
+	"project-management/internal/domain"
+	"time"
+)
 
 type Repository interface {
-	List(ctx context.Context) ([]Entity, error)
-	Search(ctx context.Context, filter, value string) ([]Entity, error)
+	List(ctx context.Context, page domain.PageRequest) (domain.Page[Entity], error)
+	Search(ctx context.Context, filter, value string, page domain.PageRequest) (domain.Page[Entity], error)
 	Create(context.Context, Entity) (string, error)
 	Get(ctx context.Context, id string) (Entity, error)
+	GetByEmail(ctx context.Context, email string) (Entity, error)
+	// Lock holds the live user until the unit of work ctx runs in ends, rows
+	// can not be pointed at the user meanwhile
+	Lock(ctx context.Context, id string) error
+	// Update and Delete only apply to the given version of the user, they
+	// return domain.ErrVersionConflict once it has moved on. The version of
+	// the update is that of u, zero skips the check.
 	Update(ctx context.Context, id string, u Entity) error
-	Delete(ctx context.Context, id string) error
+	// Delete moves the row to the trash, Restore takes it out again and Purge
+	// removes rows that were deleted before the given time for good
+	Delete(ctx context.Context, id string, version int64) error
+	Restore(ctx context.Context, id string) error
+	Purge(ctx context.Context, before time.Time) (int64, error)
+	FullTextSearch(ctx context.Context, query string, limit int) ([]domain.Ranked[Entity], error)
 }
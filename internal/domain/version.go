@@ -0,0 +1,7 @@
+package domain
+
+import "errors"
+
+// ErrVersionConflict is returned by writes made against a version of a row
+// that has been changed since. A version of zero skips the check.
+var ErrVersionConflict = errors.New("the resource has been changed since it was read")
@@ -4,6 +4,7 @@ import (
 	"project-management/internal/handler/httphandler"
 	"project-management/internal/service/management"
 	"project-management/pkg/router"
+	"project-management/pkg/token"
 
 	_ "project-management/docs"
 
@@ -13,6 +14,7 @@ import (
 
 type Dependencies struct {
 	ManagementService *management.Service
+	TokenManager      *token.Manager
 }
 
 type Handler struct {
@@ -40,6 +42,10 @@ func New(deps Dependencies, cfgs ...Configuration) Handler {
 // @description This is a simple project management API
 // @host localhost:8080
 // @BasePath /api/v1
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description Access token from /auth/login, written as "Bearer {token}"
 func WithHTTPHandler() Configuration {
 	return func(h *Handler) error {
 		h.HTTP = router.New()
@@ -47,13 +53,24 @@ func WithHTTPHandler() Configuration {
 		userHandler := httphandler.NewUserHandler(h.deps.ManagementService)
 		taskHandler := httphandler.NewTaskHandler(h.deps.ManagementService)
 		projecthandler := httphandler.NewProjectHandler(h.deps.ManagementService)
+		searchHandler := httphandler.NewSearchHandler(h.deps.ManagementService)
+		auditHandler := httphandler.NewAuditHandler(h.deps.ManagementService)
+		authHandler := httphandler.NewAuthHandler(h.deps.ManagementService)
 
 		h.HTTP.Get("/swagger/*", httpSwagger.WrapHandler)
 
 		h.HTTP.Route("/api/v1", func(r chi.Router) {
-			r.Mount("/users", userHandler.Routes())
-			r.Mount("/tasks", taskHandler.Routes())
-			r.Mount("/projects", projecthandler.Routes())
+			r.Mount("/auth", authHandler.Routes())
+
+			r.Group(func(r chi.Router) {
+				r.Use(router.Authenticator(h.deps.TokenManager))
+
+				r.Mount("/users", userHandler.Routes())
+				r.Mount("/tasks", taskHandler.Routes())
+				r.Mount("/projects", projecthandler.Routes())
+				r.Mount("/search", searchHandler.Routes())
+				r.Mount("/audit", auditHandler.Routes())
+			})
 		})
 
 		return nil
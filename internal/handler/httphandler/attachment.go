@@ -0,0 +1,178 @@
+package httphandler
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"project-management/internal/domain/attachment"
+	"project-management/internal/domain/task"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// @Summary List task attachments
+// @Description List the files attached to a task
+// @Tags tasks
+// @Param id path string true "Task ID"
+// @Success 200 {object} []attachment.Response
+// @Failure 404 {string} string "Task not found"
+// @Security BearerAuth
+// @Failure 401 {string} string "Unauthorized"
+// @Router /tasks/{id}/attachments [get]
+func (h *TaskHandler) listAttachments(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	attachments, err := h.managementService.ListAttachments(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, task.ErrNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	render.JSON(w, r, attachments)
+}
+
+// @Summary Attach a file to a task
+// @Description Upload a file as the multipart field "file". The type is detected from the content and has to be one of ATTACHMENT_ALLOWED_TYPES, the size is limited by ATTACHMENT_MAX_SIZE.
+// @Tags tasks
+// @Accept multipart/form-data
+// @Param id path string true "Task ID"
+// @Param file formData file true "File"
+// @Success 201 {object} attachment.Response
+// @Failure 400 {string} string "No file or invalid name"
+// @Failure 404 {string} string "Task not found"
+// @Failure 413 {string} string "File too large"
+// @Failure 415 {string} string "File type not allowed"
+// @Security BearerAuth
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Router /tasks/{id}/attachments [post]
+func (h *TaskHandler) uploadAttachment(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	// the parts are streamed to the blob store, nothing is buffered on disk
+	// or in memory by the multipart parser
+	mr, err := r.MultipartReader()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			http.Error(w, "file is required", http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if part.FormName() != "file" {
+			part.Close()
+			continue
+		}
+
+		data, err := h.managementService.UploadAttachment(r.Context(), id, part.FileName(), part)
+		part.Close()
+		if err != nil {
+			if writeAccessError(w, err) {
+				return
+			}
+
+			switch {
+			case errors.Is(err, task.ErrNotFound):
+				http.Error(w, err.Error(), http.StatusNotFound)
+			case errors.Is(err, attachment.ErrInvalidName):
+				http.Error(w, err.Error(), http.StatusBadRequest)
+			case errors.Is(err, attachment.ErrTooLarge):
+				http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+			case errors.Is(err, attachment.ErrTypeNotAllowed):
+				http.Error(w, err.Error(), http.StatusUnsupportedMediaType)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+			return
+		}
+
+		render.Status(r, http.StatusCreated)
+		render.JSON(w, r, data)
+		return
+	}
+}
+
+// @Summary Download an attachment
+// @Description Download the content of an attachment
+// @Tags tasks
+// @Produce octet-stream
+// @Param id path string true "Task ID"
+// @Param attachmentID path string true "Attachment ID"
+// @Success 200 {file} file "Attachment content"
+// @Failure 404 {string} string "Task or attachment not found"
+// @Security BearerAuth
+// @Failure 401 {string} string "Unauthorized"
+// @Router /tasks/{id}/attachments/{attachmentID} [get]
+func (h *TaskHandler) downloadAttachment(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	attachmentID := chi.URLParam(r, "attachmentID")
+
+	data, content, err := h.managementService.OpenAttachment(r.Context(), id, attachmentID)
+	if err != nil {
+		switch {
+		case errors.Is(err, task.ErrNotFound), errors.Is(err, attachment.ErrNotFound), errors.Is(err, attachment.ErrBlobNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		return
+	}
+	defer content.Close()
+
+	w.Header().Set("Content-Type", data.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(data.Size, 10))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": data.Name}))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+
+	io.Copy(w, content)
+}
+
+// @Summary Delete an attachment
+// @Description Delete an attachment and its content
+// @Tags tasks
+// @Param id path string true "Task ID"
+// @Param attachmentID path string true "Attachment ID"
+// @Success 200 {string} string "Attachment deleted"
+// @Failure 404 {string} string "Task or attachment not found"
+// @Security BearerAuth
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Router /tasks/{id}/attachments/{attachmentID} [delete]
+func (h *TaskHandler) deleteAttachment(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	attachmentID := chi.URLParam(r, "attachmentID")
+
+	err := h.managementService.DeleteAttachment(r.Context(), id, attachmentID)
+	if err != nil {
+		if writeAccessError(w, err) {
+			return
+		}
+
+		switch {
+		case errors.Is(err, task.ErrNotFound), errors.Is(err, attachment.ErrNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
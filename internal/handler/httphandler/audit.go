@@ -0,0 +1,75 @@
+package httphandler
+
+import (
+	"encoding/json"
+	"net/http"
+	_ "project-management/internal/domain" // resolves domain.Page in swagger annotations
+	"project-management/internal/domain/audit"
+	"project-management/internal/service/management"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+type AuditHandler struct {
+	managementService *management.Service
+}
+
+func NewAuditHandler(managementService *management.Service) *AuditHandler {
+	return &AuditHandler{
+		managementService: managementService,
+	}
+}
+
+func (h *AuditHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/", h.list)
+
+	return r
+}
+
+// @Summary List the audit log
+// @Description List who created, changed or deleted users, projects and tasks, oldest first. Only admins may read the audit log.
+// @Tags audit
+// @Param entity query string false "user, project or task"
+// @Param id query string false "Entity ID, requires entity"
+// @Param since query string false "Only entries written at or after the date or RFC 3339 timestamp"
+// @Param limit query int false "Page size, 20 by default and 100 at most"
+// @Param offset query int false "Number of entries to skip"
+// @Param cursor query string false "Cursor returned as next_cursor by the previous page"
+// @Success 200 {object} domain.Page[audit.Response]
+// @Failure 400 {object} []domain.ErrorResponse "Validation errors"
+// @Security BearerAuth
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Router /audit [get]
+func (h *AuditHandler) list(w http.ResponseWriter, r *http.Request) {
+	page, errs := parsePageRequest(r)
+	if errs != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errs)
+		return
+	}
+
+	filter, errs := audit.ParseFilter(r.URL.Query())
+	if errs != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errs)
+		return
+	}
+
+	entries, err := h.managementService.ListAudit(r.Context(), filter, page)
+	if err != nil {
+		if writeAccessError(w, err) {
+			return
+		}
+
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	render.JSON(w, r, entries)
+}
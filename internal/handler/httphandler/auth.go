@@ -0,0 +1,105 @@
+package httphandler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"project-management/internal/domain/auth"
+	"project-management/internal/service/management"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+type AuthHandler struct {
+	managementService *management.Service
+}
+
+func NewAuthHandler(managementService *management.Service) *AuthHandler {
+	return &AuthHandler{
+		managementService: managementService,
+	}
+}
+
+func (h *AuthHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Post("/login", h.login)
+	r.Post("/refresh", h.refresh)
+
+	return r
+}
+
+// @Summary Log in
+// @Description Exchange email and password for an access and a refresh token
+// @Tags auth
+// @Accept json
+// @Param body body auth.LoginRequest true "Credentials"
+// @Success 200 {object} auth.TokenResponse
+// @Failure 400 {object} []domain.ErrorResponse "Validation errors"
+// @Failure 401 {string} string "Invalid email or password"
+// @Router /auth/login [post]
+func (h *AuthHandler) login(w http.ResponseWriter, r *http.Request) {
+	req := auth.LoginRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if errs := req.Validate(); errs != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errs)
+		return
+	}
+
+	res, err := h.managementService.Login(r.Context(), req)
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidCredentials) {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	render.JSON(w, r, res)
+}
+
+// @Summary Refresh tokens
+// @Description Exchange a refresh token for a new access and refresh token
+// @Tags auth
+// @Accept json
+// @Param body body auth.RefreshRequest true "Refresh token"
+// @Success 200 {object} auth.TokenResponse
+// @Failure 400 {object} []domain.ErrorResponse "Validation errors"
+// @Failure 401 {string} string "Invalid token"
+// @Router /auth/refresh [post]
+func (h *AuthHandler) refresh(w http.ResponseWriter, r *http.Request) {
+	req := auth.RefreshRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if errs := req.Validate(); errs != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errs)
+		return
+	}
+
+	res, err := h.managementService.Refresh(r.Context(), req)
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidToken) {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	render.JSON(w, r, res)
+}
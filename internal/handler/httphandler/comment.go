@@ -0,0 +1,179 @@
+package httphandler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"project-management/internal/domain/comment"
+	"project-management/internal/domain/task"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// @Summary List task comments
+// @Description List the comments of a task as threads, replies are nested under the comment they answer
+// @Tags tasks
+// @Param id path string true "Task ID"
+// @Success 200 {object} []comment.Response
+// @Failure 404 {string} string "Task not found"
+// @Security BearerAuth
+// @Failure 401 {string} string "Unauthorized"
+// @Router /tasks/{id}/comments [get]
+func (h *TaskHandler) listComments(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	comments, err := h.managementService.ListComments(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, task.ErrNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	render.JSON(w, r, comments)
+}
+
+// @Summary Comment on a task
+// @Description Comment on a task as the authenticated user, set parent_id to reply to another comment of the task
+// @Tags tasks
+// @Accept json
+// @Param id path string true "Task ID"
+// @Param body body comment.Request true "Comment request"
+// @Success 201 {string} string "Comment ID"
+// @Failure 400 {object} []domain.ErrorResponse "Validation errors"
+// @Failure 404 {string} string "Task not found"
+// @Security BearerAuth
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Router /tasks/{id}/comments [post]
+func (h *TaskHandler) createComment(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	req := comment.Request{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if errs := req.Validate(); errs != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errs)
+		return
+	}
+
+	commentID, err := h.managementService.CreateComment(r.Context(), id, req)
+	if err != nil {
+		if writeAccessError(w, err) {
+			return
+		}
+
+		switch {
+		case errors.Is(err, task.ErrNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, comment.ErrParentNotFound):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.PlainText(w, r, commentID)
+}
+
+// @Summary Edit a comment
+// @Description Edit a comment, only its author may do so
+// @Tags tasks
+// @Accept json
+// @Param id path string true "Task ID"
+// @Param commentID path string true "Comment ID"
+// @Param body body comment.UpdateRequest true "Comment request"
+// @Success 200 {string} string "Comment updated"
+// @Failure 400 {object} []domain.ErrorResponse "Validation errors"
+// @Failure 404 {string} string "Task or comment not found"
+// @Failure 409 {string} string "Comment is deleted"
+// @Security BearerAuth
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Not the author"
+// @Router /tasks/{id}/comments/{commentID} [put]
+func (h *TaskHandler) updateComment(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	commentID := chi.URLParam(r, "commentID")
+
+	req := comment.UpdateRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if errs := req.Validate(); errs != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errs)
+		return
+	}
+
+	err := h.managementService.UpdateComment(r.Context(), id, commentID, req)
+	if err != nil {
+		if writeAccessError(w, err) || writeCommentError(w, err) {
+			return
+		}
+
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// @Summary Delete a comment
+// @Description Delete a comment, only its author may do so. A comment with replies is kept with an empty body and marked deleted.
+// @Tags tasks
+// @Param id path string true "Task ID"
+// @Param commentID path string true "Comment ID"
+// @Success 200 {string} string "Comment deleted"
+// @Failure 404 {string} string "Task or comment not found"
+// @Failure 409 {string} string "Comment is already deleted"
+// @Security BearerAuth
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Not the author"
+// @Router /tasks/{id}/comments/{commentID} [delete]
+func (h *TaskHandler) deleteComment(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	commentID := chi.URLParam(r, "commentID")
+
+	err := h.managementService.DeleteComment(r.Context(), id, commentID)
+	if err != nil {
+		if writeAccessError(w, err) || writeCommentError(w, err) {
+			return
+		}
+
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// writeCommentError answers the failures shared by the comment edits and
+// tells whether err was one of them.
+func writeCommentError(w http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, task.ErrNotFound), errors.Is(err, comment.ErrNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, comment.ErrNotAuthor):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, comment.ErrDeleted):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		return false
+	}
+
+	return true
+}
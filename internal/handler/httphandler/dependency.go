@@ -0,0 +1,122 @@
+package httphandler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"project-management/internal/domain/task"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// @Summary List task dependencies
+// @Description List the tasks blocking the task and the tasks it blocks
+// @Tags tasks
+// @Param id path string true "Task ID"
+// @Success 200 {object} task.DependenciesResponse
+// @Failure 404 {string} string "Task not found"
+// @Security BearerAuth
+// @Failure 401 {string} string "Unauthorized"
+// @Router /tasks/{id}/dependencies [get]
+func (h *TaskHandler) listDependencies(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	dependencies, err := h.managementService.GetTaskDependencies(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, task.ErrNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	render.JSON(w, r, dependencies)
+}
+
+// @Summary Add a task dependency
+// @Description Mark the task as blocked by another task, it can not enter a terminal status until the blocker is done
+// @Tags tasks
+// @Accept json
+// @Param id path string true "Task ID"
+// @Param body body task.DependencyRequest true "Dependency request"
+// @Success 201 {string} string "Dependency added"
+// @Failure 400 {object} []domain.ErrorResponse "Validation errors"
+// @Failure 404 {string} string "Task or blocker not found"
+// @Failure 409 {string} string "Dependency exists or would create a cycle"
+// @Security BearerAuth
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Router /tasks/{id}/dependencies [post]
+func (h *TaskHandler) addDependency(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	req := task.DependencyRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if errs := req.Validate(); errs != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errs)
+		return
+	}
+
+	err := h.managementService.AddTaskDependency(r.Context(), id, req)
+	if err != nil {
+		if writeAccessError(w, err) {
+			return
+		}
+
+		switch {
+		case errors.Is(err, task.ErrNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, task.ErrSelfDependency):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, task.ErrDependencyExists), errors.Is(err, task.ErrDependencyCycle):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+}
+
+// @Summary Remove a task dependency
+// @Description Remove a blocker from the task
+// @Tags tasks
+// @Param id path string true "Task ID"
+// @Param blockerID path string true "Blocker task ID"
+// @Success 200 {string} string "Dependency removed"
+// @Failure 404 {string} string "Task or dependency not found"
+// @Security BearerAuth
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Router /tasks/{id}/dependencies/{blockerID} [delete]
+func (h *TaskHandler) removeDependency(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	blockerID := chi.URLParam(r, "blockerID")
+
+	err := h.managementService.RemoveTaskDependency(r.Context(), id, blockerID)
+	if err != nil {
+		if writeAccessError(w, err) {
+			return
+		}
+
+		switch {
+		case errors.Is(err, task.ErrNotFound), errors.Is(err, task.ErrDependencyNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,70 @@
+package httphandler
+
+import (
+	"errors"
+	"net/http"
+	"project-management/internal/domain"
+	"project-management/internal/domain/auth"
+	"project-management/internal/domain/task"
+)
+
+// writeAccessError answers authentication and authorization failures reported
+// by the service and tells whether err was one of them.
+func writeAccessError(w http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, auth.ErrUnauthenticated):
+		w.WriteHeader(http.StatusUnauthorized)
+	case errors.Is(err, auth.ErrForbidden):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	default:
+		return false
+	}
+
+	return true
+}
+
+// writeVersionError answers writes based on a version that is not current
+// anymore and tells whether err was one.
+func writeVersionError(w http.ResponseWriter, err error) bool {
+	if !errors.Is(err, domain.ErrVersionConflict) {
+		return false
+	}
+
+	http.Error(w, err.Error(), http.StatusPreconditionFailed)
+
+	return true
+}
+
+// writeWorkflowError answers status changes rejected by the project workflow
+// and tells whether err was one of them.
+func writeWorkflowError(w http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, task.ErrUnknownStatus):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, task.ErrInvalidTransition):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, task.ErrTransitionRestricted):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, task.ErrBlocked):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		return false
+	}
+
+	return true
+}
+
+// writeHierarchyError answers parent changes that would break the task tree
+// and tells whether err was one of them.
+func writeHierarchyError(w http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, task.ErrParentNotFound), errors.Is(err, task.ErrParentProject):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, task.ErrParentCycle), errors.Is(err, task.ErrSubtasksMoved):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		return false
+	}
+
+	return true
+}
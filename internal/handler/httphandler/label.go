@@ -0,0 +1,257 @@
+package httphandler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"project-management/internal/domain/label"
+	"project-management/internal/domain/project"
+	"project-management/internal/domain/task"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// @Summary List project labels
+// @Description List the labels tasks of the project can carry
+// @Tags projects
+// @Param id path string true "Project ID"
+// @Success 200 {object} []label.Response
+// @Failure 404 {string} string "Project not found"
+// @Security BearerAuth
+// @Failure 401 {string} string "Unauthorized"
+// @Router /projects/{id}/labels [get]
+func (h *ProjectHandler) listLabels(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	labels, err := h.managementService.ListLabels(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, project.ErrNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	render.JSON(w, r, labels)
+}
+
+// @Summary Create a project label
+// @Description Create a label in the project, names are unique within the project and the color defaults to #808080
+// @Tags projects
+// @Accept json
+// @Param id path string true "Project ID"
+// @Param body body label.Request true "Label request"
+// @Success 201 {string} string "Label ID"
+// @Failure 400 {object} []domain.ErrorResponse "Validation errors"
+// @Failure 404 {string} string "Project not found"
+// @Failure 409 {string} string "Label exists"
+// @Security BearerAuth
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Router /projects/{id}/labels [post]
+func (h *ProjectHandler) createLabel(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	req := label.Request{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if errs := req.Validate(); errs != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errs)
+		return
+	}
+
+	labelID, err := h.managementService.CreateLabel(r.Context(), id, req)
+	if err != nil {
+		if writeAccessError(w, err) || writeLabelError(w, err) {
+			return
+		}
+
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.PlainText(w, r, labelID)
+}
+
+// @Summary Update a project label
+// @Description Rename or recolor a label
+// @Tags projects
+// @Accept json
+// @Param id path string true "Project ID"
+// @Param labelID path string true "Label ID"
+// @Param body body label.UpdateRequest true "Label request"
+// @Success 200 {string} string "Label updated"
+// @Failure 400 {object} []domain.ErrorResponse "Validation errors"
+// @Failure 404 {string} string "Project or label not found"
+// @Failure 409 {string} string "Label exists"
+// @Security BearerAuth
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Router /projects/{id}/labels/{labelID} [put]
+func (h *ProjectHandler) updateLabel(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	labelID := chi.URLParam(r, "labelID")
+
+	req := label.UpdateRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if errs := req.Validate(); errs != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errs)
+		return
+	}
+
+	err := h.managementService.UpdateLabel(r.Context(), id, labelID, req)
+	if err != nil {
+		if writeAccessError(w, err) || writeLabelError(w, err) {
+			return
+		}
+
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// @Summary Delete a project label
+// @Description Delete a label, it is taken off every task carrying it
+// @Tags projects
+// @Param id path string true "Project ID"
+// @Param labelID path string true "Label ID"
+// @Success 200 {string} string "Label deleted"
+// @Failure 404 {string} string "Project or label not found"
+// @Security BearerAuth
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Router /projects/{id}/labels/{labelID} [delete]
+func (h *ProjectHandler) deleteLabel(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	labelID := chi.URLParam(r, "labelID")
+
+	err := h.managementService.DeleteLabel(r.Context(), id, labelID)
+	if err != nil {
+		if writeAccessError(w, err) || writeLabelError(w, err) {
+			return
+		}
+
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// @Summary List task labels
+// @Description List the labels put on a task
+// @Tags tasks
+// @Param id path string true "Task ID"
+// @Success 200 {object} []label.Response
+// @Failure 404 {string} string "Task not found"
+// @Security BearerAuth
+// @Failure 401 {string} string "Unauthorized"
+// @Router /tasks/{id}/labels [get]
+func (h *TaskHandler) listLabels(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	labels, err := h.managementService.ListTaskLabels(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, task.ErrNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	render.JSON(w, r, labels)
+}
+
+// @Summary Label a task
+// @Description Put a label of the task's project on the task, labelling it twice is not an error
+// @Tags tasks
+// @Param id path string true "Task ID"
+// @Param labelID path string true "Label ID"
+// @Success 200 {string} string "Label attached"
+// @Failure 400 {string} string "Label of another project"
+// @Failure 404 {string} string "Task or label not found"
+// @Security BearerAuth
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Router /tasks/{id}/labels/{labelID} [put]
+func (h *TaskHandler) attachLabel(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	labelID := chi.URLParam(r, "labelID")
+
+	err := h.managementService.AttachLabel(r.Context(), id, labelID)
+	if err != nil {
+		if writeAccessError(w, err) || writeLabelError(w, err) {
+			return
+		}
+
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// @Summary Unlabel a task
+// @Description Take a label off a task
+// @Tags tasks
+// @Param id path string true "Task ID"
+// @Param labelID path string true "Label ID"
+// @Success 200 {string} string "Label detached"
+// @Failure 400 {string} string "Label of another project"
+// @Failure 404 {string} string "Task or label not found"
+// @Security BearerAuth
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Router /tasks/{id}/labels/{labelID} [delete]
+func (h *TaskHandler) detachLabel(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	labelID := chi.URLParam(r, "labelID")
+
+	err := h.managementService.DetachLabel(r.Context(), id, labelID)
+	if err != nil {
+		if writeAccessError(w, err) || writeLabelError(w, err) {
+			return
+		}
+
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// writeLabelError answers the failures shared by the label endpoints and
+// tells whether err was one of them.
+func writeLabelError(w http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, project.ErrNotFound), errors.Is(err, task.ErrNotFound), errors.Is(err, label.ErrNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, label.ErrOtherProject):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, label.ErrExists):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,230 @@
+package httphandler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"project-management/internal/domain/milestone"
+	"project-management/internal/domain/project"
+	"project-management/internal/domain/task"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// @Summary List project milestones
+// @Description List the milestones of the project by due date with the progress of their tasks. The status is completed once every linked task is done, overdue when tasks are open after the due date, at_risk when they are open within a week of it and on_track otherwise
+// @Tags projects
+// @Param id path string true "Project ID"
+// @Success 200 {object} []milestone.Response
+// @Failure 404 {string} string "Project not found"
+// @Security BearerAuth
+// @Failure 401 {string} string "Unauthorized"
+// @Router /projects/{id}/milestones [get]
+func (h *ProjectHandler) listMilestones(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	milestones, err := h.managementService.ListMilestones(r.Context(), id)
+	if err != nil {
+		if writeMilestoneError(w, err) {
+			return
+		}
+
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	render.JSON(w, r, milestones)
+}
+
+// @Summary Create a milestone
+// @Description Create a milestone in the project
+// @Tags projects
+// @Accept json
+// @Param id path string true "Project ID"
+// @Param body body milestone.Request true "Milestone request"
+// @Success 201 {string} string "Milestone ID"
+// @Failure 400 {object} []domain.ErrorResponse "Validation errors"
+// @Failure 404 {string} string "Project not found"
+// @Security BearerAuth
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Router /projects/{id}/milestones [post]
+func (h *ProjectHandler) createMilestone(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	req := milestone.Request{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if errs := req.Validate(); errs != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errs)
+		return
+	}
+
+	milestoneID, err := h.managementService.CreateMilestone(r.Context(), id, req)
+	if err != nil {
+		if writeAccessError(w, err) || writeMilestoneError(w, err) {
+			return
+		}
+
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.PlainText(w, r, milestoneID)
+}
+
+// @Summary Get a milestone
+// @Description Get a milestone of the project with the progress of its tasks, list them with GET /projects/{id}/tasks?milestone_id=
+// @Tags projects
+// @Param id path string true "Project ID"
+// @Param milestoneID path string true "Milestone ID"
+// @Success 200 {object} milestone.Response
+// @Failure 404 {string} string "Project or milestone not found"
+// @Security BearerAuth
+// @Failure 401 {string} string "Unauthorized"
+// @Router /projects/{id}/milestones/{milestoneID} [get]
+func (h *ProjectHandler) getMilestone(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	milestoneID := chi.URLParam(r, "milestoneID")
+
+	res, err := h.managementService.GetMilestone(r.Context(), id, milestoneID)
+	if err != nil {
+		if writeMilestoneError(w, err) {
+			return
+		}
+
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	render.JSON(w, r, res)
+}
+
+// @Summary Update a milestone
+// @Description Change the title, description or due date of a milestone
+// @Tags projects
+// @Accept json
+// @Param id path string true "Project ID"
+// @Param milestoneID path string true "Milestone ID"
+// @Param body body milestone.UpdateRequest true "Milestone request"
+// @Success 200 {string} string "Milestone updated"
+// @Failure 400 {object} []domain.ErrorResponse "Validation errors"
+// @Failure 404 {string} string "Project or milestone not found"
+// @Security BearerAuth
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Router /projects/{id}/milestones/{milestoneID} [put]
+func (h *ProjectHandler) updateMilestone(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	milestoneID := chi.URLParam(r, "milestoneID")
+
+	req := milestone.UpdateRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if errs := req.Validate(); errs != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errs)
+		return
+	}
+
+	err := h.managementService.UpdateMilestone(r.Context(), id, milestoneID, req)
+	if err != nil {
+		if writeAccessError(w, err) || writeMilestoneError(w, err) {
+			return
+		}
+
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// @Summary Delete a milestone
+// @Description Delete a milestone, its tasks are unlinked
+// @Tags projects
+// @Param id path string true "Project ID"
+// @Param milestoneID path string true "Milestone ID"
+// @Success 200 {string} string "Milestone deleted"
+// @Failure 404 {string} string "Project or milestone not found"
+// @Security BearerAuth
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Router /projects/{id}/milestones/{milestoneID} [delete]
+func (h *ProjectHandler) deleteMilestone(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	milestoneID := chi.URLParam(r, "milestoneID")
+
+	err := h.managementService.DeleteMilestone(r.Context(), id, milestoneID)
+	if err != nil {
+		if writeAccessError(w, err) || writeMilestoneError(w, err) {
+			return
+		}
+
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// @Summary Link a task to a milestone
+// @Description Put a task on a milestone of its project, an empty milestone_id unlinks it
+// @Tags tasks
+// @Accept json
+// @Param id path string true "Task ID"
+// @Param body body milestone.LinkRequest true "Link request"
+// @Success 200 {string} string "Task linked"
+// @Failure 400 {string} string "Milestone belongs to another project"
+// @Failure 404 {string} string "Task or milestone not found"
+// @Security BearerAuth
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Router /tasks/{id}/milestone [put]
+func (h *TaskHandler) linkMilestone(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	req := milestone.LinkRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	err := h.managementService.LinkTask(r.Context(), id, req)
+	if err != nil {
+		if writeAccessError(w, err) || writeMilestoneError(w, err) {
+			return
+		}
+
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// writeMilestoneError answers milestone failures reported by the service and
+// tells whether err was one of them.
+func writeMilestoneError(w http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, project.ErrNotFound), errors.Is(err, task.ErrNotFound), errors.Is(err, milestone.ErrNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, milestone.ErrOtherProject):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,56 @@
+package httphandler
+
+import (
+	"net/http"
+	"project-management/internal/domain"
+	"strconv"
+)
+
+// pageParams are the query parameters consumed by pagination and the trash
+// scope, search handlers must not treat them as filters.
+var pageParams = map[string]bool{
+	"limit":           true,
+	"offset":          true,
+	"cursor":          true,
+	"include_deleted": true,
+}
+
+func parsePageRequest(r *http.Request) (page domain.PageRequest, errs []domain.ErrorResponse) {
+	q := r.URL.Query()
+
+	var err error
+
+	if v := q.Get("limit"); v != "" {
+		if page.Limit, err = strconv.Atoi(v); err != nil {
+			errs = append(errs, domain.ErrorResponse{Message: "limit must be a number", Field: "limit"})
+		}
+	}
+
+	if v := q.Get("offset"); v != "" {
+		if page.Offset, err = strconv.Atoi(v); err != nil {
+			errs = append(errs, domain.ErrorResponse{Message: "offset must be a number", Field: "offset"})
+		}
+	}
+
+	page.Cursor = q.Get("cursor")
+
+	if errs != nil {
+		return
+	}
+
+	errs = page.Validate()
+
+	return
+}
+
+func searchParam(r *http.Request) (filter, val string) {
+	for k, v := range r.URL.Query() {
+		if pageParams[k] {
+			continue
+		}
+
+		filter, val = k, v[0]
+	}
+
+	return
+}
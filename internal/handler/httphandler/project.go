@@ -4,7 +4,10 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	_ "project-management/internal/domain" // resolves domain.Page in swagger annotations
 	"project-management/internal/domain/project"
+	"project-management/internal/domain/task"
+	"project-management/internal/domain/user"
 	"project-management/internal/service/management"
 
 	"github.com/go-chi/chi/v5"
@@ -23,6 +26,7 @@ func NewProjectHandler(managementService *management.Service) *ProjectHandler {
 
 func (h *ProjectHandler) Routes() chi.Router {
 	r := chi.NewRouter()
+	r.Use(includeDeleted(h.managementService))
 
 	r.Post("/", h.create)
 	r.Get("/", h.list)
@@ -30,11 +34,41 @@ func (h *ProjectHandler) Routes() chi.Router {
 	r.Route("/{id}", func(r chi.Router) {
 		r.Get("/", h.get)
 		r.Put("/", h.update)
+		r.Patch("/", h.patch)
 		r.Delete("/", h.delete)
+		r.Post("/restore", h.restore)
 		r.Get("/tasks", h.listTasks)
+
+		r.Get("/members", h.listMembers)
+		r.Post("/members", h.addMember)
+		r.Delete("/members/{userID}", h.removeMember)
+
+		r.Get("/workflow", h.getWorkflow)
+		r.Put("/workflow", h.updateWorkflow)
+
+		r.Get("/labels", h.listLabels)
+		r.Post("/labels", h.createLabel)
+		r.Put("/labels/{labelID}", h.updateLabel)
+		r.Delete("/labels/{labelID}", h.deleteLabel)
+
+		r.Get("/sprints", h.listSprints)
+		r.Post("/sprints", h.createSprint)
+		r.Get("/sprints/{sprintID}", h.getSprint)
+		r.Put("/sprints/{sprintID}", h.updateSprint)
+		r.Delete("/sprints/{sprintID}", h.deleteSprint)
+		r.Post("/sprints/{sprintID}/start", h.startSprint)
+		r.Post("/sprints/{sprintID}/close", h.closeSprint)
+		r.Get("/backlog", h.listBacklog)
+
+		r.Get("/milestones", h.listMilestones)
+		r.Post("/milestones", h.createMilestone)
+		r.Get("/milestones/{milestoneID}", h.getMilestone)
+		r.Put("/milestones/{milestoneID}", h.updateMilestone)
+		r.Delete("/milestones/{milestoneID}", h.deleteMilestone)
 	})
 
 	r.Get("/search", h.search)
+	r.Get("/trash", onlyDeleted(h.managementService, h.trash))
 
 	return r
 }
@@ -46,6 +80,9 @@ func (h *ProjectHandler) Routes() chi.Router {
 // @Param body body project.Request true "Project request"
 // @Success 201 {string} string "Project ID"
 // @Failure 400 {object} []string "Validation errors"
+// @Security BearerAuth
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
 // @Router /projects [post]
 func (h *ProjectHandler) create(w http.ResponseWriter, r *http.Request) {
 	req := project.Request{}
@@ -63,6 +100,10 @@ func (h *ProjectHandler) create(w http.ResponseWriter, r *http.Request) {
 
 	id, err := h.managementService.CreateProject(r.Context(), req)
 	if err != nil {
+		if writeAccessError(w, err) {
+			return
+		}
+
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
@@ -75,7 +116,10 @@ func (h *ProjectHandler) create(w http.ResponseWriter, r *http.Request) {
 // @Tags projects
 // @Param id path string true "Project ID"
 // @Success 200 {object} project.Response
+// @Header 200 {string} ETag "Version of the project, send it as If-Match to change the project"
 // @Failure 400 {string} string "Bad request"
+// @Security BearerAuth
+// @Failure 401 {string} string "Unauthorized"
 // @Router /projects/{id} [get]
 func (h *ProjectHandler) get(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
@@ -86,17 +130,31 @@ func (h *ProjectHandler) get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	setETag(w, project.Version)
 	render.JSON(w, r, project)
 }
 
 // @Summary List projects
 // @Description List projects
 // @Tags projects
-// @Success 200 {array} project.Response
+// @Param limit query int false "Page size, 20 by default and 100 at most"
+// @Param cursor query string false "Cursor returned as next_cursor by the previous page"
+// @Param offset query int false "Number of rows to skip, can not be combined with cursor"
+// @Success 200 {object} domain.Page[project.Response]
 // @Failure 400 {string} string "Bad request"
+// @Security BearerAuth
+// @Failure 401 {string} string "Unauthorized"
 // @Router /projects [get]
 func (h *ProjectHandler) list(w http.ResponseWriter, r *http.Request) {
-	projects, err := h.managementService.ListProjects(r.Context())
+	page, errs := parsePageRequest(r)
+	if errs != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errs)
+		return
+	}
+
+	projects, err := h.managementService.ListProjects(r.Context(), page)
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		return
@@ -110,13 +168,24 @@ func (h *ProjectHandler) list(w http.ResponseWriter, r *http.Request) {
 // @Tags projects
 // @Accept json
 // @Param id path string true "Project ID"
+// @Param If-Match header string true "ETag of the project the update is based on, * for any version"
 // @Param body body project.UpdateRequest true "Project update request"
 // @Success 200 {string} string "Project updated"
 // @Failure 400 {object} []string "Validation errors"
+// @Failure 412 {string} string "The project has been changed since"
+// @Failure 428 {string} string "If-Match header missing"
+// @Security BearerAuth
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
 // @Router /projects/{id} [put]
 func (h *ProjectHandler) update(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
+	version, ok := parseIfMatch(w, r)
+	if !ok {
+		return
+	}
+
 	req := project.UpdateRequest{}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		w.WriteHeader(http.StatusBadRequest)
@@ -130,8 +199,61 @@ func (h *ProjectHandler) update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err := h.managementService.UpdateProject(r.Context(), id, req)
+	err := h.managementService.UpdateProject(r.Context(), id, version, req)
+	if err != nil {
+		if writeAccessError(w, err) || writeVersionError(w, err) {
+			return
+		}
+
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// @Summary Patch a project
+// @Description Apply a JSON Merge Patch (RFC 7396) to a project, absent fields stay as they are and null clears description, finished_at or manager_id
+// @Tags projects
+// @Accept json
+// @Param id path string true "Project ID"
+// @Param If-Match header string true "ETag of the project the patch is based on, * for any version"
+// @Param body body project.PatchRequest true "Project merge patch"
+// @Success 200 {string} string "Project updated"
+// @Failure 400 {object} []string "Validation errors"
+// @Failure 412 {string} string "The project has been changed since"
+// @Failure 428 {string} string "If-Match header missing"
+// @Security BearerAuth
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Router /projects/{id} [patch]
+func (h *ProjectHandler) patch(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	version, ok := parseIfMatch(w, r)
+	if !ok {
+		return
+	}
+
+	req := project.PatchRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if errs := req.Validate(); errs != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errs)
+		return
+	}
+
+	err := h.managementService.PatchProject(r.Context(), id, version, req)
 	if err != nil {
+		if writeAccessError(w, err) || writeVersionError(w, err) {
+			return
+		}
+
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
@@ -143,14 +265,29 @@ func (h *ProjectHandler) update(w http.ResponseWriter, r *http.Request) {
 // @Description Delete a project
 // @Tags projects
 // @Param id path string true "Project ID"
+// @Param If-Match header string true "ETag of the project the deletion is based on, * for any version"
 // @Success 200 {string} string "Project deleted"
 // @Failure 400 {string} string "Bad request"
+// @Failure 412 {string} string "The project has been changed since"
+// @Failure 428 {string} string "If-Match header missing"
+// @Security BearerAuth
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
 // @Router /projects/{id} [delete]
 func (h *ProjectHandler) delete(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
-	err := h.managementService.DeleteProject(r.Context(), id)
+	version, ok := parseIfMatch(w, r)
+	if !ok {
+		return
+	}
+
+	err := h.managementService.DeleteProject(r.Context(), id, version)
 	if err != nil {
+		if writeAccessError(w, err) || writeVersionError(w, err) {
+			return
+		}
+
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
@@ -163,18 +300,27 @@ func (h *ProjectHandler) delete(w http.ResponseWriter, r *http.Request) {
 // @Tags projects
 // @Param query query string true "Query"
 // @Param val query string true "Value"
-// @Success 200 {array} project.Response
+// @Param limit query int false "Page size, 20 by default and 100 at most"
+// @Param cursor query string false "Cursor returned as next_cursor by the previous page"
+// @Param offset query int false "Number of rows to skip, can not be combined with cursor"
+// @Success 200 {object} domain.Page[project.Response]
 // @Failure 400 {string} string "Bad request"
 // @Failure 404 {string} string "Not found"
+// @Security BearerAuth
+// @Failure 401 {string} string "Unauthorized"
 // @Router /projects/search [get]
 func (h *ProjectHandler) search(w http.ResponseWriter, r *http.Request) {
-	var filter, val string
-
-	for k, v := range r.URL.Query() {
-		filter, val = k, v[0]
+	page, errs := parsePageRequest(r)
+	if errs != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errs)
+		return
 	}
 
-	projects, err := h.managementService.SearchProjects(r.Context(), filter, val)
+	filter, val := searchParam(r)
+
+	projects, err := h.managementService.SearchProjects(r.Context(), filter, val, page)
 	if err != nil {
 		if errors.Is(err, project.ErrSearch) {
 			w.WriteHeader(http.StatusBadRequest)
@@ -194,16 +340,40 @@ func (h *ProjectHandler) search(w http.ResponseWriter, r *http.Request) {
 }
 
 // @Summary List project tasks
-// @Description List project tasks
+// @Description List project tasks, accepts the same filters as the task search
 // @Tags projects
 // @Param id path string true "Project ID"
-// @Success 200 {array} task.Response
+// @Param label query string false "Label name, repeat the parameter or use label[in]=a,b to match any of several and label[all]=a,b to require all of them"
+// @Param limit query int false "Page size, 20 by default and 100 at most"
+// @Param cursor query string false "Cursor returned as next_cursor by the previous page"
+// @Param offset query int false "Number of rows to skip, can not be combined with cursor"
+// @Success 200 {object} domain.Page[task.Response]
 // @Failure 400 {string} string "Bad request"
+// @Security BearerAuth
+// @Failure 401 {string} string "Unauthorized"
 // @Router /projects/{id}/tasks [get]
 func (h *ProjectHandler) listTasks(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
-	tasks, err := h.managementService.SearchTasks(r.Context(), "project_id", id)
+	page, errs := parsePageRequest(r)
+	if errs != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errs)
+		return
+	}
+
+	filter, errs := task.ParseFilter(r.URL.Query(), pageParams)
+	if errs != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errs)
+		return
+	}
+
+	filter = filter.With(task.Equals(task.FieldProjectID, id))
+
+	tasks, err := h.managementService.ListTasks(r.Context(), filter, page)
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		return
@@ -211,3 +381,238 @@ func (h *ProjectHandler) listTasks(w http.ResponseWriter, r *http.Request) {
 
 	render.JSON(w, r, tasks)
 }
+
+// @Summary List project members
+// @Description List the users working on a project and their roles
+// @Tags projects
+// @Param id path string true "Project ID"
+// @Success 200 {array} project.MemberResponse
+// @Failure 404 {string} string "Project not found"
+// @Security BearerAuth
+// @Failure 401 {string} string "Unauthorized"
+// @Router /projects/{id}/members [get]
+func (h *ProjectHandler) listMembers(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	members, err := h.managementService.ListProjectMembers(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, project.ErrNotFound) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	render.JSON(w, r, members)
+}
+
+// @Summary Add a project member
+// @Description Add a user to a project as manager, developer or viewer
+// @Tags projects
+// @Accept json
+// @Param id path string true "Project ID"
+// @Param body body project.MemberRequest true "Member request"
+// @Success 201 {string} string "Member added"
+// @Failure 400 {object} []domain.ErrorResponse "Validation errors"
+// @Failure 404 {string} string "Project or user not found"
+// @Failure 409 {string} string "Already a member"
+// @Security BearerAuth
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Router /projects/{id}/members [post]
+func (h *ProjectHandler) addMember(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	req := project.MemberRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if errs := req.Validate(); errs != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errs)
+		return
+	}
+
+	err := h.managementService.AddProjectMember(r.Context(), id, req)
+	if err != nil {
+		if writeAccessError(w, err) {
+			return
+		}
+
+		switch {
+		case errors.Is(err, project.ErrNotFound), errors.Is(err, user.ErrNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, project.ErrMemberExists):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+}
+
+// @Summary Remove a project member
+// @Description Remove a user from a project, the manager of the project can not be removed
+// @Tags projects
+// @Param id path string true "Project ID"
+// @Param userID path string true "User ID"
+// @Success 200 {string} string "Member removed"
+// @Failure 404 {string} string "Project or member not found"
+// @Failure 409 {string} string "The user manages the project"
+// @Security BearerAuth
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Router /projects/{id}/members/{userID} [delete]
+func (h *ProjectHandler) removeMember(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	userID := chi.URLParam(r, "userID")
+
+	err := h.managementService.RemoveProjectMember(r.Context(), id, userID)
+	if err != nil {
+		if writeAccessError(w, err) {
+			return
+		}
+
+		switch {
+		case errors.Is(err, project.ErrNotFound), errors.Is(err, project.ErrMemberNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, project.ErrRemoveManager):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// @Summary Get the project workflow
+// @Description Get the statuses and transitions tasks of the project follow, projects without their own workflow use the default one
+// @Tags projects
+// @Param id path string true "Project ID"
+// @Success 200 {object} task.Workflow
+// @Failure 404 {string} string "Project not found"
+// @Security BearerAuth
+// @Failure 401 {string} string "Unauthorized"
+// @Router /projects/{id}/workflow [get]
+func (h *ProjectHandler) getWorkflow(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	workflow, err := h.managementService.GetProjectWorkflow(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, project.ErrNotFound) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	render.JSON(w, r, workflow)
+}
+
+// @Summary Update the project workflow
+// @Description Replace the workflow of the project, statuses still used by its tasks can not be removed
+// @Tags projects
+// @Accept json
+// @Param id path string true "Project ID"
+// @Param body body task.Workflow true "Workflow"
+// @Success 200 {string} string "Workflow updated"
+// @Failure 400 {object} []domain.ErrorResponse "Validation errors"
+// @Failure 404 {string} string "Project not found"
+// @Failure 409 {string} string "A removed status is still in use"
+// @Security BearerAuth
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Router /projects/{id}/workflow [put]
+func (h *ProjectHandler) updateWorkflow(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	req := task.Workflow{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if errs := req.Validate(); errs != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errs)
+		return
+	}
+
+	err := h.managementService.UpdateProjectWorkflow(r.Context(), id, req)
+	if err != nil {
+		if writeAccessError(w, err) {
+			return
+		}
+
+		switch {
+		case errors.Is(err, project.ErrNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, task.ErrStatusInUse):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// @Summary List deleted projects
+// @Description List the projects in the trash, admins only
+// @Tags projects
+// @Param limit query int false "Page size, 20 by default and 100 at most"
+// @Param cursor query string false "Cursor returned as next_cursor by the previous page"
+// @Param offset query int false "Number of rows to skip, can not be combined with cursor"
+// @Success 200 {object} domain.Page[project.Response]
+// @Failure 400 {string} string "Bad request"
+// @Security BearerAuth
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Router /projects/trash [get]
+func (h *ProjectHandler) trash(w http.ResponseWriter, r *http.Request) {
+	h.list(w, r)
+}
+
+// @Summary Restore a project
+// @Description Restore a deleted project, the tasks deleted along with it come back too
+// @Tags projects
+// @Param id path string true "Project ID"
+// @Success 200 {string} string "Project restored"
+// @Failure 400 {string} string "Bad request"
+// @Failure 404 {string} string "Project not found"
+// @Failure 409 {string} string "Project is not deleted"
+// @Security BearerAuth
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Router /projects/{id}/restore [post]
+func (h *ProjectHandler) restore(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	err := h.managementService.RestoreProject(r.Context(), id)
+	if err != nil {
+		if writeAccessError(w, err) || writeRestoreError(w, err) {
+			return
+		}
+
+		if errors.Is(err, project.ErrNotFound) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+}
@@ -0,0 +1,79 @@
+package httphandler
+
+import (
+	"encoding/json"
+	"net/http"
+	"project-management/internal/domain/search"
+	"project-management/internal/service/management"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+type SearchHandler struct {
+	managementService *management.Service
+}
+
+func NewSearchHandler(managementService *management.Service) *SearchHandler {
+	return &SearchHandler{
+		managementService: managementService,
+	}
+}
+
+func (h *SearchHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/", h.search)
+
+	return r
+}
+
+// @Summary Search everything
+// @Description Full-text search over task titles and descriptions, project titles and descriptions and user names and emails.
+// @Description Hits of all types are merged and ordered by rank.
+// @Tags search
+// @Param q query string true "Search query, supports quoted phrases, OR and -exclusions"
+// @Param type query string false "Restrict to task, project or user, may be repeated or comma separated"
+// @Param limit query int false "Maximum number of results, 20 by default and 100 at most"
+// @Success 200 {object} search.Response
+// @Failure 400 {object} []domain.ErrorResponse "Validation errors"
+// @Security BearerAuth
+// @Failure 401 {string} string "Unauthorized"
+// @Router /search [get]
+func (h *SearchHandler) search(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	req := search.Request{
+		Query: strings.TrimSpace(q.Get("q")),
+	}
+
+	for _, t := range q["type"] {
+		req.Types = append(req.Types, strings.Split(t, ",")...)
+	}
+
+	if v := q.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		req.Limit = limit
+	}
+
+	if errs := req.Validate(); errs != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errs)
+		return
+	}
+
+	res, err := h.managementService.Search(r.Context(), req)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	render.JSON(w, r, res)
+}
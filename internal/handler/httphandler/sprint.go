@@ -0,0 +1,348 @@
+package httphandler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	_ "project-management/internal/domain" // resolves domain.Page in swagger annotations
+	"project-management/internal/domain/project"
+	"project-management/internal/domain/sprint"
+	"project-management/internal/domain/task"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// @Summary List project sprints
+// @Description List the sprints of the project ordered by start date
+// @Tags projects
+// @Param id path string true "Project ID"
+// @Success 200 {object} []sprint.Response
+// @Failure 404 {string} string "Project not found"
+// @Security BearerAuth
+// @Failure 401 {string} string "Unauthorized"
+// @Router /projects/{id}/sprints [get]
+func (h *ProjectHandler) listSprints(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	sprints, err := h.managementService.ListSprints(r.Context(), id)
+	if err != nil {
+		if writeSprintError(w, err) {
+			return
+		}
+
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	render.JSON(w, r, sprints)
+}
+
+// @Summary Create a sprint
+// @Description Plan a new sprint of the project
+// @Tags projects
+// @Accept json
+// @Param id path string true "Project ID"
+// @Param body body sprint.Request true "Sprint request"
+// @Success 201 {string} string "Sprint ID"
+// @Failure 400 {object} []domain.ErrorResponse "Validation errors"
+// @Failure 404 {string} string "Project not found"
+// @Security BearerAuth
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Router /projects/{id}/sprints [post]
+func (h *ProjectHandler) createSprint(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	req := sprint.Request{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if errs := req.Validate(); errs != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errs)
+		return
+	}
+
+	sprintID, err := h.managementService.CreateSprint(r.Context(), id, req)
+	if err != nil {
+		if writeAccessError(w, err) || writeSprintError(w, err) {
+			return
+		}
+
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.PlainText(w, r, sprintID)
+}
+
+// @Summary Get a sprint
+// @Description Get a sprint of the project, list its tasks with GET /projects/{id}/tasks?sprint_id=
+// @Tags projects
+// @Param id path string true "Project ID"
+// @Param sprintID path string true "Sprint ID"
+// @Success 200 {object} sprint.Response
+// @Failure 404 {string} string "Project or sprint not found"
+// @Security BearerAuth
+// @Failure 401 {string} string "Unauthorized"
+// @Router /projects/{id}/sprints/{sprintID} [get]
+func (h *ProjectHandler) getSprint(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	sprintID := chi.URLParam(r, "sprintID")
+
+	res, err := h.managementService.GetSprint(r.Context(), id, sprintID)
+	if err != nil {
+		if writeSprintError(w, err) {
+			return
+		}
+
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	render.JSON(w, r, res)
+}
+
+// @Summary Update a sprint
+// @Description Change the name, goal or dates of a sprint that is not closed
+// @Tags projects
+// @Accept json
+// @Param id path string true "Project ID"
+// @Param sprintID path string true "Sprint ID"
+// @Param body body sprint.UpdateRequest true "Sprint request"
+// @Success 200 {string} string "Sprint updated"
+// @Failure 400 {object} []domain.ErrorResponse "Validation errors"
+// @Failure 404 {string} string "Project or sprint not found"
+// @Failure 409 {string} string "Sprint is closed"
+// @Security BearerAuth
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Router /projects/{id}/sprints/{sprintID} [put]
+func (h *ProjectHandler) updateSprint(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	sprintID := chi.URLParam(r, "sprintID")
+
+	req := sprint.UpdateRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if errs := req.Validate(); errs != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errs)
+		return
+	}
+
+	err := h.managementService.UpdateSprint(r.Context(), id, sprintID, req)
+	if err != nil {
+		if writeAccessError(w, err) || writeSprintError(w, err) {
+			return
+		}
+
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// @Summary Delete a sprint
+// @Description Delete a sprint, its tasks go back to the backlog
+// @Tags projects
+// @Param id path string true "Project ID"
+// @Param sprintID path string true "Sprint ID"
+// @Success 200 {string} string "Sprint deleted"
+// @Failure 404 {string} string "Project or sprint not found"
+// @Security BearerAuth
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Router /projects/{id}/sprints/{sprintID} [delete]
+func (h *ProjectHandler) deleteSprint(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	sprintID := chi.URLParam(r, "sprintID")
+
+	err := h.managementService.DeleteSprint(r.Context(), id, sprintID)
+	if err != nil {
+		if writeAccessError(w, err) || writeSprintError(w, err) {
+			return
+		}
+
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// @Summary Start a sprint
+// @Description Activate a planned sprint, a project has at most one active sprint
+// @Tags projects
+// @Param id path string true "Project ID"
+// @Param sprintID path string true "Sprint ID"
+// @Success 200 {string} string "Sprint started"
+// @Failure 404 {string} string "Project or sprint not found"
+// @Failure 409 {string} string "Sprint is not planned or another sprint is active"
+// @Security BearerAuth
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Router /projects/{id}/sprints/{sprintID}/start [post]
+func (h *ProjectHandler) startSprint(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	sprintID := chi.URLParam(r, "sprintID")
+
+	err := h.managementService.StartSprint(r.Context(), id, sprintID)
+	if err != nil {
+		if writeAccessError(w, err) || writeSprintError(w, err) {
+			return
+		}
+
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// @Summary Close a sprint
+// @Description Close the active sprint, tasks that are not done move to the planned sprint next_sprint_id or back to the backlog when it is empty
+// @Tags projects
+// @Accept json
+// @Param id path string true "Project ID"
+// @Param sprintID path string true "Sprint ID"
+// @Param body body sprint.CloseRequest false "Close request"
+// @Success 200 {object} sprint.CloseResponse
+// @Failure 400 {string} string "Next sprint is not a planned sprint of the project"
+// @Failure 404 {string} string "Project or sprint not found"
+// @Failure 409 {string} string "Sprint is not active"
+// @Security BearerAuth
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Router /projects/{id}/sprints/{sprintID}/close [post]
+func (h *ProjectHandler) closeSprint(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	sprintID := chi.URLParam(r, "sprintID")
+
+	// the body is optional, without it the unfinished tasks go to the backlog
+	req := sprint.CloseRequest{}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+	}
+
+	res, err := h.managementService.CloseSprint(r.Context(), id, sprintID, req)
+	if err != nil {
+		if writeAccessError(w, err) || writeSprintError(w, err) {
+			return
+		}
+
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	render.JSON(w, r, res)
+}
+
+// @Summary List the project backlog
+// @Description List the tasks of the project that are in no sprint, accepts the same filters as the task search
+// @Tags projects
+// @Param id path string true "Project ID"
+// @Param limit query int false "Page size, 20 by default and 100 at most"
+// @Param cursor query string false "Cursor returned as next_cursor by the previous page"
+// @Param offset query int false "Number of rows to skip, can not be combined with cursor"
+// @Success 200 {object} domain.Page[task.Response]
+// @Failure 400 {string} string "Bad request"
+// @Security BearerAuth
+// @Failure 401 {string} string "Unauthorized"
+// @Router /projects/{id}/backlog [get]
+func (h *ProjectHandler) listBacklog(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	page, errs := parsePageRequest(r)
+	if errs != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errs)
+		return
+	}
+
+	filter, errs := task.ParseFilter(r.URL.Query(), pageParams)
+	if errs != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errs)
+		return
+	}
+
+	// an empty sprint is the backlog
+	filter = filter.With(task.Equals(task.FieldProjectID, id), task.Equals(task.FieldSprintID, ""))
+
+	tasks, err := h.managementService.ListTasks(r.Context(), filter, page)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	render.JSON(w, r, tasks)
+}
+
+// @Summary Schedule a task
+// @Description Put a task into an open sprint of its project, an empty sprint_id moves it back to the backlog
+// @Tags tasks
+// @Accept json
+// @Param id path string true "Task ID"
+// @Param body body sprint.ScheduleRequest true "Schedule request"
+// @Success 200 {string} string "Task scheduled"
+// @Failure 400 {string} string "Sprint belongs to another project"
+// @Failure 404 {string} string "Task or sprint not found"
+// @Failure 409 {string} string "Sprint is closed"
+// @Security BearerAuth
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Router /tasks/{id}/sprint [put]
+func (h *TaskHandler) schedule(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	req := sprint.ScheduleRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	err := h.managementService.ScheduleTask(r.Context(), id, req)
+	if err != nil {
+		if writeAccessError(w, err) || writeSprintError(w, err) {
+			return
+		}
+
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// writeSprintError answers sprint failures reported by the service and tells
+// whether err was one of them.
+func writeSprintError(w http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, project.ErrNotFound), errors.Is(err, task.ErrNotFound), errors.Is(err, sprint.ErrNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, sprint.ErrOtherProject), errors.Is(err, sprint.ErrInvalidTarget), errors.Is(err, sprint.ErrInvalidDates):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, sprint.ErrActiveExists), errors.Is(err, sprint.ErrNotPlanned), errors.Is(err, sprint.ErrNotActive), errors.Is(err, sprint.ErrClosed):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,36 @@
+package httphandler
+
+import (
+	"errors"
+	"net/http"
+	"project-management/internal/domain/task"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// @Summary List subtasks
+// @Description Get the task with its subtasks nested at any depth, progress is the completion in percent rolled up from the subtasks
+// @Tags tasks
+// @Param id path string true "Task ID"
+// @Success 200 {object} task.TreeResponse
+// @Failure 404 {string} string "Task not found"
+// @Security BearerAuth
+// @Failure 401 {string} string "Unauthorized"
+// @Router /tasks/{id}/subtasks [get]
+func (h *TaskHandler) listSubtasks(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	tree, err := h.managementService.GetSubtasks(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, task.ErrNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	render.JSON(w, r, tree)
+}
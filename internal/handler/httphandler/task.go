@@ -4,6 +4,10 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	_ "project-management/internal/domain" // resolves domain.Page in swagger annotations
+	"project-management/internal/domain/milestone"
+	"project-management/internal/domain/project"
+	"project-management/internal/domain/sprint"
 	"project-management/internal/domain/task"
 	"project-management/internal/service/management"
 
@@ -23,28 +27,62 @@ func NewTaskHandler(managementService *management.Service) *TaskHandler {
 
 func (h *TaskHandler) Routes() chi.Router {
 	r := chi.NewRouter()
+	r.Use(includeDeleted(h.managementService))
 
 	r.Post("/", h.create)
 	r.Get("/", h.list)
+	r.Post("/bulk", h.bulk)
 
 	r.Route("/{id}", func(r chi.Router) {
 		r.Get("/", h.get)
 		r.Put("/", h.update)
+		r.Patch("/", h.patch)
 		r.Delete("/", h.delete)
+		r.Post("/restore", h.restore)
+		r.Post("/assign", h.assign)
+		r.Get("/history", h.history)
+
+		r.Get("/comments", h.listComments)
+		r.Post("/comments", h.createComment)
+		r.Put("/comments/{commentID}", h.updateComment)
+		r.Delete("/comments/{commentID}", h.deleteComment)
+
+		r.Get("/attachments", h.listAttachments)
+		r.Post("/attachments", h.uploadAttachment)
+		r.Get("/attachments/{attachmentID}", h.downloadAttachment)
+		r.Delete("/attachments/{attachmentID}", h.deleteAttachment)
+
+		r.Get("/labels", h.listLabels)
+		r.Put("/labels/{labelID}", h.attachLabel)
+		r.Delete("/labels/{labelID}", h.detachLabel)
+
+		r.Get("/dependencies", h.listDependencies)
+		r.Post("/dependencies", h.addDependency)
+		r.Delete("/dependencies/{blockerID}", h.removeDependency)
+
+		r.Get("/subtasks", h.listSubtasks)
+
+		r.Put("/sprint", h.schedule)
+		r.Put("/milestone", h.linkMilestone)
 	})
 
 	r.Get("/search", h.search)
+	r.Get("/trash", onlyDeleted(h.managementService, h.trash))
 
 	return r
 }
 
 // @Summary Create a task
-// @Description Create a task
+// @Description Create a task, the status defaults to the initial status of the project workflow
 // @Tags tasks
 // @Accept json
 // @Param body body task.Request true "Task request"
 // @Success 201 {string} string "Task ID"
 // @Failure 400 {object} []string "Validation errors"
+// @Failure 409 {string} string "Sprint is closed"
+// @Security BearerAuth
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
 // @Router /tasks [post]
 func (h *TaskHandler) create(w http.ResponseWriter, r *http.Request) {
 	req := task.Request{}
@@ -62,20 +100,77 @@ func (h *TaskHandler) create(w http.ResponseWriter, r *http.Request) {
 
 	id, err := h.managementService.CreateTask(r.Context(), req)
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
+		if writeAccessError(w, err) || writeWorkflowError(w, err) || writeHierarchyError(w, err) {
+			return
+		}
+
+		switch {
+		case errors.Is(err, project.ErrNotMember), errors.Is(err, sprint.ErrNotFound), errors.Is(err, sprint.ErrOtherProject),
+			errors.Is(err, milestone.ErrNotFound), errors.Is(err, milestone.ErrOtherProject):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, sprint.ErrClosed):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
 		return
 	}
 
 	render.PlainText(w, r, id)
 }
 
+// @Summary Run bulk task operations
+// @Description Create, update and delete up to 100 tasks in one transaction. In the all_or_nothing mode, the default, a failing operation cancels the batch and the response is a 400 listing what failed, per_item stores every operation that succeeds and reports the others in their results
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param body body task.BulkRequest true "Bulk request"
+// @Success 200 {object} task.BulkResponse
+// @Failure 400 {object} task.BulkResponse "Batch canceled, or validation errors"
+// @Security BearerAuth
+// @Failure 401 {string} string "Unauthorized"
+// @Router /tasks/bulk [post]
+func (h *TaskHandler) bulk(w http.ResponseWriter, r *http.Request) {
+	req := task.BulkRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if errs := req.Validate(); errs != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errs)
+		return
+	}
+
+	res, err := h.managementService.BulkTasks(r.Context(), req)
+	if err != nil {
+		if writeAccessError(w, err) {
+			return
+		}
+
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	if req.Mode != task.BulkPerItem && res.Failed > 0 {
+		render.Status(r, http.StatusBadRequest)
+	}
+
+	render.JSON(w, r, res)
+}
+
 // @Summary Get a task
 // @Description Get a task
 // @Tags tasks
 // @Accept json
 // @Param id path string true "Task ID"
 // @Success 200 {object} task.Response
+// @Header 200 {string} ETag "Version of the task, send it as If-Match to change the task"
 // @Failure 400 {string} string "Bad request"
+// @Security BearerAuth
+// @Failure 401 {string} string "Unauthorized"
 // @Router /tasks/{id} [get]
 func (h *TaskHandler) get(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
@@ -86,17 +181,57 @@ func (h *TaskHandler) get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	setETag(w, task.Version)
 	render.JSON(w, r, task)
 }
 
 // @Summary List tasks
-// @Description List tasks
+// @Description List tasks, optionally narrowed with the same filters as search
 // @Tags tasks
-// @Success 200 {object} []task.Response
+// @Param status query string false "Status, repeat the parameter or use status[in]=a,b to match any of several"
+// @Param priority query string false "Priority, repeat the parameter or use priority[in]=a,b to match any of several"
+// @Param project_id query string false "Project ID"
+// @Param parent_id query string false "Parent task ID"
+// @Param sprint_id query string false "Sprint ID"
+// @Param milestone_id query string false "Milestone ID"
+// @Param author_id query string false "Author ID"
+// @Param assignee_id query string false "Assignee ID"
+// @Param title[contains] query string false "Case insensitive substring of the title"
+// @Param description[contains] query string false "Case insensitive substring of the description"
+// @Param created_at[gte] query string false "Created on or after the date, also written as created_at>=2024-01-01"
+// @Param created_at[lte] query string false "Created on or before the date, also written as created_at<=2024-01-01"
+// @Param due_date[gte] query string false "Due on or after the date"
+// @Param due_date[lte] query string false "Due on or before the date"
+// @Param completed_at[gte] query string false "Completed on or after the date"
+// @Param completed_at[lte] query string false "Completed on or before the date"
+// @Param overdue query bool false "Only open tasks past their due date for true, every other task for false"
+// @Param label query string false "Label name, repeat the parameter or use label[in]=a,b to match any of several and label[all]=a,b to require all of them"
+// @Param limit query int false "Page size, 20 by default and 100 at most"
+// @Param cursor query string false "Cursor returned as next_cursor by the previous page"
+// @Param offset query int false "Number of rows to skip, can not be combined with cursor"
+// @Success 200 {object} domain.Page[task.Response]
 // @Failure 400 {string} string "Bad request"
+// @Security BearerAuth
+// @Failure 401 {string} string "Unauthorized"
 // @Router /tasks [get]
 func (h *TaskHandler) list(w http.ResponseWriter, r *http.Request) {
-	tasks, err := h.managementService.ListTasks(r.Context())
+	page, errs := parsePageRequest(r)
+	if errs != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errs)
+		return
+	}
+
+	filter, errs := task.ParseFilter(r.URL.Query(), pageParams)
+	if errs != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errs)
+		return
+	}
+
+	tasks, err := h.managementService.ListTasks(r.Context(), filter, page)
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		return
@@ -106,17 +241,29 @@ func (h *TaskHandler) list(w http.ResponseWriter, r *http.Request) {
 }
 
 // @Summary Update a task
-// @Description Update a task
+// @Description Update a task, status changes have to follow the workflow of the project and entering a terminal status sets completed_at and requires every blocker to be done
 // @Tags tasks
 // @Accept json
 // @Param id path string true "Task ID"
+// @Param If-Match header string true "ETag of the task the update is based on, * for any version"
 // @Param body body task.UpdateRequest true "Task update request"
 // @Success 200 {string} string "Task updated"
 // @Failure 400 {string} string "Bad request"
+// @Failure 409 {string} string "Status transition not allowed by the workflow, blocked by unfinished tasks or parent change would break the task tree"
+// @Failure 412 {string} string "The task has been changed since"
+// @Failure 428 {string} string "If-Match header missing"
+// @Security BearerAuth
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
 // @Router /tasks/{id} [put]
 func (h *TaskHandler) update(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
+	version, ok := parseIfMatch(w, r)
+	if !ok {
+		return
+	}
+
 	req := task.UpdateRequest{}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		w.WriteHeader(http.StatusBadRequest)
@@ -130,8 +277,72 @@ func (h *TaskHandler) update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err := h.managementService.UpdateTask(r.Context(), id, req)
+	err := h.managementService.UpdateTask(r.Context(), id, version, req)
+	if err != nil {
+		if writeAccessError(w, err) || writeVersionError(w, err) || writeWorkflowError(w, err) || writeHierarchyError(w, err) {
+			return
+		}
+
+		if errors.Is(err, project.ErrNotMember) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// @Summary Patch a task
+// @Description Apply a JSON Merge Patch (RFC 7396) to a task, absent fields stay as they are and null clears description, parent_id, due_date or estimate_hours. Status changes follow the same rules as with PUT.
+// @Tags tasks
+// @Accept json
+// @Param id path string true "Task ID"
+// @Param If-Match header string true "ETag of the task the patch is based on, * for any version"
+// @Param body body task.PatchRequest true "Task merge patch"
+// @Success 200 {string} string "Task updated"
+// @Failure 400 {string} string "Bad request"
+// @Failure 409 {string} string "Status transition not allowed by the workflow, blocked by unfinished tasks or parent change would break the task tree"
+// @Failure 412 {string} string "The task has been changed since"
+// @Failure 428 {string} string "If-Match header missing"
+// @Security BearerAuth
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Router /tasks/{id} [patch]
+func (h *TaskHandler) patch(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	version, ok := parseIfMatch(w, r)
+	if !ok {
+		return
+	}
+
+	req := task.PatchRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if errs := req.Validate(); errs != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errs)
+		return
+	}
+
+	err := h.managementService.PatchTask(r.Context(), id, version, req)
 	if err != nil {
+		if writeAccessError(w, err) || writeVersionError(w, err) || writeWorkflowError(w, err) || writeHierarchyError(w, err) {
+			return
+		}
+
+		if errors.Is(err, project.ErrNotMember) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
@@ -139,18 +350,101 @@ func (h *TaskHandler) update(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 }
 
+// @Summary Assign a task
+// @Description Hand a task to a member of its project, an empty assignee_id unassigns it
+// @Tags tasks
+// @Accept json
+// @Param id path string true "Task ID"
+// @Param body body task.AssignRequest true "Assign request"
+// @Success 200 {string} string "Task assigned"
+// @Failure 400 {string} string "Assignee is not a project member"
+// @Failure 404 {string} string "Task not found"
+// @Failure 403 {string} string "Forbidden"
+// @Security BearerAuth
+// @Failure 401 {string} string "Unauthorized"
+// @Router /tasks/{id}/assign [post]
+func (h *TaskHandler) assign(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	req := task.AssignRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	err := h.managementService.AssignTask(r.Context(), id, req)
+	if err != nil {
+		if writeAccessError(w, err) {
+			return
+		}
+
+		switch {
+		case errors.Is(err, task.ErrNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, project.ErrNotMember):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// @Summary Task history
+// @Description List the changes of a task, oldest first
+// @Tags tasks
+// @Param id path string true "Task ID"
+// @Success 200 {array} task.EventResponse
+// @Failure 404 {string} string "Task not found"
+// @Security BearerAuth
+// @Failure 401 {string} string "Unauthorized"
+// @Router /tasks/{id}/history [get]
+func (h *TaskHandler) history(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	events, err := h.managementService.GetTaskHistory(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, task.ErrNotFound) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	render.JSON(w, r, events)
+}
+
 // @Summary Delete a task
 // @Description Delete a task
 // @Tags tasks
 // @Param id path string true "Task ID"
+// @Param If-Match header string true "ETag of the task the deletion is based on, * for any version"
 // @Success 200 {string} string "Task deleted"
 // @Failure 400 {string} string "Bad request"
+// @Failure 412 {string} string "The task has been changed since"
+// @Failure 428 {string} string "If-Match header missing"
+// @Security BearerAuth
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
 // @Router /tasks/{id} [delete]
 func (h *TaskHandler) delete(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
-	err := h.managementService.DeleteTask(r.Context(), id)
+	version, ok := parseIfMatch(w, r)
+	if !ok {
+		return
+	}
+
+	err := h.managementService.DeleteTask(r.Context(), id, version)
 	if err != nil {
+		if writeAccessError(w, err) || writeVersionError(w, err) {
+			return
+		}
+
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
@@ -159,22 +453,55 @@ func (h *TaskHandler) delete(w http.ResponseWriter, r *http.Request) {
 }
 
 // @Summary Search tasks
-// @Description Search tasks
+// @Description Search tasks by one or more filters, all of them must match.
+// @Description A filter is written as field=value, field[op]=value, field>=value or field<=value.
+// @Description Operators are in, gte, lte and contains.
 // @Tags tasks
-// @Param query query string true "Query"
-// @Param value query string true "Value"
-// @Success 200 {object} []task.Response
+// @Param status query string false "Status, repeat the parameter or use status[in]=a,b to match any of several"
+// @Param priority query string false "Priority, repeat the parameter or use priority[in]=a,b to match any of several"
+// @Param project_id query string false "Project ID"
+// @Param parent_id query string false "Parent task ID"
+// @Param sprint_id query string false "Sprint ID"
+// @Param milestone_id query string false "Milestone ID"
+// @Param author_id query string false "Author ID"
+// @Param assignee_id query string false "Assignee ID"
+// @Param title[contains] query string false "Case insensitive substring of the title"
+// @Param description[contains] query string false "Case insensitive substring of the description"
+// @Param created_at[gte] query string false "Created on or after the date, also written as created_at>=2024-01-01"
+// @Param created_at[lte] query string false "Created on or before the date, also written as created_at<=2024-01-01"
+// @Param due_date[gte] query string false "Due on or after the date"
+// @Param due_date[lte] query string false "Due on or before the date"
+// @Param completed_at[gte] query string false "Completed on or after the date"
+// @Param completed_at[lte] query string false "Completed on or before the date"
+// @Param overdue query bool false "Only open tasks past their due date for true, every other task for false"
+// @Param label query string false "Label name, repeat the parameter or use label[in]=a,b to match any of several and label[all]=a,b to require all of them"
+// @Param limit query int false "Page size, 20 by default and 100 at most"
+// @Param cursor query string false "Cursor returned as next_cursor by the previous page"
+// @Param offset query int false "Number of rows to skip, can not be combined with cursor"
+// @Success 200 {object} domain.Page[task.Response]
 // @Failure 400 {string} string "Bad request"
 // @Failure 404 {string} string "Not found"
+// @Security BearerAuth
+// @Failure 401 {string} string "Unauthorized"
 // @Router /tasks/search [get]
 func (h *TaskHandler) search(w http.ResponseWriter, r *http.Request) {
-	filter, val := "", ""
+	page, errs := parsePageRequest(r)
+	if errs != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errs)
+		return
+	}
 
-	for k, v := range r.URL.Query() {
-		filter, val = k, v[0]
+	filter, errs := task.ParseFilter(r.URL.Query(), pageParams)
+	if errs != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errs)
+		return
 	}
 
-	tasks, err := h.managementService.SearchTasks(r.Context(), filter, val)
+	tasks, err := h.managementService.SearchTasks(r.Context(), filter, page)
 	if err != nil {
 		if errors.Is(err, task.ErrSearch) {
 			w.WriteHeader(http.StatusBadRequest)
@@ -192,3 +519,50 @@ func (h *TaskHandler) search(w http.ResponseWriter, r *http.Request) {
 
 	render.JSON(w, r, tasks)
 }
+
+// @Summary List deleted tasks
+// @Description List the tasks in the trash, admins only
+// @Tags tasks
+// @Param limit query int false "Page size, 20 by default and 100 at most"
+// @Param cursor query string false "Cursor returned as next_cursor by the previous page"
+// @Param offset query int false "Number of rows to skip, can not be combined with cursor"
+// @Success 200 {object} domain.Page[task.Response]
+// @Failure 400 {string} string "Bad request"
+// @Security BearerAuth
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Router /tasks/trash [get]
+func (h *TaskHandler) trash(w http.ResponseWriter, r *http.Request) {
+	h.list(w, r)
+}
+
+// @Summary Restore a task
+// @Description Restore a deleted task, its project must not be deleted
+// @Tags tasks
+// @Param id path string true "Task ID"
+// @Success 200 {string} string "Task restored"
+// @Failure 400 {string} string "Bad request"
+// @Failure 404 {string} string "Task not found"
+// @Failure 409 {string} string "Task is not deleted or its project is"
+// @Security BearerAuth
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Router /tasks/{id}/restore [post]
+func (h *TaskHandler) restore(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	err := h.managementService.RestoreTask(r.Context(), id)
+	if err != nil {
+		if writeAccessError(w, err) || writeRestoreError(w, err) {
+			return
+		}
+
+		if errors.Is(err, task.ErrNotFound) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+}
@@ -0,0 +1,66 @@
+package httphandler
+
+import (
+	"errors"
+	"net/http"
+	"project-management/internal/domain"
+	"project-management/internal/domain/task"
+	"project-management/internal/domain/user"
+	"project-management/internal/service/management"
+)
+
+// includeDeleted makes the reads of the request return soft deleted rows as
+// well when include_deleted=true is passed, which only admins may do.
+func includeDeleted(s *management.Service) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("include_deleted") != "true" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx, err := s.ScopeDeleted(r.Context(), domain.IncludeDeleted)
+			if err != nil {
+				if writeAccessError(w, err) {
+					return
+				}
+
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// onlyDeleted serves next with the soft deleted rows only, it backs the trash
+// listings.
+func onlyDeleted(s *management.Service, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, err := s.ScopeDeleted(r.Context(), domain.OnlyDeleted)
+		if err != nil {
+			if writeAccessError(w, err) {
+				return
+			}
+
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		next(w, r.WithContext(ctx))
+	}
+}
+
+// writeRestoreError answers restores of rows that are not in the trash or
+// whose parent still is and tells whether err was one of them.
+func writeRestoreError(w http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, domain.ErrNotDeleted), errors.Is(err, task.ErrProjectDeleted), errors.Is(err, user.ErrEmailTaken):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		return false
+	}
+
+	return true
+}
@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"project-management/internal/domain"
+	"project-management/internal/domain/task"
 	"project-management/internal/domain/user"
 	"project-management/internal/service/management"
 
@@ -21,16 +23,20 @@ func NewUserHandler(s *management.Service) *UserHandler {
 
 func (h *UserHandler) Routes() chi.Router {
 	r := chi.NewRouter()
+	r.Use(includeDeleted(h.managementService))
 
 	r.Get("/", h.list)
 	r.Post("/", h.create)
 
 	r.Get("/search", h.search)
+	r.Get("/trash", onlyDeleted(h.managementService, h.trash))
 
 	r.Route("/{id}", func(r chi.Router) {
 		r.Get("/", h.get)
 		r.Put("/", h.update)
+		r.Patch("/", h.patch)
 		r.Delete("/", h.delete)
+		r.Post("/restore", h.restore)
 		r.Get("/tasks", h.listTasks)
 	})
 
@@ -41,11 +47,24 @@ func (h *UserHandler) Routes() chi.Router {
 // @Description List users
 // @Tags users
 // @Accept json
-// @Success 200 {array} user.Response
+// @Param limit query int false "Page size, 20 by default and 100 at most"
+// @Param cursor query string false "Cursor returned as next_cursor by the previous page"
+// @Param offset query int false "Number of rows to skip, can not be combined with cursor"
+// @Success 200 {object} domain.Page[user.Response]
 // @Failure 400 {object} string
+// @Security BearerAuth
+// @Failure 401 {string} string "Unauthorized"
 // @Router /users [get]
 func (h *UserHandler) list(w http.ResponseWriter, r *http.Request) {
-	users, err := h.managementService.ListUsers(r.Context())
+	page, errs := parsePageRequest(r)
+	if errs != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errs)
+		return
+	}
+
+	users, err := h.managementService.ListUsers(r.Context(), page)
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		return
@@ -61,6 +80,9 @@ func (h *UserHandler) list(w http.ResponseWriter, r *http.Request) {
 // @Param body body user.Request true "User request"
 // @Success 201 {string} string "User ID"
 // @Failure 400 {object} []string "Validation errors"
+// @Security BearerAuth
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
 // @Router /users [post]
 func (h *UserHandler) create(w http.ResponseWriter, r *http.Request) {
 	req := user.Request{}
@@ -78,6 +100,10 @@ func (h *UserHandler) create(w http.ResponseWriter, r *http.Request) {
 
 	id, err := h.managementService.CreateUser(r.Context(), req)
 	if err != nil {
+		if writeAccessError(w, err) {
+			return
+		}
+
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
@@ -91,8 +117,11 @@ func (h *UserHandler) create(w http.ResponseWriter, r *http.Request) {
 // @Accept json
 // @Param id path string true "User ID"
 // @Success 200 {object} user.Response
+// @Header 200 {string} ETag "Version of the user, send it as If-Match to change the user"
 // @Failure 400 {string} string "Bad request"
 // @Failure 404 {string} string "User not found"
+// @Security BearerAuth
+// @Failure 401 {string} string "Unauthorized"
 // @Router /users/{id} [get]
 func (h *UserHandler) get(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
@@ -107,6 +136,7 @@ func (h *UserHandler) get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	setETag(w, data.Version)
 	render.JSON(w, r, data)
 }
 
@@ -115,14 +145,25 @@ func (h *UserHandler) get(w http.ResponseWriter, r *http.Request) {
 // @Tags users
 // @Accept json
 // @Param id path string true "User ID"
+// @Param If-Match header string true "ETag of the user the update is based on, * for any version"
 // @Param body body user.UpdateRequest true "User request"
 // @Success 200 {string} string "User ID"
 // @Failure 400 {object} []string "Validation errors"
 // @Failure 404 {string} string "User not found"
+// @Failure 412 {string} string "The user has been changed since"
+// @Failure 428 {string} string "If-Match header missing"
+// @Security BearerAuth
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
 // @Router /users/{id} [put]
 func (h *UserHandler) update(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
+	version, ok := parseIfMatch(w, r)
+	if !ok {
+		return
+	}
+
 	req := user.UpdateRequest{}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		w.WriteHeader(http.StatusBadRequest)
@@ -136,8 +177,12 @@ func (h *UserHandler) update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err := h.managementService.UpdateUser(r.Context(), id, req)
+	err := h.managementService.UpdateUser(r.Context(), id, version, req)
 	if err != nil {
+		if writeAccessError(w, err) || writeVersionError(w, err) {
+			return
+		}
+
 		if errors.Is(err, user.ErrNotFound) {
 			w.WriteHeader(http.StatusNotFound)
 		}
@@ -147,49 +192,168 @@ func (h *UserHandler) update(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// @Summary Patch a user
+// @Description Apply a JSON Merge Patch (RFC 7396) to a user, absent fields stay as they are and none can be cleared
+// @Tags users
+// @Accept json
+// @Param id path string true "User ID"
+// @Param If-Match header string true "ETag of the user the patch is based on, * for any version"
+// @Param body body user.PatchRequest true "User merge patch"
+// @Success 200 {string} string "User updated"
+// @Failure 400 {object} []string "Validation errors"
+// @Failure 404 {string} string "User not found"
+// @Failure 412 {string} string "The user has been changed since"
+// @Failure 428 {string} string "If-Match header missing"
+// @Security BearerAuth
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Router /users/{id} [patch]
+func (h *UserHandler) patch(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	version, ok := parseIfMatch(w, r)
+	if !ok {
+		return
+	}
+
+	req := user.PatchRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if errs := req.Validate(); errs != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errs)
+		return
+	}
+
+	err := h.managementService.PatchUser(r.Context(), id, version, req)
+	if err != nil {
+		if writeAccessError(w, err) || writeVersionError(w, err) {
+			return
+		}
+
+		if errors.Is(err, user.ErrNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+}
+
 // @Summary Delete a user
-// @Description Delete a user
+// @Description Delete a user. The projects the user manages and the tasks they author or are assigned to pass to reassign_to, who joins their projects if needed. Without reassign_to a user who still manages a project or owns an open task is not deleted
 // @Tags users
 // @Accept json
 // @Param id path string true "User ID"
+// @Param reassign_to query string false "ID of the user taking over the projects and tasks"
+// @Param If-Match header string true "ETag of the user the deletion is based on, * for any version"
 // @Success 200 {string} string "User deleted"
 // @Failure 400 {string} string "Bad request"
 // @Failure 404 {string} string "User not found"
+// @Failure 409 {string} string "The user still owns work and reassign_to is missing"
+// @Failure 412 {string} string "The user or their work has been changed since"
+// @Failure 428 {string} string "If-Match header missing"
+// @Security BearerAuth
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
 // @Router /users/{id} [delete]
 func (h *UserHandler) delete(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
-	err := h.managementService.DeleteUser(r.Context(), id)
+	version, ok := parseIfMatch(w, r)
+	if !ok {
+		return
+	}
+
+	err := h.managementService.DeleteUser(r.Context(), id, version, r.URL.Query().Get("reassign_to"))
 	if err != nil {
-		if errors.Is(err, user.ErrNotFound) {
-			w.WriteHeader(http.StatusNotFound)
+		if writeAccessError(w, err) || writeVersionError(w, err) {
 			return
 		}
 
-		w.WriteHeader(http.StatusBadRequest)
+		switch {
+		case errors.Is(err, user.ErrNotFound):
+			w.WriteHeader(http.StatusNotFound)
+		case errors.Is(err, user.ErrOwnsWork):
+			http.Error(w, err.Error(), http.StatusConflict)
+		case errors.Is(err, user.ErrReassignTarget):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
 		return
 	}
 }
 
 // @Summary List user tasks
-// @Description List user tasks
+// @Description List tasks assigned to or authored by the user, accepts the same filters as the task search
 // @Tags users
 // @Accept json
 // @Param id path string true "User ID"
-// @Success 200 {array} task.Response
+// @Param relation query string false "assigned (default) or authored"
+// @Param limit query int false "Page size, 20 by default and 100 at most"
+// @Param cursor query string false "Cursor returned as next_cursor by the previous page"
+// @Param offset query int false "Number of rows to skip, can not be combined with cursor"
+// @Success 200 {object} domain.Page[task.Response]
 // @Failure 400 {string} string "Bad request"
 // @Failure 404 {string} string "User not found"
+// @Security BearerAuth
+// @Failure 401 {string} string "Unauthorized"
 // @Router /users/{id}/tasks [get]
 func (h *UserHandler) listTasks(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
-	tasks, err := h.managementService.SearchTasks(r.Context(), "user_id", id)
-	if err != nil {
+	if _, err := h.managementService.GetUser(r.Context(), id); err != nil {
 		if errors.Is(err, user.ErrNotFound) {
 			w.WriteHeader(http.StatusNotFound)
+			return
 		}
 
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	page, errs := parsePageRequest(r)
+	if errs != nil {
+		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errs)
+		return
+	}
+
+	skip := map[string]bool{"relation": true}
+	for k := range pageParams {
+		skip[k] = true
+	}
+
+	filter, errs := task.ParseFilter(r.URL.Query(), skip)
+	if errs != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errs)
+		return
+	}
+
+	switch r.URL.Query().Get("relation") {
+	case "", "assigned":
+		filter = filter.With(task.Equals(task.FieldAssigneeID, id))
+	case "authored":
+		filter = filter.With(task.Equals(task.FieldAuthorID, id))
+	default:
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode([]domain.ErrorResponse{{Message: "relation must be assigned or authored", Field: "relation"}})
+		return
+	}
+
+	tasks, err := h.managementService.ListTasks(r.Context(), filter, page)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
@@ -202,18 +366,27 @@ func (h *UserHandler) listTasks(w http.ResponseWriter, r *http.Request) {
 // @Accept json
 // @Param query query string true "Query"
 // @Param value query string true "Value"
-// @Success 200 {array} user.Response
+// @Param limit query int false "Page size, 20 by default and 100 at most"
+// @Param cursor query string false "Cursor returned as next_cursor by the previous page"
+// @Param offset query int false "Number of rows to skip, can not be combined with cursor"
+// @Success 200 {object} domain.Page[user.Response]
 // @Failure 400 {string} string "Bad request"
 // @Failure 404 {string} string "Not found"
+// @Security BearerAuth
+// @Failure 401 {string} string "Unauthorized"
 // @Router /users/search [get]
 func (h *UserHandler) search(w http.ResponseWriter, r *http.Request) {
-	filter, val := "", ""
-
-	for k, v := range r.URL.Query() {
-		filter, val = k, v[0]
+	page, errs := parsePageRequest(r)
+	if errs != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(errs)
+		return
 	}
 
-	users, err := h.managementService.SearchUsers(r.Context(), filter, val)
+	filter, val := searchParam(r)
+
+	users, err := h.managementService.SearchUsers(r.Context(), filter, val, page)
 	if err != nil {
 		if errors.Is(err, user.ErrSearch) {
 			w.WriteHeader(http.StatusBadRequest)
@@ -231,3 +404,50 @@ func (h *UserHandler) search(w http.ResponseWriter, r *http.Request) {
 
 	render.JSON(w, r, users)
 }
+
+// @Summary List deleted users
+// @Description List the users in the trash, admins only
+// @Tags users
+// @Param limit query int false "Page size, 20 by default and 100 at most"
+// @Param cursor query string false "Cursor returned as next_cursor by the previous page"
+// @Param offset query int false "Number of rows to skip, can not be combined with cursor"
+// @Success 200 {object} domain.Page[user.Response]
+// @Failure 400 {string} string "Bad request"
+// @Security BearerAuth
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Router /users/trash [get]
+func (h *UserHandler) trash(w http.ResponseWriter, r *http.Request) {
+	h.list(w, r)
+}
+
+// @Summary Restore a user
+// @Description Restore a deleted user
+// @Tags users
+// @Param id path string true "User ID"
+// @Success 200 {string} string "User restored"
+// @Failure 400 {string} string "Bad request"
+// @Failure 404 {string} string "User not found"
+// @Failure 409 {string} string "User is not deleted or another user has taken their email"
+// @Security BearerAuth
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 403 {string} string "Forbidden"
+// @Router /users/{id}/restore [post]
+func (h *UserHandler) restore(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	err := h.managementService.RestoreUser(r.Context(), id)
+	if err != nil {
+		if writeAccessError(w, err) || writeRestoreError(w, err) {
+			return
+		}
+
+		if errors.Is(err, user.ErrNotFound) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+}
@@ -0,0 +1,41 @@
+package httphandler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// setETag tags the response with the version of the resource, writes send it
+// back in If-Match.
+func setETag(w http.ResponseWriter, version int64) {
+	w.Header().Set("ETag", strconv.Quote(strconv.FormatInt(version, 10)))
+}
+
+// parseIfMatch reads the version a write is based on from the required
+// If-Match header, * stands for any version and is returned as zero. It
+// answers the request itself when the header is missing or names no version,
+// ok tells the caller whether to go on.
+func parseIfMatch(w http.ResponseWriter, r *http.Request) (version int64, ok bool) {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+
+	switch header {
+	case "":
+		http.Error(w, "the If-Match header with the ETag of the resource is required", http.StatusPreconditionRequired)
+		return 0, false
+	case "*":
+		return 0, true
+	}
+
+	// weak tags never match under the strong comparison If-Match asks for
+	tag, err := strconv.Unquote(header)
+	if err == nil {
+		version, err = strconv.ParseInt(tag, 10, 64)
+	}
+	if err != nil || version < 1 {
+		http.Error(w, "the If-Match header does not match the current version", http.StatusPreconditionFailed)
+		return 0, false
+	}
+
+	return version, true
+}
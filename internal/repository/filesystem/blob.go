@@ -0,0 +1,102 @@
+package filesystem
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"project-management/internal/domain/attachment"
+)
+
+// BlobStore keeps blobs as files below a root directory.
+type BlobStore struct {
+	root string
+}
+
+func NewBlobStore(root string) (*BlobStore, error) {
+	if err := os.MkdirAll(root, 0o750); err != nil {
+		return nil, err
+	}
+
+	return &BlobStore{
+		root: root,
+	}, nil
+}
+
+// Put writes to a temporary file first so that readers never see a partial
+// blob.
+func (s *BlobStore) Put(ctx context.Context, key string, r io.Reader) (n int64, err error) {
+	path, err := s.path(key)
+	if err != nil {
+		return
+	}
+
+	if err = os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return
+	}
+	defer func() {
+		if err != nil {
+			os.Remove(f.Name())
+		}
+	}()
+
+	n, err = io.Copy(f, r)
+	if err != nil {
+		f.Close()
+		return
+	}
+
+	if err = f.Close(); err != nil {
+		return
+	}
+
+	err = os.Rename(f.Name(), path)
+
+	return
+}
+
+func (s *BlobStore) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, attachment.ErrBlobNotFound
+	}
+
+	return f, err
+}
+
+func (s *BlobStore) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+
+	return err
+}
+
+// path maps the key below the root and refuses keys that would escape it.
+func (s *BlobStore) path(key string) (string, error) {
+	if key == "" || !filepath.IsLocal(filepath.FromSlash(key)) || strings.Contains(key, "\\") {
+		return "", attachment.ErrBlobNotFound
+	}
+
+	return filepath.Join(s.root, filepath.FromSlash(key)), nil
+}
@@ -0,0 +1,96 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"project-management/internal/domain/attachment"
+	"project-management/internal/domain/task"
+)
+
+type AttachmentRepository struct {
+	db *DB
+}
+
+func NewAttachmentRepository(db *DB) *AttachmentRepository {
+	if db == nil {
+		panic("db is required")
+	}
+
+	return &AttachmentRepository{
+		db: db,
+	}
+}
+
+func (r *AttachmentRepository) Create(ctx context.Context, data attachment.Entity) (id string, err error) {
+	defer r.db.write(ctx)()
+
+	// REFERENCES tasks(id)
+	if _, ok := r.db.tasks[data.TaskID]; !ok {
+		return "", task.ErrNotFound
+	}
+
+	put(r.db, r.db.attachments, data.ID, data)
+
+	return data.ID, nil
+}
+
+func (r *AttachmentRepository) Get(ctx context.Context, id string) (data attachment.Entity, err error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	data, ok := r.db.attachments[id]
+	if !ok {
+		return attachment.Entity{}, attachment.ErrNotFound
+	}
+
+	return
+}
+
+func (r *AttachmentRepository) List(ctx context.Context, taskID string) (attachments []attachment.Entity, err error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	attachments = []attachment.Entity{}
+	for _, a := range r.db.attachments {
+		if a.TaskID == taskID {
+			attachments = append(attachments, a)
+		}
+	}
+
+	sort.Slice(attachments, func(i, j int) bool {
+		if !attachments[i].CreatedAt.Equal(attachments[j].CreatedAt) {
+			return attachments[i].CreatedAt.Before(attachments[j].CreatedAt)
+		}
+		return attachments[i].ID < attachments[j].ID
+	})
+
+	return
+}
+
+func (r *AttachmentRepository) Delete(ctx context.Context, id string) (err error) {
+	defer r.db.write(ctx)()
+
+	if _, ok := r.db.attachments[id]; !ok {
+		return attachment.ErrNotFound
+	}
+
+	remove(r.db, r.db.attachments, id)
+
+	return
+}
+
+func (r *AttachmentRepository) Expired(ctx context.Context, before time.Time) (attachments []attachment.Entity, err error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	attachments = []attachment.Entity{}
+	for _, a := range r.db.attachments {
+		if t, ok := r.db.tasks[a.TaskID]; ok && purged(t.DeletedAt, before) {
+			attachments = append(attachments, a)
+		}
+	}
+
+	return
+}
@@ -0,0 +1,63 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	"project-management/internal/domain"
+	"project-management/internal/domain/audit"
+)
+
+type AuditRepository struct {
+	db *DB
+}
+
+func NewAuditRepository(db *DB) *AuditRepository {
+	if db == nil {
+		panic("db is required")
+	}
+
+	return &AuditRepository{
+		db: db,
+	}
+}
+
+func (r *AuditRepository) Record(ctx context.Context, e audit.Entry) (err error) {
+	defer r.db.write(ctx)()
+
+	r.db.auditSeq++
+	e.ID = r.db.auditSeq
+	if r.db.journal != nil {
+		n := len(r.db.audit)
+		r.db.journal.add(func() { r.db.audit = r.db.audit[:n] })
+	}
+
+	r.db.audit = append(r.db.audit, e)
+
+	return
+}
+
+func (r *AuditRepository) List(ctx context.Context, filter audit.Filter, page domain.PageRequest) (res domain.Page[audit.Entry], err error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	rows := []audit.Entry{}
+	for _, e := range r.db.audit {
+		switch {
+		case filter.EntityType != "" && e.EntityType != filter.EntityType:
+		case filter.EntityID != "" && e.EntityID != filter.EntityID:
+		case !filter.Since.IsZero() && e.CreatedAt.Before(filter.Since):
+		default:
+			rows = append(rows, e)
+		}
+	}
+
+	// entries are appended in id order, which the date part of the key
+	// follows as well
+	return paginate(rows, page, auditKey)
+}
+
+// auditKey pads the id so that ids compare as strings in numeric order.
+func auditKey(e audit.Entry) domain.Cursor {
+	return domain.Cursor{Date: e.CreatedAt.Format(domain.DateLayout), ID: fmt.Sprintf("%019d", e.ID)}
+}
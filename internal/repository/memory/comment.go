@@ -0,0 +1,114 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"project-management/internal/domain/comment"
+)
+
+type CommentRepository struct {
+	db *DB
+}
+
+func NewCommentRepository(db *DB) *CommentRepository {
+	if db == nil {
+		panic("db is required")
+	}
+
+	return &CommentRepository{
+		db: db,
+	}
+}
+
+func (r *CommentRepository) Create(ctx context.Context, data comment.Entity) (id string, err error) {
+	defer r.db.write(ctx)()
+
+	// REFERENCES tasks(id), task_comments(id)
+	if _, ok := r.db.tasks[data.TaskID]; !ok {
+		return "", comment.ErrParentNotFound
+	}
+
+	if data.ParentID != "" {
+		if _, ok := r.db.comments[data.ParentID]; !ok {
+			return "", comment.ErrParentNotFound
+		}
+	}
+
+	put(r.db, r.db.comments, data.ID, data)
+
+	return data.ID, nil
+}
+
+func (r *CommentRepository) Get(ctx context.Context, id string) (data comment.Entity, err error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	data, ok := r.db.comments[id]
+	if !ok {
+		return comment.Entity{}, comment.ErrNotFound
+	}
+
+	return
+}
+
+func (r *CommentRepository) List(ctx context.Context, taskID string) (comments []comment.Entity, err error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	comments = []comment.Entity{}
+	for _, c := range r.db.comments {
+		if c.TaskID == taskID {
+			comments = append(comments, c)
+		}
+	}
+
+	sort.Slice(comments, func(i, j int) bool {
+		if !comments[i].CreatedAt.Equal(comments[j].CreatedAt) {
+			return comments[i].CreatedAt.Before(comments[j].CreatedAt)
+		}
+		return comments[i].ID < comments[j].ID
+	})
+
+	return
+}
+
+func (r *CommentRepository) Update(ctx context.Context, id, body string) (err error) {
+	defer r.db.write(ctx)()
+
+	c, ok := r.db.comments[id]
+	if !ok || c.DeletedAt != nil {
+		return comment.ErrNotFound
+	}
+
+	c.Body = body
+	c.UpdatedAt = time.Now().UTC()
+	put(r.db, r.db.comments, id, c)
+
+	return
+}
+
+func (r *CommentRepository) Delete(ctx context.Context, id string) (err error) {
+	defer r.db.write(ctx)()
+
+	c, ok := r.db.comments[id]
+	if !ok {
+		return comment.ErrNotFound
+	}
+
+	for _, reply := range r.db.comments {
+		if reply.ParentID == id {
+			now := time.Now().UTC()
+			c.Body = ""
+			c.DeletedAt = &now
+			c.UpdatedAt = now
+			put(r.db, r.db.comments, id, c)
+			return
+		}
+	}
+
+	remove(r.db, r.db.comments, id)
+
+	return
+}
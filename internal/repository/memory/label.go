@@ -0,0 +1,178 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"project-management/internal/domain/label"
+	"project-management/internal/domain/project"
+)
+
+type LabelRepository struct {
+	db *DB
+}
+
+func NewLabelRepository(db *DB) *LabelRepository {
+	if db == nil {
+		panic("db is required")
+	}
+
+	return &LabelRepository{
+		db: db,
+	}
+}
+
+func (r *LabelRepository) Create(ctx context.Context, data label.Entity) (id string, err error) {
+	defer r.db.write(ctx)()
+
+	// REFERENCES projects(id)
+	if _, ok := r.db.projects[data.ProjectID]; !ok {
+		return "", project.ErrNotFound
+	}
+
+	if r.nameTaken(data.ProjectID, data.Name, data.ID) {
+		return "", label.ErrExists
+	}
+
+	put(r.db, r.db.labels, data.ID, data)
+
+	return data.ID, nil
+}
+
+func (r *LabelRepository) Get(ctx context.Context, id string) (data label.Entity, err error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	data, ok := r.db.labels[id]
+	if !ok {
+		return label.Entity{}, label.ErrNotFound
+	}
+
+	return
+}
+
+func (r *LabelRepository) List(ctx context.Context, projectID string) (labels []label.Entity, err error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	labels = []label.Entity{}
+	for _, l := range r.db.labels {
+		if l.ProjectID == projectID {
+			labels = append(labels, l)
+		}
+	}
+
+	sortLabels(labels)
+
+	return
+}
+
+func (r *LabelRepository) Update(ctx context.Context, id string, data label.Entity) (err error) {
+	defer r.db.write(ctx)()
+
+	current, ok := r.db.labels[id]
+	if !ok {
+		return label.ErrNotFound
+	}
+
+	if data.Name != "" {
+		if r.nameTaken(current.ProjectID, data.Name, id) {
+			return label.ErrExists
+		}
+		current.Name = data.Name
+	}
+
+	if data.Color != "" {
+		current.Color = data.Color
+	}
+
+	put(r.db, r.db.labels, id, current)
+
+	return
+}
+
+func (r *LabelRepository) Delete(ctx context.Context, id string) (err error) {
+	defer r.db.write(ctx)()
+
+	if _, ok := r.db.labels[id]; !ok {
+		return label.ErrNotFound
+	}
+
+	remove(r.db, r.db.labels, id)
+
+	// ON DELETE CASCADE
+	for _, labels := range r.db.taskLabels {
+		remove(r.db, labels, id)
+	}
+
+	return
+}
+
+func (r *LabelRepository) TaskLabels(ctx context.Context, taskID string) (labels []label.Entity, err error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	labels = []label.Entity{}
+	for labelID := range r.db.taskLabels[taskID] {
+		labels = append(labels, r.db.labels[labelID])
+	}
+
+	sortLabels(labels)
+
+	return
+}
+
+func (r *LabelRepository) Attach(ctx context.Context, taskID, labelID string) (err error) {
+	defer r.db.write(ctx)()
+
+	// REFERENCES tasks(id), labels(id)
+	if _, ok := r.db.tasks[taskID]; !ok {
+		return label.ErrNotFound
+	}
+	if _, ok := r.db.labels[labelID]; !ok {
+		return label.ErrNotFound
+	}
+
+	if r.db.taskLabels[taskID] == nil {
+		put(r.db, r.db.taskLabels, taskID, map[string]bool{})
+	}
+	put(r.db, r.db.taskLabels[taskID], labelID, true)
+
+	return
+}
+
+func (r *LabelRepository) Detach(ctx context.Context, taskID, labelID string) (err error) {
+	defer r.db.write(ctx)()
+
+	if !r.db.taskLabels[taskID][labelID] {
+		return label.ErrNotFound
+	}
+
+	remove(r.db, r.db.taskLabels[taskID], labelID)
+
+	return
+}
+
+func (r *LabelRepository) DetachAll(ctx context.Context, taskID string) (err error) {
+	defer r.db.write(ctx)()
+
+	remove(r.db, r.db.taskLabels, taskID)
+
+	return
+}
+
+func (r *LabelRepository) nameTaken(projectID, name, exceptID string) bool {
+	for _, l := range r.db.labels {
+		if l.ProjectID == projectID && l.Name == name && l.ID != exceptID {
+			return true
+		}
+	}
+
+	return false
+}
+
+func sortLabels(labels []label.Entity) {
+	sort.Slice(labels, func(i, j int) bool {
+		return labels[i].Name < labels[j].Name
+	})
+}
@@ -0,0 +1,230 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"project-management/internal/domain"
+	"project-management/internal/domain/attachment"
+	"project-management/internal/domain/audit"
+	"project-management/internal/domain/comment"
+	"project-management/internal/domain/label"
+	"project-management/internal/domain/milestone"
+	"project-management/internal/domain/project"
+	"project-management/internal/domain/sprint"
+	"project-management/internal/domain/task"
+	"project-management/internal/domain/user"
+)
+
+// DB is an in-process store shared by the memory repositories. It mimics the
+// referential behaviour of the postgres schema so that the service layer sees
+// the same results regardless of the backend.
+type DB struct {
+	mu sync.RWMutex
+	// work is held by a running unit of work, writes outside of it wait for
+	// it to end, see write
+	work sync.Mutex
+	// journal records how to undo the changes of the write holding mu when
+	// it runs in a unit of work, nil otherwise
+	journal *journal
+
+	users    map[string]user.Entity
+	tasks    map[string]task.Entity
+	projects map[string]project.Entity
+
+	// members is keyed by project id and then by user id
+	members map[string]map[string]project.Member
+
+	// workflows is keyed by project id
+	workflows map[string]task.Workflow
+
+	// events is keyed by task id, eventSeq mimics the BIGSERIAL of task_events
+	events   map[string][]task.Event
+	eventSeq int64
+
+	audit    []audit.Entry
+	auditSeq int64
+
+	comments    map[string]comment.Entity
+	attachments map[string]attachment.Entity
+
+	labels map[string]label.Entity
+	// taskLabels is keyed by task id and then by label id
+	taskLabels map[string]map[string]bool
+
+	// blockers is keyed by the blocked task id and then by the blocker id
+	blockers map[string]map[string]bool
+
+	sprints    map[string]sprint.Entity
+	milestones map[string]milestone.Entity
+}
+
+func New() *DB {
+	return &DB{
+		users:    map[string]user.Entity{},
+		tasks:    map[string]task.Entity{},
+		projects: map[string]project.Entity{},
+		members:  map[string]map[string]project.Member{},
+
+		workflows: map[string]task.Workflow{},
+		events:    map[string][]task.Event{},
+		comments:  map[string]comment.Entity{},
+
+		attachments: map[string]attachment.Entity{},
+
+		labels:     map[string]label.Entity{},
+		taskLabels: map[string]map[string]bool{},
+		blockers:   map[string]map[string]bool{},
+		sprints:    map[string]sprint.Entity{},
+		milestones: map[string]milestone.Entity{},
+	}
+}
+
+// write takes the write lock for a write under ctx and returns its unlock.
+// Inside a unit of work the changes of the write are journaled, outside of one
+// the write waits for the running unit of work to end so that undoing the
+// unit never overwrites it.
+func (db *DB) write(ctx context.Context) (unlock func()) {
+	j, _ := ctx.Value(txKey{}).(*journal)
+	if j == nil {
+		db.work.Lock()
+	}
+
+	db.mu.Lock()
+	db.journal = j
+
+	return func() {
+		db.journal = nil
+		db.mu.Unlock()
+
+		if j == nil {
+			db.work.Unlock()
+		}
+	}
+}
+
+// put stores v under k in m, the caller holds the write lock.
+func put[K comparable, V any](db *DB, m map[K]V, k K, v V) {
+	if db.journal != nil {
+		old, ok := m[k]
+		db.journal.add(func() {
+			if ok {
+				m[k] = old
+			} else {
+				delete(m, k)
+			}
+		})
+	}
+
+	m[k] = v
+}
+
+// remove deletes k from m, the caller holds the write lock.
+func remove[K comparable, V any](db *DB, m map[K]V, k K) {
+	old, ok := m[k]
+	if !ok {
+		return
+	}
+
+	if db.journal != nil {
+		db.journal.add(func() { m[k] = old })
+	}
+
+	delete(m, k)
+}
+
+// dropTask removes the task together with the rows referencing it, the
+// caller holds the write lock.
+func (db *DB) dropTask(id string) {
+	remove(db, db.tasks, id)
+	remove(db, db.events, id)
+	remove(db, db.taskLabels, id)
+
+	remove(db, db.blockers, id)
+	for _, blockers := range db.blockers {
+		remove(db, blockers, id)
+	}
+
+	// ON DELETE SET NULL
+	for k, t := range db.tasks {
+		if t.ParentID == id {
+			t.ParentID = ""
+			put(db, db.tasks, k, t)
+		}
+	}
+
+	for k, c := range db.comments {
+		if c.TaskID == id {
+			remove(db, db.comments, k)
+		}
+	}
+
+	for k, a := range db.attachments {
+		if a.TaskID == id {
+			remove(db, db.attachments, k)
+		}
+	}
+}
+
+// dropSprint removes the sprint and moves its tasks to the backlog, the
+// caller holds the write lock.
+func (db *DB) dropSprint(id string) {
+	remove(db, db.sprints, id)
+
+	// ON DELETE SET NULL
+	for k, t := range db.tasks {
+		if t.SprintID == id {
+			t.SprintID = ""
+			put(db, db.tasks, k, t)
+		}
+	}
+}
+
+// dropMilestone removes the milestone and unlinks its tasks, the caller holds
+// the write lock.
+func (db *DB) dropMilestone(id string) {
+	remove(db, db.milestones, id)
+
+	// ON DELETE SET NULL
+	for k, t := range db.tasks {
+		if t.MilestoneID == id {
+			t.MilestoneID = ""
+			put(db, db.tasks, k, t)
+		}
+	}
+}
+
+// addTaskEvents appends to the history of the tasks, the caller holds the
+// write lock.
+func (db *DB) addTaskEvents(events []task.Event) {
+	for _, e := range events {
+		db.eventSeq++
+		e.ID = db.eventSeq
+		put(db, db.events, e.TaskID, append(db.events[e.TaskID], e))
+	}
+}
+
+// visible reports whether a row deleted at deletedAt is returned by reads
+// under the deleted scope of the context.
+func visible(ctx context.Context, deletedAt *time.Time) bool {
+	switch domain.DeletedScopeFromContext(ctx) {
+	case domain.IncludeDeleted:
+		return true
+	case domain.OnlyDeleted:
+		return deletedAt != nil
+	default:
+		return deletedAt == nil
+	}
+}
+
+// versionMatches mirrors the compare-and-swap of the postgres repositories,
+// an expected version of zero matches any version.
+func versionMatches(current, expected int64) bool {
+	return expected == 0 || expected == current
+}
+
+// purged reports whether a row deleted at deletedAt is old enough to purge.
+func purged(deletedAt *time.Time, before time.Time) bool {
+	return deletedAt != nil && deletedAt.Before(before)
+}
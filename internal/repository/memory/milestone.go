@@ -0,0 +1,128 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"project-management/internal/domain/milestone"
+	"project-management/internal/domain/project"
+	"project-management/internal/domain/task"
+)
+
+type MilestoneRepository struct {
+	db *DB
+}
+
+func NewMilestoneRepository(db *DB) *MilestoneRepository {
+	if db == nil {
+		panic("db is required")
+	}
+
+	return &MilestoneRepository{
+		db: db,
+	}
+}
+
+func (r *MilestoneRepository) Create(ctx context.Context, data milestone.Entity) (id string, err error) {
+	defer r.db.write(ctx)()
+
+	// REFERENCES projects(id)
+	if _, ok := r.db.projects[data.ProjectID]; !ok {
+		return "", project.ErrNotFound
+	}
+
+	put(r.db, r.db.milestones, data.ID, data)
+
+	return data.ID, nil
+}
+
+func (r *MilestoneRepository) Get(ctx context.Context, id string) (data milestone.Entity, err error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	data, ok := r.db.milestones[id]
+	if !ok {
+		return milestone.Entity{}, milestone.ErrNotFound
+	}
+
+	return
+}
+
+func (r *MilestoneRepository) List(ctx context.Context, projectID string) (milestones []milestone.Entity, err error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	milestones = []milestone.Entity{}
+	for _, m := range r.db.milestones {
+		if m.ProjectID == projectID {
+			milestones = append(milestones, m)
+		}
+	}
+
+	sort.Slice(milestones, func(i, j int) bool {
+		if milestones[i].DueDate != milestones[j].DueDate {
+			return milestones[i].DueDate < milestones[j].DueDate
+		}
+		return milestones[i].ID < milestones[j].ID
+	})
+
+	return
+}
+
+func (r *MilestoneRepository) Update(ctx context.Context, id string, data milestone.Entity) (err error) {
+	defer r.db.write(ctx)()
+
+	current, ok := r.db.milestones[id]
+	if !ok {
+		return milestone.ErrNotFound
+	}
+
+	if data.Title != "" {
+		current.Title = data.Title
+	}
+
+	if data.Description != "" {
+		current.Description = data.Description
+	}
+
+	if data.DueDate != "" {
+		current.DueDate = data.DueDate
+	}
+
+	put(r.db, r.db.milestones, id, current)
+
+	return
+}
+
+func (r *MilestoneRepository) Delete(ctx context.Context, id string) (err error) {
+	defer r.db.write(ctx)()
+
+	if _, ok := r.db.milestones[id]; !ok {
+		return milestone.ErrNotFound
+	}
+
+	r.db.dropMilestone(id)
+
+	return
+}
+
+func (r *MilestoneRepository) Link(ctx context.Context, taskID, milestoneID string, events ...task.Event) (err error) {
+	defer r.db.write(ctx)()
+
+	data, ok := r.db.tasks[taskID]
+	if !ok || data.DeletedAt != nil {
+		return task.ErrNotFound
+	}
+
+	// REFERENCES milestones(id)
+	if _, ok := r.db.milestones[milestoneID]; milestoneID != "" && !ok {
+		return milestone.ErrNotFound
+	}
+
+	data.MilestoneID = milestoneID
+	data.Version++
+	put(r.db, r.db.tasks, taskID, data)
+	r.db.addTaskEvents(events)
+
+	return
+}
@@ -0,0 +1,56 @@
+package memory
+
+import (
+	"sort"
+
+	"project-management/internal/domain"
+)
+
+// paginate cuts one page out of rows, which must already be ordered by
+// (date, id) as returned by key.
+func paginate[T any](rows []T, page domain.PageRequest, key func(T) domain.Cursor) (res domain.Page[T], err error) {
+	res.Items = []T{}
+	res.Total = len(rows)
+
+	if page.Cursor != "" {
+		cursor, err := domain.DecodeCursor(page.Cursor)
+		if err != nil {
+			return res, err
+		}
+
+		start := len(rows)
+		for i, row := range rows {
+			k := key(row)
+			if k.Date > cursor.Date || (k.Date == cursor.Date && k.ID > cursor.ID) {
+				start = i
+				break
+			}
+		}
+		rows = rows[start:]
+	}
+
+	if page.Offset >= len(rows) {
+		return
+	}
+	rows = rows[page.Offset:]
+
+	if len(rows) > page.Limit {
+		rows = rows[:page.Limit]
+		last := key(rows[len(rows)-1])
+		res.NextCursor = domain.EncodeCursor(last.Date, last.ID)
+	}
+
+	res.Items = append(res.Items, rows...)
+
+	return
+}
+
+func sortByKey[T any](rows []T, key func(T) domain.Cursor) {
+	sort.Slice(rows, func(i, j int) bool {
+		a, b := key(rows[i]), key(rows[j])
+		if a.Date != b.Date {
+			return a.Date < b.Date
+		}
+		return a.ID < b.ID
+	})
+}
@@ -0,0 +1,115 @@
+package memory
+
+import (
+	"reflect"
+	"testing"
+
+	"project-management/internal/domain"
+)
+
+func TestPaginate(t *testing.T) {
+	// rows created on the same day are ordered by id
+	rows := []domain.Cursor{
+		{Date: "2024-01-02", ID: "c"},
+		{Date: "2024-01-01", ID: "b"},
+		{Date: "2024-01-02", ID: "a"},
+		{Date: "2024-01-01", ID: "d"},
+		{Date: "2024-01-03", ID: "a"},
+	}
+	key := func(c domain.Cursor) domain.Cursor { return c }
+	sortByKey(rows, key)
+
+	ids := func(rows []domain.Cursor) []string {
+		res := []string{}
+		for _, r := range rows {
+			res = append(res, r.Date+"/"+r.ID)
+		}
+		return res
+	}
+
+	tests := []struct {
+		name string
+		page domain.PageRequest
+		want []string
+		next string
+	}{
+		{
+			name: "first page",
+			page: domain.PageRequest{Limit: 2},
+			want: []string{"2024-01-01/b", "2024-01-01/d"},
+			next: domain.EncodeCursor("2024-01-01", "d"),
+		},
+		{
+			name: "cursor breaks the tie of the date by id",
+			page: domain.PageRequest{Limit: 2, Cursor: domain.EncodeCursor("2024-01-02", "a")},
+			want: []string{"2024-01-02/c", "2024-01-03/a"},
+		},
+		{
+			name: "cursor between rows",
+			page: domain.PageRequest{Limit: 1, Cursor: domain.EncodeCursor("2024-01-01", "c")},
+			want: []string{"2024-01-01/d"},
+			next: domain.EncodeCursor("2024-01-01", "d"),
+		},
+		{
+			name: "cursor past the last row",
+			page: domain.PageRequest{Limit: 2, Cursor: domain.EncodeCursor("2024-01-03", "a")},
+			want: []string{},
+		},
+		{
+			name: "offset",
+			page: domain.PageRequest{Limit: 2, Offset: 3},
+			want: []string{"2024-01-02/c", "2024-01-03/a"},
+		},
+		{
+			name: "offset past the last row",
+			page: domain.PageRequest{Limit: 2, Offset: 5},
+			want: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := paginate(rows, tt.page, key)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(ids(got.Items), tt.want) {
+				t.Errorf("got %v, want %v", ids(got.Items), tt.want)
+			}
+			if got.NextCursor != tt.next {
+				t.Errorf("got next cursor %q, want %q", got.NextCursor, tt.next)
+			}
+			if got.Total != len(rows) {
+				t.Errorf("got total %d, want %d", got.Total, len(rows))
+			}
+		})
+	}
+
+	t.Run("following the cursors returns every row once", func(t *testing.T) {
+		var seen []domain.Cursor
+		page := domain.PageRequest{Limit: 2}
+		for {
+			got, err := paginate(rows, page, key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			seen = append(seen, got.Items...)
+
+			if got.NextCursor == "" {
+				break
+			}
+			page.Cursor = got.NextCursor
+		}
+
+		if !reflect.DeepEqual(ids(seen), ids(rows)) {
+			t.Errorf("got %v, want %v", ids(seen), ids(rows))
+		}
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		if _, err := paginate(rows, domain.PageRequest{Limit: 2, Cursor: "x"}, key); err != domain.ErrInvalidCursor {
+			t.Errorf("got error %v, want %v", err, domain.ErrInvalidCursor)
+		}
+	})
+}
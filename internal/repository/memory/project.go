@@ -0,0 +1,308 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"project-management/internal/domain"
+	"project-management/internal/domain/project"
+)
+
+type ProjectRepository struct {
+	db *DB
+}
+
+func NewProjectRepository(db *DB) *ProjectRepository {
+	if db == nil {
+		panic("db is required")
+	}
+
+	return &ProjectRepository{
+		db: db,
+	}
+}
+
+func (r *ProjectRepository) Create(ctx context.Context, p project.Entity) (id string, err error) {
+	defer r.db.write(ctx)()
+
+	if _, ok := r.db.projects[p.ID]; ok {
+		return "", project.ErrExists
+	}
+
+	p.Version = 1
+	put(r.db, r.db.projects, p.ID, p)
+
+	return p.ID, nil
+}
+
+func (r *ProjectRepository) Update(ctx context.Context, id string, p project.Entity) (err error) {
+	return r.Patch(ctx, id, p, nil)
+}
+
+func (r *ProjectRepository) Patch(ctx context.Context, id string, p project.Entity, clear []project.Field) (err error) {
+	defer r.db.write(ctx)()
+
+	data, ok := r.db.projects[id]
+	if !ok || data.DeletedAt != nil {
+		return project.ErrNotFound
+	}
+
+	if !versionMatches(data.Version, p.Version) {
+		return domain.ErrVersionConflict
+	}
+
+	if p.Title != "" {
+		data.Title = p.Title
+	}
+
+	if p.Description != "" {
+		data.Description = p.Description
+	}
+
+	if p.ManagerID != "" {
+		data.ManagerID = p.ManagerID
+	}
+
+	if p.StartedAt != "" {
+		data.StartedAt = p.StartedAt
+	}
+
+	if p.FinishedAt != "" {
+		data.FinishedAt = p.FinishedAt
+	}
+
+	for _, f := range clear {
+		switch f {
+		case project.FieldDescription:
+			data.Description = ""
+		case project.FieldFinishedAt:
+			data.FinishedAt = ""
+		case project.FieldManagerID:
+			data.ManagerID = ""
+		}
+	}
+
+	data.Version++
+	put(r.db, r.db.projects, id, data)
+
+	return
+}
+
+func (r *ProjectRepository) Delete(ctx context.Context, id string, version int64) (err error) {
+	defer r.db.write(ctx)()
+
+	p, ok := r.db.projects[id]
+	if !ok || p.DeletedAt != nil {
+		return project.ErrNotFound
+	}
+
+	if !versionMatches(p.Version, version) {
+		return domain.ErrVersionConflict
+	}
+
+	// the tasks share the deletion time, which is how Restore finds them
+	now := time.Now().UTC()
+	p.DeletedAt = &now
+	p.Version++
+	put(r.db, r.db.projects, id, p)
+
+	for k, t := range r.db.tasks {
+		if t.ProjectID == id && t.DeletedAt == nil {
+			t.DeletedAt = &now
+			t.Version++
+			put(r.db, r.db.tasks, k, t)
+		}
+	}
+
+	return
+}
+
+func (r *ProjectRepository) Restore(ctx context.Context, id string) (err error) {
+	defer r.db.write(ctx)()
+
+	p, ok := r.db.projects[id]
+	if !ok || p.DeletedAt == nil {
+		return project.ErrNotFound
+	}
+
+	deletedAt := *p.DeletedAt
+	p.DeletedAt = nil
+	p.Version++
+	put(r.db, r.db.projects, id, p)
+
+	for k, t := range r.db.tasks {
+		if t.ProjectID == id && t.DeletedAt != nil && t.DeletedAt.Equal(deletedAt) {
+			t.DeletedAt = nil
+			t.Version++
+			put(r.db, r.db.tasks, k, t)
+		}
+	}
+
+	return
+}
+
+func (r *ProjectRepository) Purge(ctx context.Context, before time.Time) (n int64, err error) {
+	defer r.db.write(ctx)()
+
+	for id, p := range r.db.projects {
+		if purged(p.DeletedAt, before) {
+			r.purge(id)
+			n++
+		}
+	}
+
+	return
+}
+
+// purge removes the project for good, the caller holds the write lock.
+func (r *ProjectRepository) purge(id string) {
+	remove(r.db, r.db.projects, id)
+
+	// ON DELETE CASCADE
+	for k, t := range r.db.tasks {
+		if t.ProjectID == id {
+			r.db.dropTask(k)
+		}
+	}
+	remove(r.db, r.db.members, id)
+	remove(r.db, r.db.workflows, id)
+
+	for k, l := range r.db.labels {
+		if l.ProjectID == id {
+			remove(r.db, r.db.labels, k)
+		}
+	}
+
+	for k, s := range r.db.sprints {
+		if s.ProjectID == id {
+			r.db.dropSprint(k)
+		}
+	}
+
+	for k, m := range r.db.milestones {
+		if m.ProjectID == id {
+			r.db.dropMilestone(k)
+		}
+	}
+}
+
+func (r *ProjectRepository) Get(ctx context.Context, id string) (p project.Entity, err error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	p, ok := r.db.projects[id]
+	if !ok || !visible(ctx, p.DeletedAt) {
+		return project.Entity{}, project.ErrNotFound
+	}
+
+	return
+}
+
+// Lock and LockShared only check the project, a unit of work on the memory
+// store keeps the writes of other requests out until it ends anyway.
+func (r *ProjectRepository) Lock(ctx context.Context, id string) error {
+	return r.LockShared(ctx, id)
+}
+
+func (r *ProjectRepository) LockShared(ctx context.Context, id string) (err error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	if p, ok := r.db.projects[id]; !ok || p.DeletedAt != nil {
+		return project.ErrNotFound
+	}
+
+	return
+}
+
+func (r *ProjectRepository) ManagedBy(ctx context.Context, userID string) (projects []project.Entity, err error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	projects = []project.Entity{}
+	for _, p := range r.db.projects {
+		if p.ManagerID == userID && p.DeletedAt == nil {
+			projects = append(projects, p)
+		}
+	}
+
+	sortByKey(projects, projectKey)
+
+	return
+}
+
+func (r *ProjectRepository) List(ctx context.Context, page domain.PageRequest) (projects domain.Page[project.Entity], err error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	rows := []project.Entity{}
+	for _, p := range r.db.projects {
+		if visible(ctx, p.DeletedAt) {
+			rows = append(rows, p)
+		}
+	}
+
+	sortByKey(rows, projectKey)
+
+	return paginate(rows, page, projectKey)
+}
+
+func (r *ProjectRepository) Search(ctx context.Context, arg, value string, page domain.PageRequest) (projects domain.Page[project.Entity], err error) {
+	field := r.prepareFilterArg(arg)
+	if field == nil {
+		err = project.ErrSearch
+		return
+	}
+
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	rows := []project.Entity{}
+	for _, p := range r.db.projects {
+		if field(p) == value && visible(ctx, p.DeletedAt) {
+			rows = append(rows, p)
+		}
+	}
+
+	if len(rows) == 0 {
+		err = project.ErrNotFound
+		return
+	}
+
+	sortByKey(rows, projectKey)
+
+	return paginate(rows, page, projectKey)
+}
+
+func (r *ProjectRepository) prepareFilterArg(arg string) func(project.Entity) string {
+	switch arg {
+	case "title":
+		return func(p project.Entity) string { return p.Title }
+	case "manager":
+		return func(p project.Entity) string { return p.ManagerID }
+	default:
+		return nil
+	}
+}
+
+func (r *ProjectRepository) FullTextSearch(ctx context.Context, query string, limit int) (projects []domain.Ranked[project.Entity], err error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	projects = []domain.Ranked[project.Entity]{}
+	for _, p := range r.db.projects {
+		if p.DeletedAt != nil {
+			continue
+		}
+
+		if rank := rank(query, weighted{p.Title, 1}, weighted{p.Description, 0.4}); rank > 0 {
+			projects = append(projects, domain.Ranked[project.Entity]{Entity: p, Rank: rank})
+		}
+	}
+
+	return sortRanked(projects, projectKey, limit), nil
+}
+
+func projectKey(p project.Entity) domain.Cursor {
+	return domain.Cursor{Date: string(p.StartedAt), ID: p.ID}
+}
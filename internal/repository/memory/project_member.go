@@ -0,0 +1,103 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"project-management/internal/domain/project"
+)
+
+type ProjectMemberRepository struct {
+	db *DB
+}
+
+func NewProjectMemberRepository(db *DB) *ProjectMemberRepository {
+	if db == nil {
+		panic("db is required")
+	}
+
+	return &ProjectMemberRepository{
+		db: db,
+	}
+}
+
+func (r *ProjectMemberRepository) AddMember(ctx context.Context, m project.Member) (err error) {
+	defer r.db.write(ctx)()
+
+	if _, ok := r.db.projects[m.ProjectID]; !ok {
+		return project.ErrNotFound
+	}
+
+	if _, ok := r.db.members[m.ProjectID][m.UserID]; ok {
+		return project.ErrMemberExists
+	}
+
+	if r.db.members[m.ProjectID] == nil {
+		put(r.db, r.db.members, m.ProjectID, map[string]project.Member{})
+	}
+	put(r.db, r.db.members[m.ProjectID], m.UserID, m)
+
+	return
+}
+
+func (r *ProjectMemberRepository) SetMember(ctx context.Context, m project.Member) (err error) {
+	defer r.db.write(ctx)()
+
+	if _, ok := r.db.projects[m.ProjectID]; !ok {
+		return project.ErrNotFound
+	}
+
+	if existing, ok := r.db.members[m.ProjectID][m.UserID]; ok {
+		m.JoinedAt = existing.JoinedAt
+	}
+
+	if r.db.members[m.ProjectID] == nil {
+		put(r.db, r.db.members, m.ProjectID, map[string]project.Member{})
+	}
+	put(r.db, r.db.members[m.ProjectID], m.UserID, m)
+
+	return
+}
+
+func (r *ProjectMemberRepository) GetMember(ctx context.Context, projectID, userID string) (m project.Member, err error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	m, ok := r.db.members[projectID][userID]
+	if !ok {
+		err = project.ErrMemberNotFound
+	}
+
+	return
+}
+
+func (r *ProjectMemberRepository) ListMembers(ctx context.Context, projectID string) (members []project.Member, err error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	members = []project.Member{}
+	for _, m := range r.db.members[projectID] {
+		members = append(members, m)
+	}
+
+	sort.Slice(members, func(i, j int) bool {
+		if !members[i].JoinedAt.Equal(members[j].JoinedAt) {
+			return members[i].JoinedAt.Before(members[j].JoinedAt)
+		}
+		return members[i].UserID < members[j].UserID
+	})
+
+	return
+}
+
+func (r *ProjectMemberRepository) RemoveMember(ctx context.Context, projectID, userID string) (err error) {
+	defer r.db.write(ctx)()
+
+	if _, ok := r.db.members[projectID][userID]; !ok {
+		return project.ErrMemberNotFound
+	}
+
+	remove(r.db, r.db.members[projectID], userID)
+
+	return
+}
@@ -0,0 +1,55 @@
+package memory
+
+import (
+	"sort"
+	"strings"
+
+	"project-management/internal/domain"
+)
+
+// weighted is a piece of text taking part in a full-text search, weights
+// follow the A and B weights of the postgres search_vector columns.
+type weighted struct {
+	text   string
+	weight float64
+}
+
+// rank is a rough stand-in for ts_rank: every query term has to appear in one
+// of the fields and each occurrence adds the weight of its field.
+func rank(query string, fields ...weighted) float64 {
+	terms := strings.Fields(strings.ToLower(query))
+	if len(terms) == 0 {
+		return 0
+	}
+
+	var total float64
+	for _, term := range terms {
+		var hits float64
+		for _, f := range fields {
+			hits += float64(strings.Count(strings.ToLower(f.text), term)) * f.weight
+		}
+
+		if hits == 0 {
+			return 0
+		}
+
+		total += hits
+	}
+
+	return total / float64(len(terms))
+}
+
+func sortRanked[T any](rows []domain.Ranked[T], key func(T) domain.Cursor, limit int) []domain.Ranked[T] {
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Rank != rows[j].Rank {
+			return rows[i].Rank > rows[j].Rank
+		}
+		return key(rows[i].Entity).ID < key(rows[j].Entity).ID
+	})
+
+	if len(rows) > limit {
+		rows = rows[:limit]
+	}
+
+	return rows
+}
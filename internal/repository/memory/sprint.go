@@ -0,0 +1,194 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"project-management/internal/domain/project"
+	"project-management/internal/domain/sprint"
+	"project-management/internal/domain/task"
+)
+
+type SprintRepository struct {
+	db *DB
+}
+
+func NewSprintRepository(db *DB) *SprintRepository {
+	if db == nil {
+		panic("db is required")
+	}
+
+	return &SprintRepository{
+		db: db,
+	}
+}
+
+func (r *SprintRepository) Create(ctx context.Context, data sprint.Entity) (id string, err error) {
+	defer r.db.write(ctx)()
+
+	// REFERENCES projects(id)
+	if _, ok := r.db.projects[data.ProjectID]; !ok {
+		return "", project.ErrNotFound
+	}
+
+	put(r.db, r.db.sprints, data.ID, data)
+
+	return data.ID, nil
+}
+
+func (r *SprintRepository) Get(ctx context.Context, id string) (data sprint.Entity, err error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	data, ok := r.db.sprints[id]
+	if !ok {
+		return sprint.Entity{}, sprint.ErrNotFound
+	}
+
+	return
+}
+
+func (r *SprintRepository) List(ctx context.Context, projectID string) (sprints []sprint.Entity, err error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	sprints = []sprint.Entity{}
+	for _, s := range r.db.sprints {
+		if s.ProjectID == projectID {
+			sprints = append(sprints, s)
+		}
+	}
+
+	sort.Slice(sprints, func(i, j int) bool {
+		if sprints[i].StartAt != sprints[j].StartAt {
+			return sprints[i].StartAt < sprints[j].StartAt
+		}
+		return sprints[i].ID < sprints[j].ID
+	})
+
+	return
+}
+
+func (r *SprintRepository) Update(ctx context.Context, id string, data sprint.Entity) (err error) {
+	defer r.db.write(ctx)()
+
+	current, ok := r.db.sprints[id]
+	if !ok {
+		return sprint.ErrNotFound
+	}
+
+	if data.Name != "" {
+		current.Name = data.Name
+	}
+
+	if data.Goal != "" {
+		current.Goal = data.Goal
+	}
+
+	if data.StartAt != "" {
+		current.StartAt = data.StartAt
+	}
+
+	if data.EndAt != "" {
+		current.EndAt = data.EndAt
+	}
+
+	if data.State != "" {
+		// UNIQUE (project_id) WHERE state = 'active'
+		if data.State == sprint.StateActive && r.hasActive(current.ProjectID, id) {
+			return sprint.ErrActiveExists
+		}
+		current.State = data.State
+	}
+
+	put(r.db, r.db.sprints, id, current)
+
+	return
+}
+
+func (r *SprintRepository) Delete(ctx context.Context, id string) (err error) {
+	defer r.db.write(ctx)()
+
+	if _, ok := r.db.sprints[id]; !ok {
+		return sprint.ErrNotFound
+	}
+
+	r.db.dropSprint(id)
+
+	return
+}
+
+// Lock only checks the sprint, a unit of work on the memory store keeps the
+// writes of other requests out until it ends anyway.
+func (r *SprintRepository) Lock(ctx context.Context, id string) (err error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	if _, ok := r.db.sprints[id]; !ok {
+		return sprint.ErrNotFound
+	}
+
+	return
+}
+
+func (r *SprintRepository) Schedule(ctx context.Context, taskID, sprintID string, events ...task.Event) (err error) {
+	defer r.db.write(ctx)()
+
+	data, ok := r.db.tasks[taskID]
+	if !ok || data.DeletedAt != nil {
+		return task.ErrNotFound
+	}
+
+	// REFERENCES sprints(id)
+	if _, ok := r.db.sprints[sprintID]; sprintID != "" && !ok {
+		return sprint.ErrNotFound
+	}
+
+	data.SprintID = sprintID
+	data.Version++
+	put(r.db, r.db.tasks, taskID, data)
+	r.db.addTaskEvents(events)
+
+	return
+}
+
+func (r *SprintRepository) Close(ctx context.Context, id, nextID string, taskIDs []string, events ...task.Event) (err error) {
+	defer r.db.write(ctx)()
+
+	current, ok := r.db.sprints[id]
+	if !ok {
+		return sprint.ErrNotFound
+	}
+
+	// REFERENCES sprints(id)
+	if _, ok := r.db.sprints[nextID]; nextID != "" && !ok {
+		return sprint.ErrNotFound
+	}
+
+	current.State = sprint.StateClosed
+	put(r.db, r.db.sprints, id, current)
+
+	for _, taskID := range taskIDs {
+		if t, ok := r.db.tasks[taskID]; ok {
+			t.SprintID = nextID
+			t.Version++
+			put(r.db, r.db.tasks, taskID, t)
+		}
+	}
+
+	r.db.addTaskEvents(events)
+
+	return
+}
+
+// hasActive reports whether a sprint of the project other than id is active,
+// the caller holds the lock.
+func (r *SprintRepository) hasActive(projectID, id string) bool {
+	for _, s := range r.db.sprints {
+		if s.ProjectID == projectID && s.ID != id && s.State == sprint.StateActive {
+			return true
+		}
+	}
+
+	return false
+}
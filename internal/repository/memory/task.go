@@ -0,0 +1,506 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"project-management/internal/domain"
+	"project-management/internal/domain/task"
+)
+
+type TaskRepository struct {
+	db *DB
+}
+
+func NewTaskRepository(db *DB) *TaskRepository {
+	if db == nil {
+		panic("db is required")
+	}
+
+	return &TaskRepository{
+		db: db,
+	}
+}
+
+func (r *TaskRepository) Create(ctx context.Context, t task.Entity) (id string, err error) {
+	defer r.db.write(ctx)()
+
+	if err = r.create(t); err != nil {
+		return
+	}
+
+	return t.ID, nil
+}
+
+// create stores a new task, the caller holds the write lock.
+func (r *TaskRepository) create(t task.Entity) (err error) {
+	if _, ok := r.db.tasks[t.ID]; ok {
+		return task.ErrExists
+	}
+
+	t.Version = 1
+	put(r.db, r.db.tasks, t.ID, t)
+
+	return
+}
+
+func (r *TaskRepository) Update(ctx context.Context, id string, t task.Entity, events ...task.Event) (err error) {
+	return r.Patch(ctx, id, t, nil, events...)
+}
+
+func (r *TaskRepository) Patch(ctx context.Context, id string, t task.Entity, clear []task.Field, events ...task.Event) (err error) {
+	defer r.db.write(ctx)()
+
+	if err = r.patch(id, t, clear); err != nil {
+		return
+	}
+
+	r.db.addTaskEvents(events)
+
+	return
+}
+
+// patch applies the changes to a stored task, the caller holds the write
+// lock.
+func (r *TaskRepository) patch(id string, t task.Entity, clear []task.Field) (err error) {
+	data, ok := r.db.tasks[id]
+	if !ok || data.DeletedAt != nil {
+		return task.ErrNotFound
+	}
+
+	if !versionMatches(data.Version, t.Version) {
+		return domain.ErrVersionConflict
+	}
+
+	if t.Title != "" {
+		data.Title = t.Title
+	}
+
+	if t.Description != "" {
+		data.Description = t.Description
+	}
+
+	if t.Priority != "" {
+		data.Priority = t.Priority
+	}
+
+	if t.Status != "" {
+		data.Status = t.Status
+	}
+
+	if t.AuthorID != "" {
+		data.AuthorID = t.AuthorID
+	}
+
+	if t.AssigneeID != "" {
+		data.AssigneeID = t.AssigneeID
+	}
+
+	if t.ProjectID != "" {
+		data.ProjectID = t.ProjectID
+	}
+
+	if t.ParentID != "" {
+		data.ParentID = t.ParentID
+	}
+
+	if t.DueDate != "" {
+		data.DueDate = t.DueDate
+	}
+
+	if t.EstimateHours != nil {
+		data.EstimateHours = t.EstimateHours
+	}
+
+	// a zero completion time reopens the task
+	if t.CompletedAt != nil {
+		data.CompletedAt = t.CompletedAt
+		if t.CompletedAt.IsZero() {
+			data.CompletedAt = nil
+		}
+	}
+
+	for _, f := range clear {
+		switch f {
+		case task.FieldDescription:
+			data.Description = ""
+		case task.FieldAssigneeID:
+			data.AssigneeID = ""
+		case task.FieldParentID:
+			data.ParentID = ""
+		case task.FieldDueDate:
+			data.DueDate = ""
+		case task.FieldEstimateHours:
+			data.EstimateHours = nil
+		}
+	}
+
+	data.Version++
+	put(r.db, r.db.tasks, id, data)
+
+	return
+}
+
+func (r *TaskRepository) Assign(ctx context.Context, id, assigneeID string, events ...task.Event) (err error) {
+	defer r.db.write(ctx)()
+
+	data, ok := r.db.tasks[id]
+	if !ok || data.DeletedAt != nil {
+		return task.ErrNotFound
+	}
+
+	data.AssigneeID = assigneeID
+	data.Version++
+	put(r.db, r.db.tasks, id, data)
+	r.db.addTaskEvents(events)
+
+	return
+}
+
+func (r *TaskRepository) History(ctx context.Context, id string) (events []task.Event, err error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	events = append([]task.Event{}, r.db.events[id]...)
+
+	return
+}
+
+func (r *TaskRepository) Subtasks(ctx context.Context, id string) (tasks []task.Entity, err error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	tasks = []task.Entity{}
+	seen := map[string]bool{id: true}
+	parents := []string{id}
+
+	for len(parents) > 0 {
+		var next []string
+		for _, t := range r.db.tasks {
+			if t.DeletedAt == nil && !seen[t.ID] && slices.Contains(parents, t.ParentID) {
+				seen[t.ID] = true
+				tasks = append(tasks, t)
+				next = append(next, t.ID)
+			}
+		}
+		parents = next
+	}
+
+	sortByKey(tasks, taskKey)
+
+	return
+}
+
+func (r *TaskRepository) OwnedBy(ctx context.Context, userID string) (tasks []task.Entity, err error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	tasks = []task.Entity{}
+	for _, t := range r.db.tasks {
+		if (t.AuthorID == userID || t.AssigneeID == userID) && t.DeletedAt == nil {
+			tasks = append(tasks, t)
+		}
+	}
+
+	sortByKey(tasks, taskKey)
+
+	return
+}
+
+func (r *TaskRepository) Get(ctx context.Context, id string) (t task.Entity, err error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	t, ok := r.db.tasks[id]
+	if !ok || !visible(ctx, t.DeletedAt) {
+		return task.Entity{}, task.ErrNotFound
+	}
+
+	return
+}
+
+func (r *TaskRepository) Delete(ctx context.Context, id string, version int64) (err error) {
+	defer r.db.write(ctx)()
+
+	return r.delete(id, version)
+}
+
+// delete moves a task to the trash, the caller holds the write lock.
+func (r *TaskRepository) delete(id string, version int64) (err error) {
+	t, ok := r.db.tasks[id]
+	if !ok || t.DeletedAt != nil {
+		return task.ErrNotFound
+	}
+
+	if !versionMatches(t.Version, version) {
+		return domain.ErrVersionConflict
+	}
+
+	now := time.Now().UTC()
+	t.DeletedAt = &now
+	t.Version++
+	put(r.db, r.db.tasks, id, t)
+
+	return
+}
+
+func (r *TaskRepository) Bulk(ctx context.Context, writes []task.Write, atomic bool) (errs []error, err error) {
+	defer r.db.write(ctx)()
+
+	// an atomic batch undoes the writes before a failing one from the journal,
+	// which it keeps for itself outside of a unit of work
+	j := r.db.journal
+	if j == nil {
+		j = &journal{}
+		r.db.journal = j
+	}
+	n := len(j.undo)
+
+	var events []task.Event
+
+	errs = make([]error, len(writes))
+	for i, w := range writes {
+		switch w.Op {
+		case task.OpCreate:
+			errs[i] = r.create(w.Entity)
+		case task.OpUpdate:
+			errs[i] = r.patch(w.ID, w.Entity, w.Clear)
+		case task.OpDelete:
+			errs[i] = r.delete(w.ID, w.Entity.Version)
+		default:
+			errs[i] = fmt.Errorf("unknown bulk operation %q", w.Op)
+		}
+
+		if errs[i] != nil && atomic {
+			j.rollback(n)
+			return
+		}
+
+		if errs[i] == nil {
+			events = append(events, w.Events...)
+		}
+	}
+
+	r.db.addTaskEvents(events)
+
+	return
+}
+
+// Lock does nothing, a unit of work on the memory store keeps the writes of
+// other requests out until it ends anyway.
+func (r *TaskRepository) Lock(ctx context.Context, ids ...string) (err error) {
+	return
+}
+
+func (r *TaskRepository) Restore(ctx context.Context, id string) (err error) {
+	defer r.db.write(ctx)()
+
+	t, ok := r.db.tasks[id]
+	if !ok || t.DeletedAt == nil {
+		return task.ErrNotFound
+	}
+
+	t.DeletedAt = nil
+	t.Version++
+	put(r.db, r.db.tasks, id, t)
+
+	return
+}
+
+func (r *TaskRepository) Purge(ctx context.Context, before time.Time) (n int64, err error) {
+	defer r.db.write(ctx)()
+
+	for id, t := range r.db.tasks {
+		if purged(t.DeletedAt, before) {
+			r.db.dropTask(id)
+			n++
+		}
+	}
+
+	return
+}
+
+func (r *TaskRepository) List(ctx context.Context, filter task.Filter, page domain.PageRequest) (tasks domain.Page[task.Entity], err error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	rows := []task.Entity{}
+	for _, t := range r.db.tasks {
+		if !visible(ctx, t.DeletedAt) {
+			continue
+		}
+
+		ok, err := r.match(t, filter)
+		if err != nil {
+			return tasks, err
+		}
+
+		if ok {
+			rows = append(rows, t)
+		}
+	}
+
+	sortByKey(rows, taskKey)
+
+	return paginate(rows, page, taskKey)
+}
+
+func (r *TaskRepository) Search(ctx context.Context, filter task.Filter, page domain.PageRequest) (tasks domain.Page[task.Entity], err error) {
+	tasks, err = r.List(ctx, filter, page)
+	if err != nil {
+		return
+	}
+
+	if tasks.Total == 0 {
+		err = task.ErrNotFound
+		return
+	}
+
+	return
+}
+
+func (r *TaskRepository) match(t task.Entity, filter task.Filter) (bool, error) {
+	for _, c := range filter.Conditions {
+		if c.Field == task.FieldLabel {
+			ok, err := r.matchLabels(t, c)
+			if !ok || err != nil {
+				return false, err
+			}
+			continue
+		}
+
+		if c.Field == task.FieldOverdue {
+			if c.Operator != task.OpEq || len(c.Values) != 1 {
+				return false, task.ErrSearch
+			}
+
+			if t.Overdue(time.Now().UTC()) != (c.Values[0] == "true") {
+				return false, nil
+			}
+			continue
+		}
+
+		field := r.prepareFilterArg(c.Field)
+		if field == nil || len(c.Values) == 0 {
+			return false, task.ErrSearch
+		}
+
+		value := field(t)
+
+		var ok bool
+		switch c.Operator {
+		case task.OpEq:
+			ok = value == c.Values[0]
+		case task.OpIn:
+			for _, v := range c.Values {
+				ok = ok || value == v
+			}
+		// like NULL in postgres an empty value is never in a range
+		case task.OpGte:
+			ok = value != "" && value >= c.Values[0]
+		case task.OpLte:
+			ok = value != "" && value <= c.Values[0]
+		case task.OpContains:
+			ok = strings.Contains(strings.ToLower(value), strings.ToLower(c.Values[0]))
+		default:
+			return false, task.ErrSearch
+		}
+
+		if !ok {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// matchLabels checks the names of the labels on the task, the caller holds
+// the read lock.
+func (r *TaskRepository) matchLabels(t task.Entity, c task.Condition) (bool, error) {
+	names := map[string]bool{}
+	for labelID := range r.db.taskLabels[t.ID] {
+		names[r.db.labels[labelID].Name] = true
+	}
+
+	switch c.Operator {
+	case task.OpEq, task.OpIn:
+		for _, v := range c.Values {
+			if names[v] {
+				return true, nil
+			}
+		}
+		return false, nil
+	case task.OpAll:
+		for _, v := range c.Values {
+			if !names[v] {
+				return false, nil
+			}
+		}
+		return true, nil
+	default:
+		return false, task.ErrSearch
+	}
+}
+
+func (r *TaskRepository) prepareFilterArg(field task.Field) func(task.Entity) string {
+	switch field {
+	case task.FieldTitle:
+		return func(t task.Entity) string { return t.Title }
+	case task.FieldDescription:
+		return func(t task.Entity) string { return t.Description }
+	case task.FieldPriority:
+		return func(t task.Entity) string { return t.Priority }
+	case task.FieldStatus:
+		return func(t task.Entity) string { return t.Status }
+	case task.FieldAuthorID:
+		return func(t task.Entity) string { return t.AuthorID }
+	case task.FieldAssigneeID:
+		return func(t task.Entity) string { return t.AssigneeID }
+	case task.FieldProjectID:
+		return func(t task.Entity) string { return t.ProjectID }
+	case task.FieldParentID:
+		return func(t task.Entity) string { return t.ParentID }
+	case task.FieldSprintID:
+		return func(t task.Entity) string { return t.SprintID }
+	case task.FieldMilestoneID:
+		return func(t task.Entity) string { return t.MilestoneID }
+	case task.FieldCreatedAt:
+		return func(t task.Entity) string { return string(t.CreatedAt) }
+	case task.FieldDueDate:
+		return func(t task.Entity) string { return string(t.DueDate) }
+	case task.FieldCompletedAt:
+		return func(t task.Entity) string {
+			if t.CompletedAt == nil {
+				return ""
+			}
+			return t.CompletedAt.Format(domain.DateLayout)
+		}
+	default:
+		return nil
+	}
+}
+
+func (r *TaskRepository) FullTextSearch(ctx context.Context, query string, limit int) (tasks []domain.Ranked[task.Entity], err error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	tasks = []domain.Ranked[task.Entity]{}
+	for _, t := range r.db.tasks {
+		if t.DeletedAt != nil {
+			continue
+		}
+
+		if rank := rank(query, weighted{t.Title, 1}, weighted{t.Description, 0.4}); rank > 0 {
+			tasks = append(tasks, domain.Ranked[task.Entity]{Entity: t, Rank: rank})
+		}
+	}
+
+	return sortRanked(tasks, taskKey, limit), nil
+}
+
+func taskKey(t task.Entity) domain.Cursor {
+	return domain.Cursor{Date: string(t.CreatedAt), ID: t.ID}
+}
@@ -0,0 +1,111 @@
+package memory
+
+import (
+	"context"
+
+	"project-management/internal/domain/task"
+)
+
+type TaskDependencyRepository struct {
+	db *DB
+}
+
+func NewTaskDependencyRepository(db *DB) *TaskDependencyRepository {
+	if db == nil {
+		panic("db is required")
+	}
+
+	return &TaskDependencyRepository{
+		db: db,
+	}
+}
+
+func (r *TaskDependencyRepository) AddDependency(ctx context.Context, blockerID, blockedID string) (err error) {
+	defer r.db.write(ctx)()
+
+	if blockerID == blockedID {
+		return task.ErrSelfDependency
+	}
+
+	// REFERENCES tasks(id)
+	if _, ok := r.db.tasks[blockerID]; !ok {
+		return task.ErrNotFound
+	}
+	if _, ok := r.db.tasks[blockedID]; !ok {
+		return task.ErrNotFound
+	}
+
+	if r.db.blockers[blockedID][blockerID] {
+		return task.ErrDependencyExists
+	}
+
+	if r.db.blockers[blockedID] == nil {
+		put(r.db, r.db.blockers, blockedID, map[string]bool{})
+	}
+	put(r.db, r.db.blockers[blockedID], blockerID, true)
+
+	return
+}
+
+// Lock only checks the tasks, a unit of work on the memory store keeps the
+// writes of other requests out until it ends anyway.
+func (r *TaskDependencyRepository) Lock(ctx context.Context, taskIDs ...string) (err error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	for _, id := range taskIDs {
+		if t, ok := r.db.tasks[id]; !ok || t.DeletedAt != nil {
+			return task.ErrNotFound
+		}
+	}
+
+	return
+}
+
+func (r *TaskDependencyRepository) RemoveDependency(ctx context.Context, blockerID, blockedID string) (err error) {
+	defer r.db.write(ctx)()
+
+	if !r.db.blockers[blockedID][blockerID] {
+		return task.ErrDependencyNotFound
+	}
+
+	remove(r.db, r.db.blockers[blockedID], blockerID)
+
+	return
+}
+
+func (r *TaskDependencyRepository) Blockers(ctx context.Context, taskID string) (tasks []task.Entity, err error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	tasks = []task.Entity{}
+	for blockerID := range r.db.blockers[taskID] {
+		if t, ok := r.db.tasks[blockerID]; ok && visible(ctx, t.DeletedAt) {
+			tasks = append(tasks, t)
+		}
+	}
+
+	sortByKey(tasks, taskKey)
+
+	return
+}
+
+func (r *TaskDependencyRepository) Dependents(ctx context.Context, taskID string) (tasks []task.Entity, err error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	tasks = []task.Entity{}
+	for blockedID, blockers := range r.db.blockers {
+		if !blockers[taskID] {
+			continue
+		}
+
+		if t, ok := r.db.tasks[blockedID]; ok && visible(ctx, t.DeletedAt) {
+			tasks = append(tasks, t)
+		}
+	}
+
+	sortByKey(tasks, taskKey)
+
+	return
+}
@@ -0,0 +1,46 @@
+package memory
+
+import (
+	"context"
+
+	"project-management/internal/domain/project"
+	"project-management/internal/domain/task"
+)
+
+type TaskWorkflowRepository struct {
+	db *DB
+}
+
+func NewTaskWorkflowRepository(db *DB) *TaskWorkflowRepository {
+	if db == nil {
+		panic("db is required")
+	}
+
+	return &TaskWorkflowRepository{
+		db: db,
+	}
+}
+
+func (r *TaskWorkflowRepository) GetWorkflow(ctx context.Context, projectID string) (w task.Workflow, err error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	w, ok := r.db.workflows[projectID]
+	if !ok {
+		err = task.ErrWorkflowNotFound
+	}
+
+	return
+}
+
+func (r *TaskWorkflowRepository) SaveWorkflow(ctx context.Context, projectID string, w task.Workflow) (err error) {
+	defer r.db.write(ctx)()
+
+	if _, ok := r.db.projects[projectID]; !ok {
+		return project.ErrNotFound
+	}
+
+	put(r.db, r.db.workflows, projectID, w)
+
+	return
+}
@@ -0,0 +1,59 @@
+package memory
+
+import "context"
+
+type txKey struct{}
+
+// journal lists how to undo the changes of a unit of work, newest last.
+type journal struct {
+	undo []func()
+}
+
+func (j *journal) add(undo func()) {
+	j.undo = append(j.undo, undo)
+}
+
+// rollback undoes the changes journaled after the first n, the caller holds
+// the write lock.
+func (j *journal) rollback(n int) {
+	for i := len(j.undo) - 1; i >= n; i-- {
+		j.undo[i]()
+	}
+
+	j.undo = j.undo[:n]
+}
+
+// TxManager runs units of work on the memory store. They run one at a time,
+// writes outside of them wait for the running one to end, and a failing one
+// undoes its own changes from the journal it keeps in the context.
+type TxManager struct {
+	db *DB
+}
+
+func NewTxManager(db *DB) *TxManager {
+	if db == nil {
+		panic("db is required")
+	}
+
+	return &TxManager{
+		db: db,
+	}
+}
+
+func (m *TxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	if _, ok := ctx.Value(txKey{}).(*journal); ok {
+		return fn(ctx)
+	}
+
+	m.db.work.Lock()
+	defer m.db.work.Unlock()
+
+	j := &journal{}
+	if err = fn(context.WithValue(ctx, txKey{}, j)); err != nil {
+		m.db.mu.Lock()
+		j.rollback(0)
+		m.db.mu.Unlock()
+	}
+
+	return
+}
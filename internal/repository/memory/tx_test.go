@@ -0,0 +1,107 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"project-management/internal/domain/task"
+)
+
+func TestTxManagerWithinTx(t *testing.T) {
+	errFailed := errors.New("failed")
+
+	tests := []struct {
+		name string
+		fn   func(ctx context.Context, r *TaskRepository, m *TxManager) error
+		want []string
+	}{
+		{
+			name: "commit keeps the writes",
+			fn: func(ctx context.Context, r *TaskRepository, m *TxManager) error {
+				_, err := r.Create(ctx, task.Entity{ID: "a"})
+				return err
+			},
+			want: []string{"a", "kept"},
+		},
+		{
+			name: "rollback undoes the writes",
+			fn: func(ctx context.Context, r *TaskRepository, m *TxManager) error {
+				r.Create(ctx, task.Entity{ID: "a"})
+				r.Patch(ctx, "kept", task.Entity{Title: "changed"}, nil)
+				r.Delete(ctx, "kept", 0)
+				return errFailed
+			},
+			want: []string{"kept"},
+		},
+		{
+			name: "nested unit joins the outer one",
+			fn: func(ctx context.Context, r *TaskRepository, m *TxManager) error {
+				r.Create(ctx, task.Entity{ID: "a"})
+				return m.WithinTx(ctx, func(ctx context.Context) error {
+					r.Create(ctx, task.Entity{ID: "b"})
+					return errFailed
+				})
+			},
+			want: []string{"kept"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := New()
+			r := NewTaskRepository(db)
+			m := NewTxManager(db)
+			ctx := context.Background()
+
+			if _, err := r.Create(ctx, task.Entity{ID: "kept", Title: "kept"}); err != nil {
+				t.Fatal(err)
+			}
+
+			m.WithinTx(ctx, func(ctx context.Context) error { return tt.fn(ctx, r, m) })
+
+			for _, id := range tt.want {
+				if _, ok := db.tasks[id]; !ok {
+					t.Errorf("task %q is missing", id)
+				}
+			}
+			if len(db.tasks) != len(tt.want) {
+				t.Errorf("got %d tasks, want %d", len(db.tasks), len(tt.want))
+			}
+			if kept := db.tasks["kept"]; kept.Title != "kept" || kept.DeletedAt != nil {
+				t.Errorf("kept task was changed: %+v", kept)
+			}
+		})
+	}
+}
+
+func TestTxManagerRollbackKeepsOtherWrites(t *testing.T) {
+	db := New()
+	r := NewTaskRepository(db)
+	m := NewTxManager(db)
+	ctx := context.Background()
+
+	done := make(chan error)
+	m.WithinTx(ctx, func(txCtx context.Context) error {
+		r.Create(txCtx, task.Entity{ID: "a"})
+
+		// a write of another request waits for the unit of work to end
+		go func() {
+			_, err := r.Create(ctx, task.Entity{ID: "other"})
+			done <- err
+		}()
+
+		return errors.New("failed")
+	})
+
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := db.tasks["a"]; ok {
+		t.Error("write of the failed unit of work was kept")
+	}
+	if _, ok := db.tasks["other"]; !ok {
+		t.Error("write of another request was lost")
+	}
+}
@@ -0,0 +1,309 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"project-management/internal/domain"
+	"project-management/internal/domain/user"
+)
+
+type UserRepository struct {
+	db *DB
+}
+
+func NewUserRepository(db *DB) *UserRepository {
+	if db == nil {
+		panic("db is required")
+	}
+
+	return &UserRepository{
+		db: db,
+	}
+}
+
+func (r *UserRepository) Create(ctx context.Context, u user.Entity) (id string, err error) {
+	defer r.db.write(ctx)()
+
+	if _, ok := r.db.users[u.ID]; ok {
+		return "", user.ErrExists
+	}
+
+	if r.emailTaken(u.Email, u.ID) {
+		return "", user.ErrExists
+	}
+
+	u.Version = 1
+	put(r.db, r.db.users, u.ID, u)
+
+	return u.ID, nil
+}
+
+func (r *UserRepository) Update(ctx context.Context, id string, u user.Entity) (err error) {
+	defer r.db.write(ctx)()
+
+	data, ok := r.db.users[id]
+	if !ok || data.DeletedAt != nil {
+		return user.ErrNotFound
+	}
+
+	if !versionMatches(data.Version, u.Version) {
+		return domain.ErrVersionConflict
+	}
+
+	if u.Name != "" {
+		data.Name = u.Name
+	}
+
+	if u.Email != "" {
+		if r.emailTaken(u.Email, id) {
+			return user.ErrExists
+		}
+		data.Email = u.Email
+	}
+
+	if u.Role != "" {
+		data.Role = u.Role
+	}
+
+	if u.PasswordHash != "" {
+		data.PasswordHash = u.PasswordHash
+	}
+
+	data.Version++
+	put(r.db, r.db.users, id, data)
+
+	return
+}
+
+func (r *UserRepository) Get(ctx context.Context, id string) (u user.Entity, err error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	u, ok := r.db.users[id]
+	if !ok || !visible(ctx, u.DeletedAt) {
+		return user.Entity{}, user.ErrNotFound
+	}
+
+	return
+}
+
+func (r *UserRepository) GetByEmail(ctx context.Context, email string) (u user.Entity, err error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	for _, u := range r.db.users {
+		if u.Email == email && visible(ctx, u.DeletedAt) {
+			return u, nil
+		}
+	}
+
+	err = user.ErrNotFound
+
+	return
+}
+
+// Lock only checks the user, a unit of work on the memory store keeps the
+// writes of other requests out until it ends anyway.
+func (r *UserRepository) Lock(ctx context.Context, id string) (err error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	u, ok := r.db.users[id]
+	if !ok || u.DeletedAt != nil {
+		return user.ErrNotFound
+	}
+
+	return
+}
+
+func (r *UserRepository) Delete(ctx context.Context, id string, version int64) (err error) {
+	defer r.db.write(ctx)()
+
+	u, ok := r.db.users[id]
+	if !ok || u.DeletedAt != nil {
+		return user.ErrNotFound
+	}
+
+	if !versionMatches(u.Version, version) {
+		return domain.ErrVersionConflict
+	}
+
+	now := time.Now().UTC()
+	u.DeletedAt = &now
+	u.Version++
+	put(r.db, r.db.users, id, u)
+
+	return
+}
+
+func (r *UserRepository) Restore(ctx context.Context, id string) (err error) {
+	defer r.db.write(ctx)()
+
+	u, ok := r.db.users[id]
+	if !ok || u.DeletedAt == nil {
+		return user.ErrNotFound
+	}
+
+	if r.emailTaken(u.Email, id) {
+		return user.ErrEmailTaken
+	}
+
+	u.DeletedAt = nil
+	u.Version++
+	put(r.db, r.db.users, id, u)
+
+	return
+}
+
+func (r *UserRepository) Purge(ctx context.Context, before time.Time) (n int64, err error) {
+	defer r.db.write(ctx)()
+
+	for id, u := range r.db.users {
+		if purged(u.DeletedAt, before) {
+			r.purge(id)
+			n++
+		}
+	}
+
+	return
+}
+
+// purge removes the user for good, the caller holds the write lock.
+func (r *UserRepository) purge(id string) {
+	remove(r.db, r.db.users, id)
+
+	// ON DELETE SET NULL
+	for k, p := range r.db.projects {
+		if p.ManagerID == id {
+			p.ManagerID = ""
+			put(r.db, r.db.projects, k, p)
+		}
+	}
+
+	for k, t := range r.db.tasks {
+		if t.AuthorID == id {
+			t.AuthorID = ""
+		}
+		if t.AssigneeID == id {
+			t.AssigneeID = ""
+		}
+		put(r.db, r.db.tasks, k, t)
+	}
+
+	for _, events := range r.db.events {
+		for i := range events {
+			if events[i].ActorID == id {
+				events[i].ActorID = ""
+			}
+		}
+	}
+
+	for k, c := range r.db.comments {
+		if c.AuthorID == id {
+			c.AuthorID = ""
+			put(r.db, r.db.comments, k, c)
+		}
+	}
+
+	for k, a := range r.db.attachments {
+		if a.UploaderID == id {
+			a.UploaderID = ""
+			put(r.db, r.db.attachments, k, a)
+		}
+	}
+
+	// ON DELETE CASCADE
+	for _, members := range r.db.members {
+		remove(r.db, members, id)
+	}
+}
+
+func (r *UserRepository) List(ctx context.Context, page domain.PageRequest) (users domain.Page[user.Entity], err error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	rows := []user.Entity{}
+	for _, u := range r.db.users {
+		if visible(ctx, u.DeletedAt) {
+			rows = append(rows, u)
+		}
+	}
+
+	sortByKey(rows, userKey)
+
+	return paginate(rows, page, userKey)
+}
+
+func (r *UserRepository) Search(ctx context.Context, filter, value string, page domain.PageRequest) (users domain.Page[user.Entity], err error) {
+	field := r.prepareFilterArg(filter)
+	if field == nil {
+		err = user.ErrSearch
+		return
+	}
+
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	rows := []user.Entity{}
+	for _, u := range r.db.users {
+		if field(u) == value && visible(ctx, u.DeletedAt) {
+			rows = append(rows, u)
+		}
+	}
+
+	if len(rows) == 0 {
+		err = user.ErrNotFound
+		return
+	}
+
+	sortByKey(rows, userKey)
+
+	return paginate(rows, page, userKey)
+}
+
+// emailTaken mirrors the unique index on the emails of live users.
+func (r *UserRepository) emailTaken(email, exceptID string) bool {
+	for _, u := range r.db.users {
+		if u.Email == email && u.ID != exceptID && u.DeletedAt == nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r *UserRepository) prepareFilterArg(arg string) func(user.Entity) string {
+	switch arg {
+	case "name":
+		return func(u user.Entity) string { return u.Name }
+	case "email":
+		return func(u user.Entity) string { return u.Email }
+	case "role":
+		return func(u user.Entity) string { return u.Role }
+	default:
+		return nil
+	}
+}
+
+func (r *UserRepository) FullTextSearch(ctx context.Context, query string, limit int) (users []domain.Ranked[user.Entity], err error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	users = []domain.Ranked[user.Entity]{}
+	for _, u := range r.db.users {
+		if u.DeletedAt != nil {
+			continue
+		}
+
+		if rank := rank(query, weighted{u.Name, 1}, weighted{u.Email, 0.4}); rank > 0 {
+			users = append(users, domain.Ranked[user.Entity]{Entity: u, Rank: rank})
+		}
+	}
+
+	return sortRanked(users, userKey, limit), nil
+}
+
+func userKey(u user.Entity) domain.Cursor {
+	return domain.Cursor{Date: string(u.RegistrationDate), ID: u.ID}
+}
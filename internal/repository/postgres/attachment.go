@@ -0,0 +1,105 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"project-management/internal/domain/attachment"
+	"project-management/internal/domain/task"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+const attachmentColumns = "id, task_id, name, content_type, size, COALESCE(uploader_id, '') AS uploader_id, created_at"
+
+type AttachmentRepository struct {
+	db *sqlx.DB
+}
+
+func NewAttachmentRepository(db *sqlx.DB) *AttachmentRepository {
+	if db == nil {
+		panic("db is required")
+	}
+
+	return &AttachmentRepository{
+		db: db,
+	}
+}
+
+func (r *AttachmentRepository) Create(ctx context.Context, data attachment.Entity) (id string, err error) {
+	q := `
+		INSERT INTO task_attachments (id, task_id, name, content_type, size, uploader_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`
+
+	args := []any{data.ID, data.TaskID, data.Name, data.ContentType, data.Size, nullable(data.UploaderID), data.CreatedAt}
+
+	if err = conn(ctx, r.db).QueryRowxContext(ctx, q, args...).Scan(&id); err != nil {
+		if err, ok := err.(*pq.Error); ok && err.Code.Name() == "foreign_key_violation" {
+			return "", task.ErrNotFound
+		}
+		return
+	}
+
+	return
+}
+
+func (r *AttachmentRepository) Get(ctx context.Context, id string) (data attachment.Entity, err error) {
+	q := "SELECT " + attachmentColumns + " FROM task_attachments WHERE id = $1"
+
+	if err = sqlx.GetContext(ctx, conn(ctx, r.db), &data, q, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = attachment.ErrNotFound
+		}
+		return
+	}
+
+	return
+}
+
+func (r *AttachmentRepository) List(ctx context.Context, taskID string) (attachments []attachment.Entity, err error) {
+	attachments = []attachment.Entity{}
+
+	q := "SELECT " + attachmentColumns + " FROM task_attachments WHERE task_id = $1 ORDER BY created_at, id"
+
+	if err = sqlx.SelectContext(ctx, conn(ctx, r.db), &attachments, q, taskID); err != nil {
+		return
+	}
+
+	return
+}
+
+func (r *AttachmentRepository) Delete(ctx context.Context, id string) (err error) {
+	q := `
+	DELETE FROM task_attachments WHERE id = $1 RETURNING id
+	`
+
+	if err = conn(ctx, r.db).QueryRowxContext(ctx, q, id).Scan(&id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = attachment.ErrNotFound
+		}
+		return
+	}
+
+	return
+}
+
+func (r *AttachmentRepository) Expired(ctx context.Context, before time.Time) (attachments []attachment.Entity, err error) {
+	attachments = []attachment.Entity{}
+
+	q := `
+	SELECT a.id, a.task_id, a.name, a.content_type, a.size, COALESCE(a.uploader_id, '') AS uploader_id, a.created_at
+	FROM task_attachments a JOIN tasks t ON t.id = a.task_id
+	WHERE t.deleted_at < $1
+	`
+
+	if err = sqlx.SelectContext(ctx, conn(ctx, r.db), &attachments, q, before); err != nil {
+		return
+	}
+
+	return
+}
@@ -0,0 +1,112 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"project-management/internal/domain"
+	"project-management/internal/domain/audit"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const auditColumns = "id, actor_id, entity_type, entity_id, action, changes, created_at"
+
+type AuditRepository struct {
+	db *sqlx.DB
+}
+
+func NewAuditRepository(db *sqlx.DB) *AuditRepository {
+	if db == nil {
+		panic("db is required")
+	}
+
+	return &AuditRepository{
+		db: db,
+	}
+}
+
+func (r *AuditRepository) Record(ctx context.Context, e audit.Entry) (err error) {
+	q := `
+		INSERT INTO audit_log (actor_id, entity_type, entity_id, action, changes, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	args := []any{e.ActorID, e.EntityType, e.EntityID, e.Action, e.Changes, e.CreatedAt}
+
+	_, err = conn(ctx, r.db).ExecContext(ctx, q, args...)
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+// List returns the entries in the order they were written. The serial id
+// already follows that order, so the cursor only needs the id.
+func (r *AuditRepository) List(ctx context.Context, filter audit.Filter, page domain.PageRequest) (res domain.Page[audit.Entry], err error) {
+	res.Items = []audit.Entry{}
+
+	where, args := []string{"TRUE"}, []any{}
+
+	if filter.EntityType != "" {
+		args = append(args, filter.EntityType)
+		where = append(where, fmt.Sprintf("entity_type = $%d", len(args)))
+	}
+
+	if filter.EntityID != "" {
+		args = append(args, filter.EntityID)
+		where = append(where, fmt.Sprintf("entity_id = $%d", len(args)))
+	}
+
+	if !filter.Since.IsZero() {
+		args = append(args, filter.Since)
+		where = append(where, fmt.Sprintf("created_at >= $%d", len(args)))
+	}
+
+	q := "SELECT count(*) FROM audit_log WHERE " + strings.Join(where, " AND ")
+	if err = sqlx.GetContext(ctx, conn(ctx, r.db), &res.Total, q, args...); err != nil {
+		return
+	}
+
+	if page.Cursor != "" {
+		var after int64
+		if after, err = auditCursor(page.Cursor); err != nil {
+			return
+		}
+
+		args = append(args, after)
+		where = append(where, fmt.Sprintf("id > $%d", len(args)))
+	}
+
+	args = append(args, page.Limit+1, page.Offset)
+	q = fmt.Sprintf("SELECT %s FROM audit_log WHERE %s ORDER BY id LIMIT $%d OFFSET $%d", auditColumns, strings.Join(where, " AND "), len(args)-1, len(args))
+
+	if err = sqlx.SelectContext(ctx, conn(ctx, r.db), &res.Items, q, args...); err != nil {
+		return
+	}
+
+	if len(res.Items) > page.Limit {
+		res.Items = res.Items[:page.Limit]
+		last := res.Items[len(res.Items)-1]
+		res.NextCursor = domain.EncodeCursor(last.CreatedAt.Format(domain.DateLayout), strconv.FormatInt(last.ID, 10))
+	}
+
+	return
+}
+
+func auditCursor(s string) (int64, error) {
+	cursor, err := domain.DecodeCursor(s)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := strconv.ParseInt(cursor.ID, 10, 64)
+	if err != nil {
+		return 0, domain.ErrInvalidCursor
+	}
+
+	return id, nil
+}
@@ -0,0 +1,113 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"project-management/internal/domain/comment"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+const commentColumns = "id, task_id, COALESCE(parent_id, '') AS parent_id, COALESCE(author_id, '') AS author_id, body, created_at, updated_at, deleted_at"
+
+type CommentRepository struct {
+	db *sqlx.DB
+}
+
+func NewCommentRepository(db *sqlx.DB) *CommentRepository {
+	if db == nil {
+		panic("db is required")
+	}
+
+	return &CommentRepository{
+		db: db,
+	}
+}
+
+func (r *CommentRepository) Create(ctx context.Context, data comment.Entity) (id string, err error) {
+	q := `
+		INSERT INTO task_comments (id, task_id, parent_id, author_id, body, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`
+
+	args := []any{data.ID, data.TaskID, nullable(data.ParentID), nullable(data.AuthorID), data.Body, data.CreatedAt, data.UpdatedAt}
+
+	if err = conn(ctx, r.db).QueryRowxContext(ctx, q, args...).Scan(&id); err != nil {
+		if err, ok := err.(*pq.Error); ok && err.Code.Name() == "foreign_key_violation" {
+			return "", comment.ErrParentNotFound
+		}
+		return
+	}
+
+	return
+}
+
+func (r *CommentRepository) Get(ctx context.Context, id string) (data comment.Entity, err error) {
+	q := "SELECT " + commentColumns + " FROM task_comments WHERE id = $1"
+
+	if err = sqlx.GetContext(ctx, conn(ctx, r.db), &data, q, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = comment.ErrNotFound
+		}
+		return
+	}
+
+	return
+}
+
+func (r *CommentRepository) List(ctx context.Context, taskID string) (comments []comment.Entity, err error) {
+	comments = []comment.Entity{}
+
+	q := "SELECT " + commentColumns + " FROM task_comments WHERE task_id = $1 ORDER BY created_at, id"
+
+	if err = sqlx.SelectContext(ctx, conn(ctx, r.db), &comments, q, taskID); err != nil {
+		return
+	}
+
+	return
+}
+
+func (r *CommentRepository) Update(ctx context.Context, id, body string) (err error) {
+	q := `
+	UPDATE task_comments SET body = $2, updated_at = now() WHERE id = $1 AND deleted_at IS NULL RETURNING id
+	`
+
+	if err = conn(ctx, r.db).QueryRowxContext(ctx, q, id, body).Scan(&id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = comment.ErrNotFound
+		}
+		return
+	}
+
+	return
+}
+
+func (r *CommentRepository) Delete(ctx context.Context, id string) (err error) {
+	q := `
+	UPDATE task_comments SET body = '', deleted_at = now(), updated_at = now()
+	WHERE id = $1 AND EXISTS (SELECT 1 FROM task_comments WHERE parent_id = $1)
+	RETURNING id
+	`
+
+	err = conn(ctx, r.db).QueryRowxContext(ctx, q, id).Scan(&id)
+	if !errors.Is(err, sql.ErrNoRows) {
+		return
+	}
+
+	q = `
+	DELETE FROM task_comments WHERE id = $1 RETURNING id
+	`
+
+	if err = conn(ctx, r.db).QueryRowxContext(ctx, q, id).Scan(&id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = comment.ErrNotFound
+		}
+		return
+	}
+
+	return
+}
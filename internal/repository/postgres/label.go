@@ -0,0 +1,184 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"project-management/internal/domain/label"
+	"project-management/internal/domain/project"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+const labelColumns = "id, project_id, name, color"
+
+type LabelRepository struct {
+	db *sqlx.DB
+}
+
+func NewLabelRepository(db *sqlx.DB) *LabelRepository {
+	if db == nil {
+		panic("db is required")
+	}
+
+	return &LabelRepository{
+		db: db,
+	}
+}
+
+func (r *LabelRepository) Create(ctx context.Context, data label.Entity) (id string, err error) {
+	q := `
+		INSERT INTO labels (id, project_id, name, color)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`
+
+	args := []any{data.ID, data.ProjectID, data.Name, data.Color}
+
+	if err = conn(ctx, r.db).QueryRowxContext(ctx, q, args...).Scan(&id); err != nil {
+		if err, ok := err.(*pq.Error); ok {
+			switch err.Code.Name() {
+			case "unique_violation":
+				return "", label.ErrExists
+			case "foreign_key_violation":
+				return "", project.ErrNotFound
+			}
+		}
+		return
+	}
+
+	return
+}
+
+func (r *LabelRepository) Get(ctx context.Context, id string) (data label.Entity, err error) {
+	q := "SELECT " + labelColumns + " FROM labels WHERE id = $1"
+
+	if err = sqlx.GetContext(ctx, conn(ctx, r.db), &data, q, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = label.ErrNotFound
+		}
+		return
+	}
+
+	return
+}
+
+func (r *LabelRepository) List(ctx context.Context, projectID string) (labels []label.Entity, err error) {
+	labels = []label.Entity{}
+
+	q := "SELECT " + labelColumns + " FROM labels WHERE project_id = $1 ORDER BY name"
+
+	if err = sqlx.SelectContext(ctx, conn(ctx, r.db), &labels, q, projectID); err != nil {
+		return
+	}
+
+	return
+}
+
+func (r *LabelRepository) Update(ctx context.Context, id string, data label.Entity) (err error) {
+	var sets []string
+	args := []any{id}
+
+	if data.Name != "" {
+		args = append(args, data.Name)
+		sets = append(sets, fmt.Sprintf("name=$%d", len(args)))
+	}
+
+	if data.Color != "" {
+		args = append(args, data.Color)
+		sets = append(sets, fmt.Sprintf("color=$%d", len(args)))
+	}
+
+	if len(sets) == 0 {
+		return
+	}
+
+	q := fmt.Sprintf("UPDATE labels SET %s WHERE id = $1 RETURNING id", strings.Join(sets, ", "))
+
+	if err = conn(ctx, r.db).QueryRowxContext(ctx, q, args...).Scan(&id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return label.ErrNotFound
+		}
+		if err, ok := err.(*pq.Error); ok && err.Code.Name() == "unique_violation" {
+			return label.ErrExists
+		}
+		return
+	}
+
+	return
+}
+
+func (r *LabelRepository) Delete(ctx context.Context, id string) (err error) {
+	q := `
+	DELETE FROM labels WHERE id = $1 RETURNING id
+	`
+
+	if err = conn(ctx, r.db).QueryRowxContext(ctx, q, id).Scan(&id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = label.ErrNotFound
+		}
+		return
+	}
+
+	return
+}
+
+func (r *LabelRepository) TaskLabels(ctx context.Context, taskID string) (labels []label.Entity, err error) {
+	labels = []label.Entity{}
+
+	q := `
+	SELECT l.id, l.project_id, l.name, l.color
+	FROM labels l JOIN task_labels tl ON tl.label_id = l.id
+	WHERE tl.task_id = $1 ORDER BY l.name
+	`
+
+	if err = sqlx.SelectContext(ctx, conn(ctx, r.db), &labels, q, taskID); err != nil {
+		return
+	}
+
+	return
+}
+
+func (r *LabelRepository) Attach(ctx context.Context, taskID, labelID string) (err error) {
+	q := `
+	INSERT INTO task_labels (task_id, label_id) VALUES ($1, $2) ON CONFLICT DO NOTHING
+	`
+
+	if _, err = conn(ctx, r.db).ExecContext(ctx, q, taskID, labelID); err != nil {
+		if err, ok := err.(*pq.Error); ok && err.Code.Name() == "foreign_key_violation" {
+			return label.ErrNotFound
+		}
+		return
+	}
+
+	return
+}
+
+func (r *LabelRepository) Detach(ctx context.Context, taskID, labelID string) (err error) {
+	q := `
+	DELETE FROM task_labels WHERE task_id = $1 AND label_id = $2 RETURNING label_id
+	`
+
+	if err = conn(ctx, r.db).QueryRowxContext(ctx, q, taskID, labelID).Scan(&labelID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = label.ErrNotFound
+		}
+		return
+	}
+
+	return
+}
+
+func (r *LabelRepository) DetachAll(ctx context.Context, taskID string) (err error) {
+	q := `
+	DELETE FROM task_labels WHERE task_id = $1
+	`
+
+	_, err = conn(ctx, r.db).ExecContext(ctx, q, taskID)
+
+	return
+}
@@ -0,0 +1,154 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"project-management/internal/domain/milestone"
+	"project-management/internal/domain/project"
+	"project-management/internal/domain/task"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+const milestoneColumns = "id, project_id, title, description, due_date"
+
+type MilestoneRepository struct {
+	db *sqlx.DB
+}
+
+func NewMilestoneRepository(db *sqlx.DB) *MilestoneRepository {
+	if db == nil {
+		panic("db is required")
+	}
+
+	return &MilestoneRepository{
+		db: db,
+	}
+}
+
+func (r *MilestoneRepository) Create(ctx context.Context, data milestone.Entity) (id string, err error) {
+	q := `
+		INSERT INTO milestones (id, project_id, title, description, due_date)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`
+
+	args := []any{data.ID, data.ProjectID, data.Title, data.Description, data.DueDate}
+
+	if err = conn(ctx, r.db).QueryRowxContext(ctx, q, args...).Scan(&id); err != nil {
+		if err, ok := err.(*pq.Error); ok && err.Code.Name() == "foreign_key_violation" {
+			return "", project.ErrNotFound
+		}
+		return
+	}
+
+	return
+}
+
+func (r *MilestoneRepository) Get(ctx context.Context, id string) (data milestone.Entity, err error) {
+	q := "SELECT " + milestoneColumns + " FROM milestones WHERE id = $1"
+
+	if err = sqlx.GetContext(ctx, conn(ctx, r.db), &data, q, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = milestone.ErrNotFound
+		}
+		return
+	}
+
+	return
+}
+
+func (r *MilestoneRepository) List(ctx context.Context, projectID string) (milestones []milestone.Entity, err error) {
+	milestones = []milestone.Entity{}
+
+	q := "SELECT " + milestoneColumns + " FROM milestones WHERE project_id = $1 ORDER BY due_date, id"
+
+	if err = sqlx.SelectContext(ctx, conn(ctx, r.db), &milestones, q, projectID); err != nil {
+		return
+	}
+
+	return
+}
+
+func (r *MilestoneRepository) Update(ctx context.Context, id string, data milestone.Entity) (err error) {
+	var sets []string
+	args := []any{id}
+
+	if data.Title != "" {
+		args = append(args, data.Title)
+		sets = append(sets, fmt.Sprintf("title=$%d", len(args)))
+	}
+
+	if data.Description != "" {
+		args = append(args, data.Description)
+		sets = append(sets, fmt.Sprintf("description=$%d", len(args)))
+	}
+
+	if data.DueDate != "" {
+		args = append(args, data.DueDate)
+		sets = append(sets, fmt.Sprintf("due_date=$%d", len(args)))
+	}
+
+	if len(sets) == 0 {
+		return
+	}
+
+	q := fmt.Sprintf("UPDATE milestones SET %s WHERE id = $1 RETURNING id", strings.Join(sets, ", "))
+
+	if err = conn(ctx, r.db).QueryRowxContext(ctx, q, args...).Scan(&id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return milestone.ErrNotFound
+		}
+		return
+	}
+
+	return
+}
+
+func (r *MilestoneRepository) Delete(ctx context.Context, id string) (err error) {
+	q := `
+	DELETE FROM milestones WHERE id = $1 RETURNING id
+	`
+
+	if err = conn(ctx, r.db).QueryRowxContext(ctx, q, id).Scan(&id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = milestone.ErrNotFound
+		}
+		return
+	}
+
+	return
+}
+
+func (r *MilestoneRepository) Link(ctx context.Context, taskID, milestoneID string, events ...task.Event) (err error) {
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return
+	}
+	defer tx.Rollback()
+
+	q := `
+	UPDATE tasks SET milestone_id = $1, version = version + 1 WHERE id = $2 AND deleted_at IS NULL RETURNING id
+	`
+
+	if err = tx.QueryRowContext(ctx, q, nullable(milestoneID), taskID).Scan(&taskID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return task.ErrNotFound
+		}
+		if err, ok := err.(*pq.Error); ok && err.Code.Name() == "foreign_key_violation" {
+			return milestone.ErrNotFound
+		}
+		return
+	}
+
+	if err = insertTaskEvents(ctx, tx, events); err != nil {
+		return
+	}
+
+	return tx.Commit()
+}
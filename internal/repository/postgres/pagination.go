@@ -0,0 +1,62 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"project-management/internal/domain"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// listing describes a paginated SELECT over a single table with soft delete.
+// where may be empty and references its arguments as $1..$len(args).
+type listing struct {
+	table      string
+	columns    string
+	dateColumn string
+	where      string
+	args       []any
+}
+
+// paginate fetches one page of the listing ordered by (dateColumn, id), one row
+// more than requested is read to find out whether a next page exists.
+func paginate[T any](ctx context.Context, db sqlx.QueryerContext, l listing, page domain.PageRequest, cursorOf func(T) string) (res domain.Page[T], err error) {
+	res.Items = []T{}
+
+	where := visible(ctx)
+	if l.where != "" {
+		where = fmt.Sprintf("%s AND (%s)", where, l.where)
+	}
+
+	q := fmt.Sprintf("SELECT count(*) FROM %s WHERE %s", l.table, where)
+	if err = sqlx.GetContext(ctx, db, &res.Total, q, l.args...); err != nil {
+		return
+	}
+
+	args := append([]any{}, l.args...)
+
+	if page.Cursor != "" {
+		cursor, err := domain.DecodeCursor(page.Cursor)
+		if err != nil {
+			return res, err
+		}
+
+		args = append(args, cursor.Date, cursor.ID)
+		where = fmt.Sprintf("%s AND (%s, id) > ($%d::date, $%d)", where, l.dateColumn, len(args)-1, len(args))
+	}
+
+	args = append(args, page.Limit+1, page.Offset)
+	q = fmt.Sprintf("SELECT %s FROM %s WHERE %s ORDER BY %s, id LIMIT $%d OFFSET $%d", l.columns, l.table, where, l.dateColumn, len(args)-1, len(args))
+
+	if err = sqlx.SelectContext(ctx, db, &res.Items, q, args...); err != nil {
+		return
+	}
+
+	if len(res.Items) > page.Limit {
+		res.Items = res.Items[:page.Limit]
+		res.NextCursor = cursorOf(res.Items[len(res.Items)-1])
+	}
+
+	return
+}
@@ -1,9 +1,12 @@
 package postgres
 
 import (
+	"context"
 	_ "database/sql"
 	"fmt"
 	"project-management/config"
+	"project-management/internal/domain"
+	"strings"
 
 	_ "github.com/golang-migrate/migrate/v4/database/postgres"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
@@ -12,6 +15,56 @@ import (
 	"github.com/jmoiron/sqlx"
 )
 
+// likeEscaper escapes the wildcards of a LIKE pattern, the default escape
+// character is a backslash.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// nullable turns an empty id into NULL so that optional foreign keys can be
+// written from plain strings.
+func nullable(id string) any {
+	if id == "" {
+		return nil
+	}
+
+	return id
+}
+
+// versionCheck is the condition of a compare-and-swap on the version column,
+// argument n holds the expected version and zero matches any version.
+func versionCheck(n int) string {
+	return fmt.Sprintf("($%d::bigint = 0 OR version = $%d::bigint)", n, n)
+}
+
+// missingOrConflict tells why a compare-and-swap on a live row of the table
+// matched nothing: either the row is gone or it is at another version.
+func missingOrConflict(ctx context.Context, q sqlx.QueryerContext, table, id string, notFound error) error {
+	var exists bool
+
+	err := sqlx.GetContext(ctx, q, &exists, "SELECT EXISTS (SELECT 1 FROM "+table+" WHERE id = $1 AND deleted_at IS NULL)", id)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		return domain.ErrVersionConflict
+	}
+
+	return notFound
+}
+
+// visible is the condition selecting the rows the deleted scope of the context
+// allows, tables with soft delete use it in every read.
+func visible(ctx context.Context) string {
+	switch domain.DeletedScopeFromContext(ctx) {
+	case domain.IncludeDeleted:
+		return "TRUE"
+	case domain.OnlyDeleted:
+		return "deleted_at IS NOT NULL"
+	default:
+		return "deleted_at IS NULL"
+	}
+}
+
 type DB struct {
 	Client *sqlx.DB
 
@@ -6,13 +6,17 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
+	"project-management/internal/domain"
 	"project-management/internal/domain/project"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/lib/pq"
 )
 
+const projectColumns = "id, title, description, started_at, finished_at, COALESCE(manager_id, '') AS manager_id, deleted_at, version"
+
 type ProjectRepository struct {
 	db *sqlx.DB
 }
@@ -33,9 +37,9 @@ func (r *ProjectRepository) Create(ctx context.Context, p project.Entity) (id st
 		VALUES ($1, $2, $3, $4, $5, $6) RETURNING id
 	`
 
-	args := []any{p.ID, p.Title, p.Description, p.ManagerID, p.StartedAt, p.FinishedAt}
+	args := []any{p.ID, p.Title, p.Description, p.ManagerID, p.StartedAt, nullable(string(p.FinishedAt))}
 
-	err = r.db.QueryRowContext(ctx, q, args...).Scan(&id)
+	err = conn(ctx, r.db).QueryRowxContext(ctx, q, args...).Scan(&id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			err = project.ErrExists
@@ -50,15 +54,20 @@ func (r *ProjectRepository) Create(ctx context.Context, p project.Entity) (id st
 }
 
 func (r *ProjectRepository) Update(ctx context.Context, id string, p project.Entity) (err error) {
+	return r.Patch(ctx, id, p, nil)
+}
+
+func (r *ProjectRepository) Patch(ctx context.Context, id string, p project.Entity, clear []project.Field) (err error) {
 	sets, args := r.prepareArgs(p)
+	sets = append(sets, r.prepareClears(clear)...)
 	if len(sets) > 0 {
-		args = append(args, id)
-		q := fmt.Sprintf("UPDATE projects SET %s WHERE id = $%d RETURNING ID", strings.Join(sets, ", "), len(args))
+		args = append(args, id, p.Version)
+		q := fmt.Sprintf("UPDATE projects SET %s, version = version + 1 WHERE id = $%d AND deleted_at IS NULL AND %s RETURNING ID", strings.Join(sets, ", "), len(args)-1, versionCheck(len(args)))
 
-		err = r.db.QueryRowContext(ctx, q, args...).Scan(&id)
+		err = conn(ctx, r.db).QueryRowxContext(ctx, q, args...).Scan(&id)
 		if err != nil {
 			if errors.Is(err, sql.ErrNoRows) {
-				err = project.ErrNotFound
+				err = missingOrConflict(ctx, conn(ctx, r.db), "projects", id, project.ErrNotFound)
 			}
 		}
 	}
@@ -66,30 +75,100 @@ func (r *ProjectRepository) Update(ctx context.Context, id string, p project.Ent
 	return
 }
 
-func (r *ProjectRepository) Delete(ctx context.Context, id string) (err error) {
+// Delete moves the project and its live tasks to the trash with the same
+// deletion time, which is how Restore recognizes the tasks to bring back.
+func (r *ProjectRepository) Delete(ctx context.Context, id string, version int64) (err error) {
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return
+	}
+	defer tx.Rollback()
+
 	q := `
-	DELETE FROM projects
-	WHERE id = $1 RETURNING id
+	UPDATE projects SET deleted_at = now(), version = version + 1
+	WHERE id = $1 AND deleted_at IS NULL AND ` + versionCheck(2) + ` RETURNING deleted_at
 	`
 
-	err = r.db.QueryRowContext(ctx, q, id).Scan(&id)
+	var deletedAt time.Time
+	if err = tx.QueryRowContext(ctx, q, id, version).Scan(&deletedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = missingOrConflict(ctx, tx, "projects", id, project.ErrNotFound)
+		}
+		return
+	}
+
+	q = `
+	UPDATE tasks SET deleted_at = $1, version = version + 1 WHERE project_id = $2 AND deleted_at IS NULL
+	`
+
+	if _, err = tx.ExecContext(ctx, q, deletedAt, id); err != nil {
+		return
+	}
+
+	return tx.Commit()
+}
+
+// Restore brings the project back together with the tasks that were deleted
+// along with it, tasks deleted on their own stay in the trash.
+func (r *ProjectRepository) Restore(ctx context.Context, id string) (err error) {
+	tx, err := begin(ctx, r.db)
 	if err != nil {
+		return
+	}
+	defer tx.Rollback()
+
+	q := `
+	SELECT deleted_at FROM projects WHERE id = $1 AND deleted_at IS NOT NULL FOR UPDATE
+	`
+
+	var deletedAt time.Time
+	if err = tx.QueryRowContext(ctx, q, id).Scan(&deletedAt); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			err = project.ErrNotFound
 		}
+		return
 	}
 
-	return
+	q = `
+	UPDATE projects SET deleted_at = NULL, version = version + 1 WHERE id = $1
+	`
+
+	if _, err = tx.ExecContext(ctx, q, id); err != nil {
+		return
+	}
+
+	q = `
+	UPDATE tasks SET deleted_at = NULL, version = version + 1 WHERE project_id = $1 AND deleted_at = $2
+	`
+
+	if _, err = tx.ExecContext(ctx, q, id, deletedAt); err != nil {
+		return
+	}
+
+	return tx.Commit()
+}
+
+// Purge deletes the projects for good, their tasks and memberships go with
+// them through the foreign keys.
+func (r *ProjectRepository) Purge(ctx context.Context, before time.Time) (n int64, err error) {
+	q := `
+	DELETE FROM projects WHERE deleted_at < $1
+	`
+
+	res, err := conn(ctx, r.db).ExecContext(ctx, q, before)
+	if err != nil {
+		return
+	}
+
+	return res.RowsAffected()
 }
 
 func (r *ProjectRepository) Get(ctx context.Context, id string) (p project.Entity, err error) {
 	p = project.Entity{}
 
-	q := `
-	SELECT * FROM projects WHERE id = $1
-	`
+	q := "SELECT " + projectColumns + " FROM projects WHERE id = $1 AND " + visible(ctx)
 
-	err = r.db.GetContext(ctx, &p, q, id)
+	err = sqlx.GetContext(ctx, conn(ctx, r.db), &p, q, id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			err = project.ErrNotFound
@@ -99,30 +178,66 @@ func (r *ProjectRepository) Get(ctx context.Context, id string) (p project.Entit
 	return
 }
 
-func (r *ProjectRepository) List(ctx context.Context) (projects []project.Entity, err error) {
-	s := "SELECT * FROM projects"
+func (r *ProjectRepository) Lock(ctx context.Context, id string) error {
+	return r.lock(ctx, id, "FOR UPDATE")
+}
 
-	err = r.db.SelectContext(ctx, &projects, s)
-	if err != nil {
-		return
+// LockShared takes the key share lock foreign keys take on the project, so it
+// does not keep tasks from being added to it either.
+func (r *ProjectRepository) LockShared(ctx context.Context, id string) error {
+	return r.lock(ctx, id, "FOR KEY SHARE")
+}
+
+func (r *ProjectRepository) lock(ctx context.Context, id, mode string) (err error) {
+	q := "SELECT id FROM projects WHERE id = $1 AND deleted_at IS NULL " + mode
+
+	if err = conn(ctx, r.db).QueryRowxContext(ctx, q, id).Scan(&id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = project.ErrNotFound
+		}
 	}
 
 	return
 }
 
-func (r *ProjectRepository) Search(ctx context.Context, arg, value string) (projects []project.Entity, err error) {
+func (r *ProjectRepository) ManagedBy(ctx context.Context, userID string) (projects []project.Entity, err error) {
 	projects = []project.Entity{}
 
+	q := "SELECT " + projectColumns + " FROM projects WHERE manager_id = $1 AND deleted_at IS NULL ORDER BY started_at, id FOR UPDATE"
+
+	err = sqlx.SelectContext(ctx, conn(ctx, r.db), &projects, q, userID)
+
+	return
+}
+
+func (r *ProjectRepository) List(ctx context.Context, page domain.PageRequest) (projects domain.Page[project.Entity], err error) {
+	l := listing{table: "projects", columns: projectColumns, dateColumn: "started_at"}
+
+	projects, err = paginate(ctx, conn(ctx, r.db), l, page, r.cursor)
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+func (r *ProjectRepository) Search(ctx context.Context, arg, value string, page domain.PageRequest) (projects domain.Page[project.Entity], err error) {
 	filter := r.prepareFilterArg(arg)
 
-	q := fmt.Sprintf("SELECT * FROM projects WHERE %s = $1", filter)
+	l := listing{
+		table:      "projects",
+		columns:    projectColumns,
+		dateColumn: "started_at",
+		where:      fmt.Sprintf("%s = $1", filter),
+		args:       []any{value},
+	}
 
-	err = r.db.SelectContext(ctx, &projects, q, value)
+	projects, err = paginate(ctx, conn(ctx, r.db), l, page, r.cursor)
 	if err != nil {
 		return
 	}
 
-	if len(projects) == 0 {
+	if projects.Total == 0 {
 		err = project.ErrNotFound
 		return
 	}
@@ -130,6 +245,32 @@ func (r *ProjectRepository) Search(ctx context.Context, arg, value string) (proj
 	return
 }
 
+func (r *ProjectRepository) FullTextSearch(ctx context.Context, query string, limit int) (projects []domain.Ranked[project.Entity], err error) {
+	projects = []domain.Ranked[project.Entity]{}
+
+	rows := []struct {
+		project.Entity
+		Rank float64 `db:"rank"`
+	}{}
+
+	q := fullTextQuery("projects", projectColumns, "english")
+
+	err = sqlx.SelectContext(ctx, conn(ctx, r.db), &rows, q, query, limit)
+	if err != nil {
+		return
+	}
+
+	for _, row := range rows {
+		projects = append(projects, domain.Ranked[project.Entity]{Entity: row.Entity, Rank: row.Rank})
+	}
+
+	return
+}
+
+func (r *ProjectRepository) cursor(p project.Entity) string {
+	return domain.EncodeCursor(string(p.StartedAt), p.ID)
+}
+
 func (r *ProjectRepository) prepareArgs(p project.Entity) (sets []string, args []any) {
 	if p.Title != "" {
 		args = append(args, p.Title)
@@ -159,6 +300,23 @@ func (r *ProjectRepository) prepareArgs(p project.Entity) (sets []string, args [
 	return
 }
 
+// prepareClears empties the columns of the fields, only the optional ones can
+// be cleared.
+func (r *ProjectRepository) prepareClears(fields []project.Field) (sets []string) {
+	for _, f := range fields {
+		switch f {
+		case project.FieldDescription:
+			sets = append(sets, "description = ''")
+		case project.FieldFinishedAt:
+			sets = append(sets, "finished_at = NULL")
+		case project.FieldManagerID:
+			sets = append(sets, "manager_id = NULL")
+		}
+	}
+
+	return
+}
+
 func (r *ProjectRepository) prepareFilterArg(arg string) string {
 	switch arg {
 	case "title":
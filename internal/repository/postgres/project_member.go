@@ -0,0 +1,121 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"project-management/internal/domain/project"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type ProjectMemberRepository struct {
+	db *sqlx.DB
+}
+
+func NewProjectMemberRepository(db *sqlx.DB) *ProjectMemberRepository {
+	if db == nil {
+		panic("db is required")
+	}
+
+	return &ProjectMemberRepository{
+		db: db,
+	}
+}
+
+func (r *ProjectMemberRepository) AddMember(ctx context.Context, m project.Member) (err error) {
+	// a conflict is not an error, which would abort the unit of work the
+	// caller may run in
+	q := `
+		INSERT INTO project_members (project_id, user_id, role, joined_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT DO NOTHING
+	`
+
+	args := []any{m.ProjectID, m.UserID, m.Role, m.JoinedAt}
+
+	res, err := conn(ctx, r.db).ExecContext(ctx, q, args...)
+	if err != nil {
+		if err, ok := err.(*pq.Error); ok && err.Code.Name() == "foreign_key_violation" {
+			return project.ErrNotFound
+		}
+		return
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return
+	}
+
+	if n == 0 {
+		return project.ErrMemberExists
+	}
+
+	return
+}
+
+func (r *ProjectMemberRepository) SetMember(ctx context.Context, m project.Member) (err error) {
+	q := `
+		INSERT INTO project_members (project_id, user_id, role, joined_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (project_id, user_id) DO UPDATE SET role = EXCLUDED.role
+	`
+
+	args := []any{m.ProjectID, m.UserID, m.Role, m.JoinedAt}
+
+	if _, err = conn(ctx, r.db).ExecContext(ctx, q, args...); err != nil {
+		if err, ok := err.(*pq.Error); ok && err.Code.Name() == "foreign_key_violation" {
+			return project.ErrNotFound
+		}
+		return
+	}
+
+	return
+}
+
+func (r *ProjectMemberRepository) GetMember(ctx context.Context, projectID, userID string) (m project.Member, err error) {
+	q := `
+	SELECT project_id, user_id, role, joined_at FROM project_members WHERE project_id = $1 AND user_id = $2
+	`
+
+	if err = sqlx.GetContext(ctx, conn(ctx, r.db), &m, q, projectID, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = project.ErrMemberNotFound
+			return
+		}
+	}
+
+	return
+}
+
+func (r *ProjectMemberRepository) ListMembers(ctx context.Context, projectID string) (members []project.Member, err error) {
+	members = []project.Member{}
+
+	q := `
+	SELECT project_id, user_id, role, joined_at FROM project_members WHERE project_id = $1 ORDER BY joined_at, user_id
+	`
+
+	err = sqlx.SelectContext(ctx, conn(ctx, r.db), &members, q, projectID)
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+func (r *ProjectMemberRepository) RemoveMember(ctx context.Context, projectID, userID string) (err error) {
+	q := `
+	DELETE FROM project_members WHERE project_id = $1 AND user_id = $2 RETURNING user_id
+	`
+
+	if err = conn(ctx, r.db).QueryRowxContext(ctx, q, projectID, userID).Scan(&userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = project.ErrMemberNotFound
+			return
+		}
+	}
+
+	return
+}
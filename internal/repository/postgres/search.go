@@ -0,0 +1,17 @@
+package postgres
+
+import "fmt"
+
+// fullTextQuery selects the columns of table matching the websearch query in
+// $1 ordered by relevance and limited to $2 rows, deleted rows are never
+// found. config must be the text search configuration the search_vector
+// column was built with.
+func fullTextQuery(table, columns, config string) string {
+	return fmt.Sprintf(`
+	SELECT %s, ts_rank(search_vector, query) AS rank
+	FROM %s, websearch_to_tsquery('%s', $1) query
+	WHERE search_vector @@ query AND deleted_at IS NULL
+	ORDER BY rank DESC, id
+	LIMIT $2
+	`, columns, table, config)
+}
@@ -0,0 +1,219 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"project-management/internal/domain/project"
+	"project-management/internal/domain/sprint"
+	"project-management/internal/domain/task"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+const sprintColumns = "id, project_id, name, goal, start_at, end_at, state"
+
+type SprintRepository struct {
+	db *sqlx.DB
+}
+
+func NewSprintRepository(db *sqlx.DB) *SprintRepository {
+	if db == nil {
+		panic("db is required")
+	}
+
+	return &SprintRepository{
+		db: db,
+	}
+}
+
+func (r *SprintRepository) Create(ctx context.Context, data sprint.Entity) (id string, err error) {
+	q := `
+		INSERT INTO sprints (id, project_id, name, goal, start_at, end_at, state)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`
+
+	args := []any{data.ID, data.ProjectID, data.Name, data.Goal, data.StartAt, data.EndAt, data.State}
+
+	if err = conn(ctx, r.db).QueryRowxContext(ctx, q, args...).Scan(&id); err != nil {
+		if err, ok := err.(*pq.Error); ok && err.Code.Name() == "foreign_key_violation" {
+			return "", project.ErrNotFound
+		}
+		return
+	}
+
+	return
+}
+
+func (r *SprintRepository) Get(ctx context.Context, id string) (data sprint.Entity, err error) {
+	q := "SELECT " + sprintColumns + " FROM sprints WHERE id = $1"
+
+	if err = sqlx.GetContext(ctx, conn(ctx, r.db), &data, q, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = sprint.ErrNotFound
+		}
+		return
+	}
+
+	return
+}
+
+func (r *SprintRepository) List(ctx context.Context, projectID string) (sprints []sprint.Entity, err error) {
+	sprints = []sprint.Entity{}
+
+	q := "SELECT " + sprintColumns + " FROM sprints WHERE project_id = $1 ORDER BY start_at, id"
+
+	if err = sqlx.SelectContext(ctx, conn(ctx, r.db), &sprints, q, projectID); err != nil {
+		return
+	}
+
+	return
+}
+
+func (r *SprintRepository) Update(ctx context.Context, id string, data sprint.Entity) (err error) {
+	var sets []string
+	args := []any{id}
+
+	if data.Name != "" {
+		args = append(args, data.Name)
+		sets = append(sets, fmt.Sprintf("name=$%d", len(args)))
+	}
+
+	if data.Goal != "" {
+		args = append(args, data.Goal)
+		sets = append(sets, fmt.Sprintf("goal=$%d", len(args)))
+	}
+
+	if data.StartAt != "" {
+		args = append(args, data.StartAt)
+		sets = append(sets, fmt.Sprintf("start_at=$%d", len(args)))
+	}
+
+	if data.EndAt != "" {
+		args = append(args, data.EndAt)
+		sets = append(sets, fmt.Sprintf("end_at=$%d", len(args)))
+	}
+
+	if data.State != "" {
+		args = append(args, data.State)
+		sets = append(sets, fmt.Sprintf("state=$%d", len(args)))
+	}
+
+	if len(sets) == 0 {
+		return
+	}
+
+	q := fmt.Sprintf("UPDATE sprints SET %s WHERE id = $1 RETURNING id", strings.Join(sets, ", "))
+
+	if err = conn(ctx, r.db).QueryRowxContext(ctx, q, args...).Scan(&id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return sprint.ErrNotFound
+		}
+		if err, ok := err.(*pq.Error); ok && err.Code.Name() == "unique_violation" {
+			return sprint.ErrActiveExists
+		}
+		return
+	}
+
+	return
+}
+
+func (r *SprintRepository) Delete(ctx context.Context, id string) (err error) {
+	q := `
+	DELETE FROM sprints WHERE id = $1 RETURNING id
+	`
+
+	if err = conn(ctx, r.db).QueryRowxContext(ctx, q, id).Scan(&id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = sprint.ErrNotFound
+		}
+		return
+	}
+
+	return
+}
+
+// Lock takes a row lock that conflicts with the key share lock foreign keys
+// take on the sprint, so tasks pointed at it wait for the unit of work to end.
+func (r *SprintRepository) Lock(ctx context.Context, id string) (err error) {
+	q := "SELECT id FROM sprints WHERE id = $1 FOR UPDATE"
+
+	if err = conn(ctx, r.db).QueryRowxContext(ctx, q, id).Scan(&id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = sprint.ErrNotFound
+		}
+	}
+
+	return
+}
+
+func (r *SprintRepository) Schedule(ctx context.Context, taskID, sprintID string, events ...task.Event) (err error) {
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return
+	}
+	defer tx.Rollback()
+
+	q := `
+	UPDATE tasks SET sprint_id = $1, version = version + 1 WHERE id = $2 AND deleted_at IS NULL RETURNING id
+	`
+
+	if err = tx.QueryRowContext(ctx, q, nullable(sprintID), taskID).Scan(&taskID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return task.ErrNotFound
+		}
+		if err, ok := err.(*pq.Error); ok && err.Code.Name() == "foreign_key_violation" {
+			return sprint.ErrNotFound
+		}
+		return
+	}
+
+	if err = insertTaskEvents(ctx, tx, events); err != nil {
+		return
+	}
+
+	return tx.Commit()
+}
+
+func (r *SprintRepository) Close(ctx context.Context, id, nextID string, taskIDs []string, events ...task.Event) (err error) {
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return
+	}
+	defer tx.Rollback()
+
+	q := `
+	UPDATE sprints SET state = 'closed' WHERE id = $1 RETURNING id
+	`
+
+	if err = tx.QueryRowContext(ctx, q, id).Scan(&id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = sprint.ErrNotFound
+		}
+		return
+	}
+
+	if len(taskIDs) > 0 {
+		q = `
+		UPDATE tasks SET sprint_id = $1, version = version + 1 WHERE id = ANY($2)
+		`
+
+		if _, err = tx.ExecContext(ctx, q, nullable(nextID), pq.Array(taskIDs)); err != nil {
+			if err, ok := err.(*pq.Error); ok && err.Code.Name() == "foreign_key_violation" {
+				return sprint.ErrNotFound
+			}
+			return
+		}
+	}
+
+	if err = insertTaskEvents(ctx, tx, events); err != nil {
+		return
+	}
+
+	return tx.Commit()
+}
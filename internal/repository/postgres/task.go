@@ -4,8 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"project-management/internal/domain"
 	"project-management/internal/domain/task"
 	"strings"
+	"time"
 
 	"database/sql"
 
@@ -13,6 +15,8 @@ import (
 	"github.com/lib/pq"
 )
 
+const taskColumns = "id, title, description, priority, status, COALESCE(author_id, '') AS author_id, COALESCE(assignee_id, '') AS assignee_id, project_id, COALESCE(parent_id, '') AS parent_id, COALESCE(sprint_id, '') AS sprint_id, COALESCE(milestone_id, '') AS milestone_id, created_at, due_date, COALESCE(estimate_hours, 0) AS estimate_hours, completed_at, deleted_at, version"
+
 type TaskRepository struct {
 	db *sqlx.DB
 }
@@ -28,14 +32,18 @@ func NewTaskRepository(db *sqlx.DB) *TaskRepository {
 }
 
 func (r *TaskRepository) Create(ctx context.Context, t task.Entity) (id string, err error) {
+	return r.create(ctx, conn(ctx, r.db), t)
+}
+
+func (r *TaskRepository) create(ctx context.Context, db sqlx.QueryerContext, t task.Entity) (id string, err error) {
 	q := `
-		INSERT INTO tasks (id, title, description, priority, status, author_id, project_id, created_at, done_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id
+		INSERT INTO tasks (id, title, description, priority, status, author_id, assignee_id, project_id, parent_id, sprint_id, milestone_id, created_at, due_date, estimate_hours, completed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15) RETURNING id
 	`
 
-	args := []any{t.ID, t.Title, t.Description, t.Priority, t.Status, t.AuthorID, t.ProjectID, t.CreatedAt, t.DoneAt}
+	args := []any{t.ID, t.Title, t.Description, t.Priority, t.Status, t.AuthorID, nullable(t.AssigneeID), t.ProjectID, nullable(t.ParentID), nullable(t.SprintID), nullable(t.MilestoneID), t.CreatedAt, nullable(string(t.DueDate)), t.EstimateHours, t.CompletedAt}
 
-	err = r.db.QueryRowContext(ctx, q, args...).Scan(&id)
+	err = db.QueryRowxContext(ctx, q, args...).Scan(&id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			err = task.ErrExists
@@ -49,17 +57,141 @@ func (r *TaskRepository) Create(ctx context.Context, t task.Entity) (id string,
 	return
 }
 
-func (r *TaskRepository) Update(ctx context.Context, id string, t task.Entity) (err error) {
+func (r *TaskRepository) Update(ctx context.Context, id string, t task.Entity, events ...task.Event) (err error) {
+	return r.Patch(ctx, id, t, nil, events...)
+}
+
+func (r *TaskRepository) Patch(ctx context.Context, id string, t task.Entity, clear []task.Field, events ...task.Event) (err error) {
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return
+	}
+	defer tx.Rollback()
+
+	if err = r.patch(ctx, tx, id, t, clear, events); err != nil {
+		return
+	}
+
+	return tx.Commit()
+}
+
+func (r *TaskRepository) patch(ctx context.Context, tx sqlx.ExtContext, id string, t task.Entity, clear []task.Field, events []task.Event) (err error) {
 	sets, args := r.prepareArgs(t)
-	if len(sets) > 0 {
-		args = append(args, id)
-		q := fmt.Sprintf("UPDATE tasks SET %s WHERE id = $%d RETURNING ID", strings.Join(sets, ", "), len(args))
+	sets = append(sets, r.prepareClears(clear)...)
+	if len(sets) == 0 {
+		return
+	}
+
+	args = append(args, id, t.Version)
+	q := fmt.Sprintf("UPDATE tasks SET %s, version = version + 1 WHERE id = $%d AND deleted_at IS NULL AND %s RETURNING ID", strings.Join(sets, ", "), len(args)-1, versionCheck(len(args)))
+
+	if err = tx.QueryRowxContext(ctx, q, args...).Scan(&id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = missingOrConflict(ctx, tx, "tasks", id, task.ErrNotFound)
+		}
+		return
+	}
+
+	return insertTaskEvents(ctx, tx, events)
+}
 
-		err = r.db.QueryRowContext(ctx, q, args...).Scan(&id)
+func (r *TaskRepository) Assign(ctx context.Context, id, assigneeID string, events ...task.Event) (err error) {
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return
+	}
+	defer tx.Rollback()
+
+	q := `
+	UPDATE tasks SET assignee_id = $1, version = version + 1 WHERE id = $2 AND deleted_at IS NULL RETURNING id
+	`
+
+	if err = tx.QueryRowContext(ctx, q, nullable(assigneeID), id).Scan(&id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = task.ErrNotFound
+		}
+		return
+	}
+
+	if err = insertTaskEvents(ctx, tx, events); err != nil {
+		return
+	}
+
+	return tx.Commit()
+}
+
+func (r *TaskRepository) History(ctx context.Context, id string) (events []task.Event, err error) {
+	events = []task.Event{}
+
+	q := `
+	SELECT id, task_id, field, old_value, new_value, COALESCE(actor_id, '') AS actor_id, created_at
+	FROM task_events WHERE task_id = $1 ORDER BY created_at, id
+	`
+
+	err = sqlx.SelectContext(ctx, conn(ctx, r.db), &events, q, id)
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+func (r *TaskRepository) Subtasks(ctx context.Context, id string) (tasks []task.Entity, err error) {
+	tasks = []task.Entity{}
+
+	// UNION rather than UNION ALL stops the recursion should the hierarchy
+	// ever contain a cycle
+	q := `
+	WITH RECURSIVE tree AS (
+		SELECT id FROM tasks WHERE parent_id = $1 AND deleted_at IS NULL
+		UNION
+		SELECT t.id FROM tasks t JOIN tree ON t.parent_id = tree.id WHERE t.deleted_at IS NULL
+	)
+	SELECT ` + taskColumns + ` FROM tasks WHERE id IN (SELECT id FROM tree) ORDER BY created_at, id
+	`
+
+	if err = sqlx.SelectContext(ctx, conn(ctx, r.db), &tasks, q, id); err != nil {
+		return
+	}
+
+	return
+}
+
+func (r *TaskRepository) OwnedBy(ctx context.Context, userID string) (tasks []task.Entity, err error) {
+	tasks = []task.Entity{}
+
+	q := `
+	SELECT ` + taskColumns + ` FROM tasks
+	WHERE (author_id = $1 OR assignee_id = $1) AND deleted_at IS NULL ORDER BY created_at, id FOR UPDATE
+	`
+
+	err = sqlx.SelectContext(ctx, conn(ctx, r.db), &tasks, q, userID)
+
+	return
+}
+
+// Lock takes the rows in the order of their ids, which keeps two units of work
+// locking the same tasks from waiting on one another.
+func (r *TaskRepository) Lock(ctx context.Context, ids ...string) (err error) {
+	var locked []string
+
+	q := "SELECT id FROM tasks WHERE id = ANY($1) AND deleted_at IS NULL ORDER BY id FOR UPDATE"
+
+	err = sqlx.SelectContext(ctx, conn(ctx, r.db), &locked, q, pq.Array(ids))
+
+	return
+}
+
+func insertTaskEvents(ctx context.Context, tx sqlx.ExecerContext, events []task.Event) (err error) {
+	q := `
+		INSERT INTO task_events (task_id, field, old_value, new_value, actor_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	for _, e := range events {
+		_, err = tx.ExecContext(ctx, q, e.TaskID, e.Field, e.OldValue, e.NewValue, nullable(e.ActorID), e.CreatedAt)
 		if err != nil {
-			if errors.Is(err, sql.ErrNoRows) {
-				err = task.ErrNotFound
-			}
+			return
 		}
 	}
 
@@ -69,13 +201,31 @@ func (r *TaskRepository) Update(ctx context.Context, id string, t task.Entity) (
 func (r *TaskRepository) Get(ctx context.Context, id string) (t task.Entity, err error) {
 	t = task.Entity{}
 
+	q := "SELECT " + taskColumns + " FROM tasks WHERE id = $1 AND " + visible(ctx)
+
+	if err = sqlx.GetContext(ctx, conn(ctx, r.db), &t, q, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = task.ErrNotFound
+			return
+		}
+	}
+
+	return
+}
+
+func (r *TaskRepository) Delete(ctx context.Context, id string, version int64) (err error) {
+	return r.delete(ctx, conn(ctx, r.db), id, version)
+}
+
+func (r *TaskRepository) delete(ctx context.Context, db sqlx.QueryerContext, id string, version int64) (err error) {
 	q := `
-	SELECT * FROM tasks WHERE id = $1
+	UPDATE tasks SET deleted_at = now(), version = version + 1
+	WHERE id = $1 AND deleted_at IS NULL AND ` + versionCheck(2) + ` RETURNING id
 	`
 
-	if err = r.db.GetContext(ctx, &t, q, id); err != nil {
+	if err = db.QueryRowxContext(ctx, q, id, version).Scan(&id); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			err = task.ErrNotFound
+			err = missingOrConflict(ctx, db, "tasks", id, task.ErrNotFound)
 			return
 		}
 	}
@@ -83,12 +233,62 @@ func (r *TaskRepository) Get(ctx context.Context, id string) (t task.Entity, err
 	return
 }
 
-func (r *TaskRepository) Delete(ctx context.Context, id string) (err error) {
+func (r *TaskRepository) Bulk(ctx context.Context, writes []task.Write, atomic bool) (errs []error, err error) {
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return
+	}
+	defer tx.Rollback()
+
+	errs = make([]error, len(writes))
+	for i, w := range writes {
+		// a savepoint per write keeps a failing one from aborting the others
+		if !atomic {
+			if _, err = tx.ExecContext(ctx, "SAVEPOINT bulk_write"); err != nil {
+				return
+			}
+		}
+
+		errs[i] = r.write(ctx, tx, w)
+
+		switch {
+		case errs[i] != nil && atomic:
+			return errs, nil
+		case errs[i] != nil:
+			_, err = tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT bulk_write")
+		case !atomic:
+			_, err = tx.ExecContext(ctx, "RELEASE SAVEPOINT bulk_write")
+		}
+		if err != nil {
+			return
+		}
+	}
+
+	return errs, tx.Commit()
+}
+
+func (r *TaskRepository) write(ctx context.Context, tx sqlx.ExtContext, w task.Write) (err error) {
+	switch w.Op {
+	case task.OpCreate:
+		if _, err = r.create(ctx, tx, w.Entity); err != nil {
+			return
+		}
+		return insertTaskEvents(ctx, tx, w.Events)
+	case task.OpUpdate:
+		return r.patch(ctx, tx, w.ID, w.Entity, w.Clear, w.Events)
+	case task.OpDelete:
+		return r.delete(ctx, tx, w.ID, w.Entity.Version)
+	default:
+		return fmt.Errorf("unknown bulk operation %q", w.Op)
+	}
+}
+
+func (r *TaskRepository) Restore(ctx context.Context, id string) (err error) {
 	q := `
-	DELETE FROM tasks WHERE id = $1 RETURNING id
+	UPDATE tasks SET deleted_at = NULL, version = version + 1 WHERE id = $1 AND deleted_at IS NOT NULL RETURNING id
 	`
 
-	if err = r.db.QueryRowContext(ctx, q, id).Scan(&id); err != nil {
+	if err = conn(ctx, r.db).QueryRowxContext(ctx, q, id).Scan(&id); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			err = task.ErrNotFound
 			return
@@ -98,29 +298,48 @@ func (r *TaskRepository) Delete(ctx context.Context, id string) (err error) {
 	return
 }
 
-func (r *TaskRepository) List(ctx context.Context) (tasks []task.Entity, err error) {
-	q := "SELECT * FROM tasks"
-	err = r.db.SelectContext(ctx, &tasks, q)
+func (r *TaskRepository) Purge(ctx context.Context, before time.Time) (n int64, err error) {
+	q := `
+	DELETE FROM tasks WHERE deleted_at < $1
+	`
+
+	res, err := conn(ctx, r.db).ExecContext(ctx, q, before)
 	if err != nil {
 		return
 	}
 
-	return
+	return res.RowsAffected()
 }
 
-func (r *TaskRepository) Search(ctx context.Context, filter, value string) (tasks []task.Entity, err error) {
-	tasks = []task.Entity{}
+func (r *TaskRepository) List(ctx context.Context, filter task.Filter, page domain.PageRequest) (tasks domain.Page[task.Entity], err error) {
+	where, args, err := r.prepareFilter(filter)
+	if err != nil {
+		return
+	}
 
-	filter = r.prepareFilterArg(filter)
+	l := listing{
+		table:      "tasks",
+		columns:    taskColumns,
+		dateColumn: "created_at",
+		where:      where,
+		args:       args,
+	}
 
-	q := fmt.Sprintf("SELECT * FROM users WHERE %s = $1", filter)
+	tasks, err = paginate(ctx, conn(ctx, r.db), l, page, r.cursor)
+	if err != nil {
+		return
+	}
+
+	return
+}
 
-	err = r.db.SelectContext(ctx, &tasks, q, value)
+func (r *TaskRepository) Search(ctx context.Context, filter task.Filter, page domain.PageRequest) (tasks domain.Page[task.Entity], err error) {
+	tasks, err = r.List(ctx, filter, page)
 	if err != nil {
 		return
 	}
 
-	if len(tasks) == 0 {
+	if tasks.Total == 0 {
 		err = task.ErrNotFound
 		return
 	}
@@ -128,6 +347,32 @@ func (r *TaskRepository) Search(ctx context.Context, filter, value string) (task
 	return
 }
 
+func (r *TaskRepository) FullTextSearch(ctx context.Context, query string, limit int) (tasks []domain.Ranked[task.Entity], err error) {
+	tasks = []domain.Ranked[task.Entity]{}
+
+	rows := []struct {
+		task.Entity
+		Rank float64 `db:"rank"`
+	}{}
+
+	q := fullTextQuery("tasks", taskColumns, "english")
+
+	err = sqlx.SelectContext(ctx, conn(ctx, r.db), &rows, q, query, limit)
+	if err != nil {
+		return
+	}
+
+	for _, row := range rows {
+		tasks = append(tasks, domain.Ranked[task.Entity]{Entity: row.Entity, Rank: row.Rank})
+	}
+
+	return
+}
+
+func (r *TaskRepository) cursor(t task.Entity) string {
+	return domain.EncodeCursor(string(t.CreatedAt), t.ID)
+}
+
 func (r *TaskRepository) prepareArgs(data task.Entity) (sets []string, args []any) {
 	if data.Title != "" {
 		args = append(args, data.Title)
@@ -154,37 +399,171 @@ func (r *TaskRepository) prepareArgs(data task.Entity) (sets []string, args []an
 		sets = append(sets, fmt.Sprintf("author_id=$%d", len(args)))
 	}
 
+	if data.AssigneeID != "" {
+		args = append(args, data.AssigneeID)
+		sets = append(sets, fmt.Sprintf("assignee_id=$%d", len(args)))
+	}
+
 	if data.ProjectID != "" {
 		args = append(args, data.ProjectID)
 		sets = append(sets, fmt.Sprintf("project_id=$%d", len(args)))
 	}
 
-	if data.DoneAt != "" {
-		args = append(args, data.DoneAt)
-		sets = append(sets, fmt.Sprintf("done_at=$%d", len(args)))
+	if data.ParentID != "" {
+		args = append(args, data.ParentID)
+		sets = append(sets, fmt.Sprintf("parent_id=$%d", len(args)))
+	}
+
+	if data.DueDate != "" {
+		args = append(args, data.DueDate)
+		sets = append(sets, fmt.Sprintf("due_date=$%d", len(args)))
+	}
+
+	if data.EstimateHours != nil {
+		args = append(args, *data.EstimateHours)
+		sets = append(sets, fmt.Sprintf("estimate_hours=$%d", len(args)))
+	}
+
+	// a zero completion time reopens the task
+	if data.CompletedAt != nil {
+		if data.CompletedAt.IsZero() {
+			args = append(args, nil)
+		} else {
+			args = append(args, *data.CompletedAt)
+		}
+		sets = append(sets, fmt.Sprintf("completed_at=$%d", len(args)))
+	}
+
+	return
+}
+
+// prepareClears empties the columns of the fields, only the optional ones can
+// be cleared.
+func (r *TaskRepository) prepareClears(fields []task.Field) (sets []string) {
+	for _, f := range fields {
+		switch f {
+		case task.FieldDescription:
+			sets = append(sets, "description=''")
+		case task.FieldAssigneeID:
+			sets = append(sets, "assignee_id=NULL")
+		case task.FieldParentID:
+			sets = append(sets, "parent_id=NULL")
+		case task.FieldDueDate:
+			sets = append(sets, "due_date=NULL")
+		case task.FieldEstimateHours:
+			sets = append(sets, "estimate_hours=NULL")
+		}
 	}
 
 	return
 }
 
-func (r *TaskRepository) prepareFilterArg(arg string) string {
-	switch arg {
-	case "title":
+// prepareFilter translates the filter into a WHERE clause, values are always
+// passed as arguments and columns come from a fixed list.
+func (r *TaskRepository) prepareFilter(filter task.Filter) (where string, args []any, err error) {
+	var conds []string
+
+	for _, c := range filter.Conditions {
+		if c.Field == task.FieldLabel {
+			var cond string
+			if cond, args, err = r.labelCondition(c, args); err != nil {
+				return "", nil, err
+			}
+			conds = append(conds, cond)
+			continue
+		}
+
+		if c.Field == task.FieldOverdue {
+			if c.Operator != task.OpEq || len(c.Values) != 1 {
+				return "", nil, task.ErrSearch
+			}
+
+			args = append(args, time.Now().UTC().Format(domain.DateLayout), c.Values[0] == "true")
+			conds = append(conds, fmt.Sprintf("COALESCE(due_date < $%d AND completed_at IS NULL, false) = $%d", len(args)-1, len(args)))
+			continue
+		}
+
+		column := r.prepareFilterArg(c.Field)
+		if column == "" || len(c.Values) == 0 {
+			return "", nil, task.ErrSearch
+		}
+
+		switch c.Operator {
+		case task.OpEq:
+			args = append(args, c.Values[0])
+			conds = append(conds, fmt.Sprintf("%s = $%d", column, len(args)))
+		case task.OpIn:
+			args = append(args, pq.Array(c.Values))
+			conds = append(conds, fmt.Sprintf("%s = ANY($%d)", column, len(args)))
+		case task.OpGte:
+			args = append(args, c.Values[0])
+			conds = append(conds, fmt.Sprintf("%s >= $%d", column, len(args)))
+		case task.OpLte:
+			args = append(args, c.Values[0])
+			conds = append(conds, fmt.Sprintf("%s <= $%d", column, len(args)))
+		case task.OpContains:
+			args = append(args, "%"+likeEscaper.Replace(c.Values[0])+"%")
+			conds = append(conds, fmt.Sprintf("%s ILIKE $%d", column, len(args)))
+		default:
+			return "", nil, task.ErrSearch
+		}
+	}
+
+	return strings.Join(conds, " AND "), args, nil
+}
+
+// labelCondition matches tasks by the names of their labels, for OpAll the
+// distinct names found on the task are counted.
+func (r *TaskRepository) labelCondition(c task.Condition, args []any) (string, []any, error) {
+	const labelled = "FROM task_labels tl JOIN labels l ON l.id = tl.label_id WHERE tl.task_id = tasks.id AND l.name = ANY($%d)"
+
+	names := map[string]bool{}
+	for _, v := range c.Values {
+		names[v] = true
+	}
+
+	args = append(args, pq.Array(c.Values))
+
+	switch c.Operator {
+	case task.OpEq, task.OpIn:
+		return fmt.Sprintf("EXISTS (SELECT 1 "+labelled+")", len(args)), args, nil
+	case task.OpAll:
+		args = append(args, len(names))
+		return fmt.Sprintf("(SELECT count(DISTINCT l.name) "+labelled+") = $%d", len(args)-1, len(args)), args, nil
+	default:
+		return "", nil, task.ErrSearch
+	}
+}
+
+func (r *TaskRepository) prepareFilterArg(field task.Field) string {
+	switch field {
+	case task.FieldTitle:
 		return "title"
-	case "description":
+	case task.FieldDescription:
 		return "description"
-	case "priority":
+	case task.FieldPriority:
 		return "priority"
-	case "status":
+	case task.FieldStatus:
 		return "status"
-	case "assignee":
+	case task.FieldAuthorID:
 		return "author_id"
-	case "project_id":
+	case task.FieldAssigneeID:
+		return "assignee_id"
+	case task.FieldProjectID:
 		return "project_id"
-	case "created_at":
+	case task.FieldParentID:
+		return "parent_id"
+	case task.FieldSprintID:
+		// an empty sprint is the backlog
+		return "COALESCE(sprint_id, '')"
+	case task.FieldMilestoneID:
+		return "milestone_id"
+	case task.FieldCreatedAt:
 		return "created_at"
-	case "done_at":
-		return "done_at"
+	case task.FieldDueDate:
+		return "due_date"
+	case task.FieldCompletedAt:
+		return "completed_at::date"
 	default:
 		return ""
 	}
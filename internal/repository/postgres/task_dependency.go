@@ -0,0 +1,117 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"project-management/internal/domain/task"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type TaskDependencyRepository struct {
+	db *sqlx.DB
+}
+
+func NewTaskDependencyRepository(db *sqlx.DB) *TaskDependencyRepository {
+	if db == nil {
+		panic("db is required")
+	}
+
+	return &TaskDependencyRepository{
+		db: db,
+	}
+}
+
+func (r *TaskDependencyRepository) AddDependency(ctx context.Context, blockerID, blockedID string) (err error) {
+	q := `
+		INSERT INTO task_dependencies (blocker_id, blocked_id) VALUES ($1, $2)
+	`
+
+	if _, err = conn(ctx, r.db).ExecContext(ctx, q, blockerID, blockedID); err != nil {
+		if err, ok := err.(*pq.Error); ok {
+			switch err.Code.Name() {
+			case "unique_violation":
+				return task.ErrDependencyExists
+			case "foreign_key_violation":
+				return task.ErrNotFound
+			case "check_violation":
+				return task.ErrSelfDependency
+			}
+		}
+		return
+	}
+
+	return
+}
+
+func (r *TaskDependencyRepository) RemoveDependency(ctx context.Context, blockerID, blockedID string) (err error) {
+	q := `
+	DELETE FROM task_dependencies WHERE blocker_id = $1 AND blocked_id = $2 RETURNING blocker_id
+	`
+
+	if err = conn(ctx, r.db).QueryRowxContext(ctx, q, blockerID, blockedID).Scan(&blockerID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = task.ErrDependencyNotFound
+		}
+		return
+	}
+
+	return
+}
+
+// Lock takes the table lock first, it conflicts with itself and with the
+// writes of other units of work but not with reads. The task rows are locked
+// in the order of taskIDs.
+func (r *TaskDependencyRepository) Lock(ctx context.Context, taskIDs ...string) (err error) {
+	if _, err = conn(ctx, r.db).ExecContext(ctx, "LOCK TABLE task_dependencies IN SHARE ROW EXCLUSIVE MODE"); err != nil {
+		return
+	}
+
+	for _, id := range taskIDs {
+		q := "SELECT id FROM tasks WHERE id = $1 AND deleted_at IS NULL FOR UPDATE"
+
+		if err = conn(ctx, r.db).QueryRowxContext(ctx, q, id).Scan(&id); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				err = task.ErrNotFound
+			}
+			return
+		}
+	}
+
+	return
+}
+
+func (r *TaskDependencyRepository) Blockers(ctx context.Context, taskID string) (tasks []task.Entity, err error) {
+	tasks = []task.Entity{}
+
+	q := `
+	SELECT ` + taskColumns + ` FROM tasks
+	WHERE ` + visible(ctx) + ` AND id IN (SELECT blocker_id FROM task_dependencies WHERE blocked_id = $1)
+	ORDER BY created_at, id
+	`
+
+	if err = sqlx.SelectContext(ctx, conn(ctx, r.db), &tasks, q, taskID); err != nil {
+		return
+	}
+
+	return
+}
+
+func (r *TaskDependencyRepository) Dependents(ctx context.Context, taskID string) (tasks []task.Entity, err error) {
+	tasks = []task.Entity{}
+
+	q := `
+	SELECT ` + taskColumns + ` FROM tasks
+	WHERE ` + visible(ctx) + ` AND id IN (SELECT blocked_id FROM task_dependencies WHERE blocker_id = $1)
+	ORDER BY created_at, id
+	`
+
+	if err = sqlx.SelectContext(ctx, conn(ctx, r.db), &tasks, q, taskID); err != nil {
+		return
+	}
+
+	return
+}
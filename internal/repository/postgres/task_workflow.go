@@ -0,0 +1,69 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"project-management/internal/domain/project"
+	"project-management/internal/domain/task"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type TaskWorkflowRepository struct {
+	db *sqlx.DB
+}
+
+func NewTaskWorkflowRepository(db *sqlx.DB) *TaskWorkflowRepository {
+	if db == nil {
+		panic("db is required")
+	}
+
+	return &TaskWorkflowRepository{
+		db: db,
+	}
+}
+
+func (r *TaskWorkflowRepository) GetWorkflow(ctx context.Context, projectID string) (w task.Workflow, err error) {
+	q := `
+	SELECT workflow FROM project_workflows WHERE project_id = $1
+	`
+
+	var data []byte
+	if err = conn(ctx, r.db).QueryRowxContext(ctx, q, projectID).Scan(&data); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = task.ErrWorkflowNotFound
+		}
+		return
+	}
+
+	err = json.Unmarshal(data, &w)
+
+	return
+}
+
+func (r *TaskWorkflowRepository) SaveWorkflow(ctx context.Context, projectID string, w task.Workflow) (err error) {
+	data, err := json.Marshal(w)
+	if err != nil {
+		return
+	}
+
+	q := `
+		INSERT INTO project_workflows (project_id, workflow, updated_at)
+		VALUES ($1, $2, now())
+		ON CONFLICT (project_id) DO UPDATE SET workflow = EXCLUDED.workflow, updated_at = EXCLUDED.updated_at
+	`
+
+	_, err = conn(ctx, r.db).ExecContext(ctx, q, projectID, data)
+	if err != nil {
+		if err, ok := err.(*pq.Error); ok && err.Code.Name() == "foreign_key_violation" {
+			return project.ErrNotFound
+		}
+		return
+	}
+
+	return
+}
@@ -0,0 +1,109 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type txKey struct{}
+
+// TxManager runs units of work in a postgres transaction, the repositories
+// pick it up from the context.
+type TxManager struct {
+	db *sqlx.DB
+}
+
+func NewTxManager(db *sqlx.DB) *TxManager {
+	if db == nil {
+		panic("db is required")
+	}
+
+	return &TxManager{
+		db: db,
+	}
+}
+
+func (m *TxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	if _, ok := ctx.Value(txKey{}).(*sqlx.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := m.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return
+	}
+	defer tx.Rollback()
+
+	if err = fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return
+	}
+
+	return tx.Commit()
+}
+
+// conn returns the transaction of the unit of work ctx runs in, or db outside
+// of one.
+func conn(ctx context.Context, db *sqlx.DB) sqlx.ExtContext {
+	if tx, ok := ctx.Value(txKey{}).(*sqlx.Tx); ok {
+		return tx
+	}
+
+	return db
+}
+
+// txn is the transaction of a write spanning several statements. Inside a
+// unit of work it is a savepoint of the outer transaction, so that a failing
+// write is undone without aborting the work around it.
+type txn struct {
+	*sqlx.Tx
+
+	ctx    context.Context
+	nested bool
+	done   bool
+}
+
+func begin(ctx context.Context, db *sqlx.DB) (t *txn, err error) {
+	if tx, ok := ctx.Value(txKey{}).(*sqlx.Tx); ok {
+		if _, err = tx.ExecContext(ctx, "SAVEPOINT repository_write"); err != nil {
+			return
+		}
+
+		return &txn{Tx: tx, ctx: ctx, nested: true}, nil
+	}
+
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return
+	}
+
+	return &txn{Tx: tx, ctx: ctx}, nil
+}
+
+func (t *txn) Commit() error {
+	if !t.nested {
+		return t.Tx.Commit()
+	}
+
+	t.done = true
+	_, err := t.ExecContext(t.ctx, "RELEASE SAVEPOINT repository_write")
+
+	return err
+}
+
+// Rollback undoes the write unless it was committed, like the Rollback of
+// sql.Tx it is safe to defer.
+func (t *txn) Rollback() error {
+	if !t.nested {
+		return t.Tx.Rollback()
+	}
+
+	if t.done {
+		return nil
+	}
+
+	t.done = true
+	_, err := t.ExecContext(t.ctx, "ROLLBACK TO SAVEPOINT repository_write")
+
+	return err
+}
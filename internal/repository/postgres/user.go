@@ -4,8 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"project-management/internal/domain"
 	"project-management/internal/domain/user"
 	"strings"
+	"time"
 
 	"database/sql"
 
@@ -13,6 +15,8 @@ import (
 	"github.com/lib/pq"
 )
 
+const userColumns = "id, name, email, registration_date, role, password_hash, deleted_at, version"
+
 type UserRepository struct {
 	db *sqlx.DB
 }
@@ -29,13 +33,13 @@ func NewUserRepository(db *sqlx.DB) *UserRepository {
 
 func (r *UserRepository) Create(ctx context.Context, u user.Entity) (id string, err error) {
 	q := `
-		INSERT INTO users (id, name, email, registration_date, role)
-		VALUES ($1, $2, $3, $4, $5) RETURNING id
+		INSERT INTO users (id, name, email, registration_date, role, password_hash)
+		VALUES ($1, $2, $3, $4, $5, $6) RETURNING id
 	`
 
-	args := []any{u.ID, u.Name, u.Email, u.RegistrationDate, u.Role}
+	args := []any{u.ID, u.Name, u.Email, u.RegistrationDate, u.Role, u.PasswordHash}
 
-	err = r.db.QueryRowContext(ctx, q, args...).Scan(&id)
+	err = conn(ctx, r.db).QueryRowxContext(ctx, q, args...).Scan(&id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			err = user.ErrExists
@@ -52,13 +56,16 @@ func (r *UserRepository) Create(ctx context.Context, u user.Entity) (id string,
 func (r *UserRepository) Update(ctx context.Context, id string, u user.Entity) (err error) {
 	sets, args := r.prepareArgs(u)
 	if len(sets) > 0 {
-		args = append(args, id)
-		q := fmt.Sprintf("UPDATE users SET %s WHERE id = $%d RETURNING ID", strings.Join(sets, ", "), len(args))
+		args = append(args, id, u.Version)
+		q := fmt.Sprintf("UPDATE users SET %s, version = version + 1 WHERE id = $%d AND deleted_at IS NULL AND %s RETURNING ID", strings.Join(sets, ", "), len(args)-1, versionCheck(len(args)))
 
-		err = r.db.QueryRowContext(ctx, q, args...).Scan(&id)
+		err = conn(ctx, r.db).QueryRowxContext(ctx, q, args...).Scan(&id)
 		if err != nil {
 			if errors.Is(err, sql.ErrNoRows) {
-				err = user.ErrNotFound
+				err = missingOrConflict(ctx, conn(ctx, r.db), "users", id, user.ErrNotFound)
+			}
+			if err, ok := err.(*pq.Error); ok && err.Code.Name() == "unique_violation" {
+				return user.ErrExists
 			}
 		}
 	}
@@ -69,13 +76,56 @@ func (r *UserRepository) Update(ctx context.Context, id string, u user.Entity) (
 func (r *UserRepository) Get(ctx context.Context, id string) (u user.Entity, err error) {
 	u = user.Entity{}
 
+	q := "SELECT " + userColumns + " FROM users WHERE id = $1 AND " + visible(ctx)
+
+	if err = sqlx.GetContext(ctx, conn(ctx, r.db), &u, q, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = user.ErrNotFound
+			return
+		}
+	}
+
+	return
+}
+
+func (r *UserRepository) GetByEmail(ctx context.Context, email string) (u user.Entity, err error) {
+	u = user.Entity{}
+
+	q := "SELECT " + userColumns + " FROM users WHERE email = $1 AND " + visible(ctx)
+
+	if err = sqlx.GetContext(ctx, conn(ctx, r.db), &u, q, email); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = user.ErrNotFound
+			return
+		}
+	}
+
+	return
+}
+
+// Lock takes a row lock that conflicts with the key share lock foreign keys
+// take on the user, so rows pointed at it wait for the unit of work to end.
+func (r *UserRepository) Lock(ctx context.Context, id string) (err error) {
+	q := "SELECT id FROM users WHERE id = $1 AND deleted_at IS NULL FOR UPDATE"
+
+	if err = conn(ctx, r.db).QueryRowxContext(ctx, q, id).Scan(&id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = user.ErrNotFound
+		}
+	}
+
+	return
+}
+
+func (r *UserRepository) Delete(ctx context.Context, id string, version int64) (err error) {
 	q := `
-	SELECT * FROM users WHERE id = $1
+	UPDATE users SET deleted_at = now(), version = version + 1
+	WHERE id = $1 AND deleted_at IS NULL AND ` + versionCheck(2) + ` RETURNING id
 	`
 
-	if err = r.db.GetContext(ctx, &u, q, id); err != nil {
+	if err = conn(ctx, r.db).QueryRowxContext(ctx, q, id, version).Scan(&id); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			err = user.ErrNotFound
+			err = missingOrConflict(ctx, conn(ctx, r.db), "users", id, user.ErrNotFound)
 			return
 		}
 	}
@@ -83,27 +133,44 @@ func (r *UserRepository) Get(ctx context.Context, id string) (u user.Entity, err
 	return
 }
 
-func (r *UserRepository) Delete(ctx context.Context, id string) (err error) {
+func (r *UserRepository) Restore(ctx context.Context, id string) (err error) {
 	q := `
-	DELETE FROM users WHERE id = $1 RETURNING id
+	UPDATE users SET deleted_at = NULL, version = version + 1 WHERE id = $1 AND deleted_at IS NOT NULL RETURNING id
 	`
 
-	if err = r.db.QueryRowContext(ctx, q, id).Scan(&id); err != nil {
+	if err = conn(ctx, r.db).QueryRowxContext(ctx, q, id).Scan(&id); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			err = user.ErrNotFound
 			return
 		}
+		// the email is only unique among live users
+		if err, ok := err.(*pq.Error); ok && err.Code.Name() == "unique_violation" {
+			return user.ErrEmailTaken
+		}
 	}
 
 	return
 }
 
-func (r *UserRepository) List(ctx context.Context) (users []user.Entity, err error) {
-	users = []user.Entity{}
+// Purge deletes the users for good, the foreign keys unset them as authors and
+// managers and drop their memberships.
+func (r *UserRepository) Purge(ctx context.Context, before time.Time) (n int64, err error) {
+	q := `
+	DELETE FROM users WHERE deleted_at < $1
+	`
+
+	res, err := conn(ctx, r.db).ExecContext(ctx, q, before)
+	if err != nil {
+		return
+	}
+
+	return res.RowsAffected()
+}
 
-	q := "SELECT * FROM users"
+func (r *UserRepository) List(ctx context.Context, page domain.PageRequest) (users domain.Page[user.Entity], err error) {
+	l := listing{table: "users", columns: userColumns, dateColumn: "registration_date"}
 
-	err = r.db.SelectContext(ctx, &users, q)
+	users, err = paginate(ctx, conn(ctx, r.db), l, page, r.cursor)
 	if err != nil {
 		return
 	}
@@ -111,19 +178,23 @@ func (r *UserRepository) List(ctx context.Context) (users []user.Entity, err err
 	return
 }
 
-func (r *UserRepository) Search(ctx context.Context, filter, value string) (users []user.Entity, err error) {
-	users = []user.Entity{}
-
+func (r *UserRepository) Search(ctx context.Context, filter, value string, page domain.PageRequest) (users domain.Page[user.Entity], err error) {
 	filter = r.prepareFilterArg(filter)
 
-	q := fmt.Sprintf("SELECT * FROM users WHERE %s = $1", filter)
+	l := listing{
+		table:      "users",
+		columns:    userColumns,
+		dateColumn: "registration_date",
+		where:      fmt.Sprintf("%s = $1", filter),
+		args:       []any{value},
+	}
 
-	err = r.db.SelectContext(ctx, &users, q, value)
+	users, err = paginate(ctx, conn(ctx, r.db), l, page, r.cursor)
 	if err != nil {
 		return
 	}
 
-	if len(users) == 0 {
+	if users.Total == 0 {
 		err = user.ErrNotFound
 		return
 	}
@@ -131,6 +202,32 @@ func (r *UserRepository) Search(ctx context.Context, filter, value string) (user
 	return
 }
 
+func (r *UserRepository) FullTextSearch(ctx context.Context, query string, limit int) (users []domain.Ranked[user.Entity], err error) {
+	users = []domain.Ranked[user.Entity]{}
+
+	rows := []struct {
+		user.Entity
+		Rank float64 `db:"rank"`
+	}{}
+
+	q := fullTextQuery("users", userColumns, "simple")
+
+	err = sqlx.SelectContext(ctx, conn(ctx, r.db), &rows, q, query, limit)
+	if err != nil {
+		return
+	}
+
+	for _, row := range rows {
+		users = append(users, domain.Ranked[user.Entity]{Entity: row.Entity, Rank: row.Rank})
+	}
+
+	return
+}
+
+func (r *UserRepository) cursor(u user.Entity) string {
+	return domain.EncodeCursor(string(u.RegistrationDate), u.ID)
+}
+
 func (r *UserRepository) prepareArgs(data user.Entity) (sets []string, args []any) {
 	if data.Name != "" {
 		args = append(args, data.Name)
@@ -147,6 +244,11 @@ func (r *UserRepository) prepareArgs(data user.Entity) (sets []string, args []an
 		sets = append(sets, fmt.Sprintf("role=$%d", len(args)))
 	}
 
+	if data.PasswordHash != "" {
+		args = append(args, data.PasswordHash)
+		sets = append(sets, fmt.Sprintf("password_hash=$%d", len(args)))
+	}
+
 	return
 }
 
@@ -2,9 +2,18 @@ package repository
 
 import (
 	"project-management/config"
+	"project-management/internal/domain"
+	"project-management/internal/domain/attachment"
+	"project-management/internal/domain/audit"
+	"project-management/internal/domain/comment"
+	"project-management/internal/domain/label"
+	"project-management/internal/domain/milestone"
 	"project-management/internal/domain/project"
+	"project-management/internal/domain/sprint"
 	"project-management/internal/domain/task"
 	"project-management/internal/domain/user"
+	"project-management/internal/repository/filesystem"
+	"project-management/internal/repository/memory"
 	"project-management/internal/repository/postgres"
 )
 
@@ -12,10 +21,26 @@ type Configuration func(r *Repository) error
 
 type Repository struct {
 	postgres postgres.DB
+	memory   *memory.DB
 
 	User    user.Repository
 	Task    task.Repository
 	Project project.Repository
+
+	ProjectMember  project.MemberRepository
+	TaskWorkflow   task.WorkflowRepository
+	TaskDependency task.DependencyRepository
+	Audit          audit.Repository
+	Comment        comment.Repository
+	Attachment     attachment.Repository
+	Label          label.Repository
+	Sprint         sprint.Repository
+	Milestone      milestone.Repository
+
+	// Tx runs units of work across the repositories above
+	Tx domain.TxManager
+
+	Blob attachment.BlobStore
 }
 
 func New(configs ...Configuration) (s *Repository, err error) {
@@ -46,7 +71,53 @@ func WithPostgresStore(cfg config.DB) Configuration {
 		s.User = postgres.NewUserRepository(s.postgres.Client)
 		s.Task = postgres.NewTaskRepository(s.postgres.Client)
 		s.Project = postgres.NewProjectRepository(s.postgres.Client)
+		s.ProjectMember = postgres.NewProjectMemberRepository(s.postgres.Client)
+		s.TaskWorkflow = postgres.NewTaskWorkflowRepository(s.postgres.Client)
+		s.TaskDependency = postgres.NewTaskDependencyRepository(s.postgres.Client)
+		s.Audit = postgres.NewAuditRepository(s.postgres.Client)
+		s.Comment = postgres.NewCommentRepository(s.postgres.Client)
+		s.Attachment = postgres.NewAttachmentRepository(s.postgres.Client)
+		s.Label = postgres.NewLabelRepository(s.postgres.Client)
+		s.Sprint = postgres.NewSprintRepository(s.postgres.Client)
+		s.Milestone = postgres.NewMilestoneRepository(s.postgres.Client)
+		s.Tx = postgres.NewTxManager(s.postgres.Client)
 
 		return
 	}
 }
+
+func WithMemoryStore() Configuration {
+	return func(s *Repository) (err error) {
+		// Everything lives in process memory and is lost on restart, handy for demos and tests
+		s.memory = memory.New()
+
+		s.User = memory.NewUserRepository(s.memory)
+		s.Task = memory.NewTaskRepository(s.memory)
+		s.Project = memory.NewProjectRepository(s.memory)
+		s.ProjectMember = memory.NewProjectMemberRepository(s.memory)
+		s.TaskWorkflow = memory.NewTaskWorkflowRepository(s.memory)
+		s.TaskDependency = memory.NewTaskDependencyRepository(s.memory)
+		s.Audit = memory.NewAuditRepository(s.memory)
+		s.Comment = memory.NewCommentRepository(s.memory)
+		s.Attachment = memory.NewAttachmentRepository(s.memory)
+		s.Label = memory.NewLabelRepository(s.memory)
+		s.Sprint = memory.NewSprintRepository(s.memory)
+		s.Milestone = memory.NewMilestoneRepository(s.memory)
+		s.Tx = memory.NewTxManager(s.memory)
+
+		return
+	}
+}
+
+// WithLocalBlobStore keeps attachment content in files below dir, an S3
+// compatible store can be plugged in the same way.
+func WithLocalBlobStore(dir string) Configuration {
+	return func(s *Repository) (err error) {
+		s.Blob, err = filesystem.NewBlobStore(dir)
+		return
+	}
+}
+
+func (s *Repository) Close() error {
+	return s.postgres.Close()
+}
@@ -0,0 +1,178 @@
+package management
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"path"
+	"project-management/internal/domain"
+	"project-management/internal/domain/attachment"
+	"project-management/pkg/log"
+	"strings"
+	"time"
+)
+
+func (s *Service) ListAttachments(ctx context.Context, taskID string) (res []attachment.Response, err error) {
+	logger := log.LoggerFromContext(ctx)
+
+	if _, err = s.taskRepository.Get(ctx, taskID); err != nil {
+		logger.Err(err).Stack().Msg("failed to list attachments")
+		return
+	}
+
+	data, err := s.attachmentRepository.List(ctx, taskID)
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to list attachments")
+		return
+	}
+
+	res = attachment.ParseFromEntities(data)
+
+	return
+}
+
+// UploadAttachment stores the content read from body and records it on the
+// task. The content type is detected from the first bytes and the size is
+// checked while reading, so nothing larger than the limit is kept.
+func (s *Service) UploadAttachment(ctx context.Context, taskID, name string, body io.Reader) (res attachment.Response, err error) {
+	logger := log.LoggerFromContext(ctx)
+
+	t, err := s.taskRepository.Get(ctx, taskID)
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to upload attachment")
+		return
+	}
+
+	actor, err := s.authorizeTaskEdit(ctx, t.ProjectID)
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to upload attachment")
+		return
+	}
+
+	name = path.Base(strings.ReplaceAll(name, "\\", "/"))
+	if name == "" || name == "." || name == "/" || len(name) > 255 {
+		err = attachment.ErrInvalidName
+		logger.Err(err).Stack().Msg("failed to upload attachment")
+		return
+	}
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(body, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		logger.Err(err).Stack().Msg("failed to upload attachment")
+		return
+	}
+	head = head[:n]
+
+	data := attachment.Entity{
+		ID:          domain.GenerateID(),
+		TaskID:      taskID,
+		Name:        name,
+		ContentType: http.DetectContentType(head),
+		UploaderID:  actor.UserID(),
+		CreatedAt:   time.Now().UTC(),
+	}
+
+	if !s.attachmentLimits.Allows(data.ContentType) {
+		err = attachment.ErrTypeNotAllowed
+		logger.Err(err).Stack().Str("content_type", data.ContentType).Msg("failed to upload attachment")
+		return
+	}
+
+	content := io.LimitReader(io.MultiReader(bytes.NewReader(head), body), s.attachmentLimits.MaxSize+1)
+
+	data.Size, err = s.blobStore.Put(ctx, data.Key(), content)
+	if err == nil && data.Size > s.attachmentLimits.MaxSize {
+		err = attachment.ErrTooLarge
+	}
+	if err == nil {
+		_, err = s.attachmentRepository.Create(ctx, data)
+	}
+	if err != nil {
+		s.deleteBlob(ctx, data.Key())
+		logger.Err(err).Stack().Msg("failed to upload attachment")
+		return
+	}
+
+	res = attachment.ParseFromEntity(data)
+
+	return
+}
+
+// OpenAttachment returns the metadata and the content of an attachment, the
+// caller has to close the content.
+func (s *Service) OpenAttachment(ctx context.Context, taskID, id string) (data attachment.Entity, content io.ReadCloser, err error) {
+	logger := log.LoggerFromContext(ctx)
+
+	data, err = s.taskAttachment(ctx, taskID, id)
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to open attachment")
+		return
+	}
+
+	content, err = s.blobStore.Open(ctx, data.Key())
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to open attachment")
+		return
+	}
+
+	return
+}
+
+func (s *Service) DeleteAttachment(ctx context.Context, taskID, id string) (err error) {
+	logger := log.LoggerFromContext(ctx)
+
+	data, err := s.taskAttachment(ctx, taskID, id)
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to delete attachment")
+		return
+	}
+
+	t, err := s.taskRepository.Get(ctx, taskID)
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to delete attachment")
+		return
+	}
+
+	if _, err = s.authorizeTaskEdit(ctx, t.ProjectID); err != nil {
+		logger.Err(err).Stack().Msg("failed to delete attachment")
+		return
+	}
+
+	if err = s.attachmentRepository.Delete(ctx, id); err != nil {
+		logger.Err(err).Stack().Msg("failed to delete attachment")
+		return
+	}
+
+	s.deleteBlob(ctx, data.Key())
+
+	return
+}
+
+// taskAttachment returns the attachment when it belongs to a visible task.
+func (s *Service) taskAttachment(ctx context.Context, taskID, id string) (data attachment.Entity, err error) {
+	if _, err = s.taskRepository.Get(ctx, taskID); err != nil {
+		return
+	}
+
+	data, err = s.attachmentRepository.Get(ctx, id)
+	if err != nil {
+		return
+	}
+
+	if data.TaskID != taskID {
+		err = attachment.ErrNotFound
+	}
+
+	return
+}
+
+// deleteBlob removes content that is no longer referenced. A failure only
+// leaves an orphaned blob behind, so it is logged and otherwise ignored.
+func (s *Service) deleteBlob(ctx context.Context, key string) {
+	if err := s.blobStore.Delete(ctx, key); err != nil {
+		log.LoggerFromContext(ctx).Err(err).Stack().Str("key", key).Msg("failed to delete attachment content")
+	}
+}
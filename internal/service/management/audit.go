@@ -0,0 +1,64 @@
+package management
+
+import (
+	"context"
+	"project-management/internal/domain"
+	"project-management/internal/domain/audit"
+	"project-management/pkg/log"
+	"project-management/pkg/token"
+	"time"
+)
+
+func (s *Service) ListAudit(ctx context.Context, filter audit.Filter, page domain.PageRequest) (res domain.Page[audit.Response], err error) {
+	logger := log.LoggerFromContext(ctx)
+
+	if _, err = s.authorizeAudit(ctx); err != nil {
+		logger.Err(err).Stack().Msg("failed to list audit log")
+		return
+	}
+
+	data, err := s.auditRepository.List(ctx, filter, page)
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to list audit log")
+		return
+	}
+
+	res = domain.MapPage(data, audit.ParseFromEntry)
+
+	return
+}
+
+// record writes the audit entry of a change. It runs in the unit of work of
+// the change, which fails along with it so that no change goes unrecorded.
+// before and after are the API representations of the entity, nil for created
+// and deleted entities.
+func (s *Service) record(ctx context.Context, entityType, entityID, action string, before, after any) error {
+	changes, err := audit.Diff(before, after)
+	if err != nil {
+		return err
+	}
+
+	return s.recordChanges(ctx, entityType, entityID, action, changes)
+}
+
+func (s *Service) recordChanges(ctx context.Context, entityType, entityID, action string, changes audit.Changes) error {
+	// updates that change nothing are not worth an entry
+	if action == audit.ActionUpdate && len(changes) == 0 {
+		return nil
+	}
+
+	// changes made without an authenticated user, like seeding the admin,
+	// are recorded without an actor
+	claims, _ := token.ClaimsFromContext(ctx)
+
+	e := audit.Entry{
+		ActorID:    claims.UserID(),
+		EntityType: entityType,
+		EntityID:   entityID,
+		Action:     action,
+		Changes:    changes,
+		CreatedAt:  time.Now().UTC(),
+	}
+
+	return s.auditRepository.Record(ctx, e)
+}
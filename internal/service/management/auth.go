@@ -0,0 +1,140 @@
+package management
+
+import (
+	"context"
+	"errors"
+	"project-management/internal/domain"
+	"project-management/internal/domain/audit"
+	"project-management/internal/domain/auth"
+	"project-management/internal/domain/user"
+	"project-management/pkg/log"
+	"project-management/pkg/token"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func (s *Service) Login(ctx context.Context, req auth.LoginRequest) (res auth.TokenResponse, err error) {
+	logger := log.LoggerFromContext(ctx)
+
+	data, err := s.userRepostitory.GetByEmail(ctx, req.Email)
+	if err != nil {
+		if errors.Is(err, user.ErrNotFound) {
+			err = auth.ErrInvalidCredentials
+		}
+		logger.Err(err).Stack().Msg("failed to login")
+		return
+	}
+
+	if err = bcrypt.CompareHashAndPassword([]byte(data.PasswordHash), []byte(req.Password)); err != nil {
+		err = auth.ErrInvalidCredentials
+		logger.Err(err).Stack().Msg("failed to login")
+		return
+	}
+
+	return s.issueTokens(data)
+}
+
+// Refresh exchanges a refresh token for a new token pair. The user is loaded
+// again so that a changed role or a deleted account take effect.
+func (s *Service) Refresh(ctx context.Context, req auth.RefreshRequest) (res auth.TokenResponse, err error) {
+	logger := log.LoggerFromContext(ctx)
+
+	claims, err := s.tokenManager.Parse(req.RefreshToken, token.KindRefresh)
+	if err != nil {
+		err = auth.ErrInvalidToken
+		logger.Err(err).Stack().Msg("failed to refresh token")
+		return
+	}
+
+	data, err := s.userRepostitory.Get(ctx, claims.UserID())
+	if err != nil {
+		if errors.Is(err, user.ErrNotFound) {
+			err = auth.ErrInvalidToken
+		}
+		logger.Err(err).Stack().Msg("failed to refresh token")
+		return
+	}
+
+	return s.issueTokens(data)
+}
+
+// SeedAdmin creates an admin account unless a user with the email already
+// exists, so that a fresh deployment has someone who can log in.
+func (s *Service) SeedAdmin(ctx context.Context, email, password string) (err error) {
+	logger := log.LoggerFromContext(ctx)
+
+	_, err = s.userRepostitory.GetByEmail(ctx, email)
+	if err == nil || !errors.Is(err, user.ErrNotFound) {
+		return
+	}
+
+	hash, err := hashPassword(password)
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to hash password")
+		return
+	}
+
+	data := user.Entity{
+		ID:               domain.GenerateID(),
+		Name:             "admin",
+		Email:            email,
+		RegistrationDate: domain.OnlyDate(time.Now().Format(domain.DateLayout)),
+		Role:             user.RoleAdmin,
+		PasswordHash:     hash,
+	}
+
+	err = s.withinTx(ctx, func(ctx context.Context) (err error) {
+		if _, err = s.userRepostitory.Create(ctx, data); err != nil {
+			return
+		}
+
+		return s.record(ctx, audit.EntityUser, data.ID, audit.ActionCreate, nil, user.ParseFromEntity(data))
+	})
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to seed admin")
+		return
+	}
+
+	return
+}
+
+func (s *Service) issueTokens(u user.Entity) (res auth.TokenResponse, err error) {
+	access, expiresAt, err := s.tokenManager.Issue(u.ID, u.Role, token.KindAccess)
+	if err != nil {
+		return
+	}
+
+	refresh, _, err := s.tokenManager.Issue(u.ID, u.Role, token.KindRefresh)
+	if err != nil {
+		return
+	}
+
+	res = auth.TokenResponse{
+		AccessToken:  access,
+		RefreshToken: refresh,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(time.Until(expiresAt).Seconds()),
+	}
+
+	return
+}
+
+// actor returns the claims of the authenticated user the request is made by.
+func (s *Service) actor(ctx context.Context) (claims token.Claims, err error) {
+	claims, ok := token.ClaimsFromContext(ctx)
+	if !ok {
+		err = auth.ErrUnauthenticated
+	}
+
+	return
+}
+
+func hashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+
+	return string(hash), nil
+}
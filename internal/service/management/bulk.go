@@ -0,0 +1,185 @@
+package management
+
+import (
+	"context"
+	"project-management/internal/domain/audit"
+	"project-management/internal/domain/task"
+	"project-management/pkg/log"
+)
+
+// BulkTasks runs the operations of the request in one transaction. Every
+// operation is checked like its single counterpart against the tasks as they
+// are before the batch, which are locked until it is stored. In the
+// all_or_nothing mode one failing operation cancels all of them, in per_item
+// mode the others are still stored.
+func (s *Service) BulkTasks(ctx context.Context, req task.BulkRequest) (res task.BulkResponse, err error) {
+	logger := log.LoggerFromContext(ctx)
+
+	if _, err = s.actor(ctx); err != nil {
+		logger.Err(err).Stack().Msg("failed to run bulk task operations")
+		return
+	}
+
+	atomic := req.Mode != task.BulkPerItem
+	n := len(req.Operations)
+
+	res.Results = make([]task.BulkResult, n)
+	errs := make([]error, n)
+	writes := make([]task.Write, n)
+	currents := make([]task.Entity, n)
+	seen := map[string]bool{}
+
+	err = s.withinTx(ctx, func(ctx context.Context) (err error) {
+		var ids []string
+		for _, op := range req.Operations {
+			if op.ID != "" {
+				ids = append(ids, op.ID)
+			}
+		}
+
+		if err = s.taskRepository.Lock(ctx, ids...); err != nil {
+			return
+		}
+
+		for i, op := range req.Operations {
+			res.Results[i] = task.BulkResult{Index: i, Op: op.Op, ID: op.ID}
+
+			if verrs := op.Validate(); verrs != nil {
+				res.Results[i].Errors = verrs
+				errs[i] = task.ErrBulkInvalid
+				continue
+			}
+
+			if op.ID != "" {
+				if seen[op.ID] {
+					errs[i] = task.ErrBulkDuplicate
+					continue
+				}
+				seen[op.ID] = true
+			}
+
+			currents[i], writes[i], errs[i] = s.prepareBulk(ctx, op)
+		}
+
+		// nothing is stored when an all_or_nothing batch already failed its
+		// checks
+		if atomic && failed(errs) {
+			return
+		}
+
+		var stored []task.Write
+		var indexes []int
+		for i, w := range writes {
+			if errs[i] == nil {
+				stored = append(stored, w)
+				indexes = append(indexes, i)
+			}
+		}
+
+		werrs, err := s.taskRepository.Bulk(ctx, stored, atomic)
+		if err != nil {
+			return
+		}
+
+		for k, werr := range werrs {
+			errs[indexes[k]] = werr
+		}
+		if atomic && failed(errs) {
+			return
+		}
+
+		for _, i := range indexes {
+			if errs[i] != nil {
+				continue
+			}
+
+			if err = s.recordBulk(ctx, writes[i], currents[i]); err != nil {
+				return
+			}
+		}
+
+		return
+	})
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to run bulk task operations")
+		return task.BulkResponse{}, err
+	}
+
+	aborted := atomic && failed(errs)
+	for i := range res.Results {
+		switch {
+		case errs[i] != nil:
+			logger.Err(errs[i]).Stack().Msg("failed to run bulk task operation")
+			res.Results[i].Error = errs[i].Error()
+			res.Failed++
+		case aborted:
+			res.Results[i].Error = task.ErrBulkAborted.Error()
+			res.Failed++
+		default:
+			res.Results[i].ID = writes[i].ID
+			res.Succeeded++
+		}
+	}
+
+	return
+}
+
+// prepareBulk checks a single operation of a bulk request and returns the
+// task as it is now along with the write of the operation.
+func (s *Service) prepareBulk(ctx context.Context, op task.BulkOperation) (current task.Entity, w task.Write, err error) {
+	switch op.Op {
+	case task.OpCreate:
+		var data task.Entity
+		if data, err = s.prepareTask(ctx, *op.Create); err != nil {
+			return
+		}
+
+		w = task.Write{Op: task.OpCreate, ID: data.ID, Entity: data}
+
+	case task.OpUpdate:
+		current, w, err = s.prepareUpdate(ctx, op.ID, op.Version, op.Update.Update(), op.Update.Clear())
+		if err != nil {
+			return
+		}
+
+		// moving a task detaches its labels, sprint and milestone outside of
+		// the task table, which a batch does not cover
+		if w.Entity.ProjectID != "" && w.Entity.ProjectID != current.ProjectID {
+			err = task.ErrBulkMove
+			return
+		}
+
+	case task.OpDelete:
+		if current, err = s.prepareDelete(ctx, op.ID, op.Version); err != nil {
+			return
+		}
+
+		w = task.Write{Op: task.OpDelete, ID: op.ID, Entity: task.Entity{Version: current.Version}}
+	}
+
+	return
+}
+
+// recordBulk writes a stored operation of a bulk request to the audit log.
+func (s *Service) recordBulk(ctx context.Context, w task.Write, current task.Entity) error {
+	switch w.Op {
+	case task.OpCreate:
+		return s.record(ctx, audit.EntityTask, w.ID, audit.ActionCreate, nil, task.ParseFromEntity(w.Entity))
+	case task.OpUpdate:
+		return s.recordTaskUpdate(ctx, current)
+	case task.OpDelete:
+		return s.record(ctx, audit.EntityTask, w.ID, audit.ActionDelete, task.ParseFromEntity(current), nil)
+	}
+
+	return nil
+}
+
+func failed(errs []error) bool {
+	for _, err := range errs {
+		if err != nil {
+			return true
+		}
+	}
+
+	return false
+}
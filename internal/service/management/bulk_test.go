@@ -0,0 +1,97 @@
+package management
+
+import (
+	"testing"
+
+	"project-management/internal/domain"
+	"project-management/internal/domain/task"
+	"project-management/internal/domain/user"
+)
+
+func TestBulkTasks(t *testing.T) {
+	tests := []struct {
+		name      string
+		mode      string
+		fail      bool
+		succeeded int
+		stored    bool
+	}{
+		{name: "all_or_nothing stores every operation", mode: task.BulkAllOrNothing, succeeded: 3, stored: true},
+		{name: "all_or_nothing is the default", mode: "", fail: true},
+		{name: "all_or_nothing cancels the batch", mode: task.BulkAllOrNothing, fail: true},
+		{name: "per_item keeps the operations that succeed", mode: task.BulkPerItem, fail: true, succeeded: 3, stored: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(t)
+			manager, developer := f.user(user.RoleManager), f.user(user.RoleDeveloper)
+			p := f.project(manager, developer)
+			updated, deleted := f.task(p, manager), f.task(p, manager)
+
+			ops := []task.BulkOperation{
+				{Op: task.OpCreate, Create: &task.Request{Title: "created", Description: "created", Priority: "low", ProjectID: p, AuthorID: manager}},
+				{Op: task.OpUpdate, ID: updated, Update: &task.PatchRequest{
+					Title:      domain.Nullable[string]{Set: true, Value: "updated"},
+					AssigneeID: domain.Nullable[string]{Set: true, Value: developer},
+				}},
+				{Op: task.OpDelete, ID: deleted},
+			}
+			if tt.fail {
+				ops = append(ops, task.BulkOperation{Op: task.OpDelete, ID: "unknown"})
+			}
+
+			res, err := f.s.BulkTasks(f.ctx, task.BulkRequest{Mode: tt.mode, Operations: ops})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if res.Succeeded != tt.succeeded || res.Failed != len(ops)-tt.succeeded {
+				t.Errorf("got %d succeeded and %d failed, want %d and %d", res.Succeeded, res.Failed, tt.succeeded, len(ops)-tt.succeeded)
+			}
+			if tt.fail && res.Results[3].Error != task.ErrNotFound.Error() {
+				t.Errorf("got error %q for the failing operation, want %q", res.Results[3].Error, task.ErrNotFound.Error())
+			}
+			if tt.fail && !tt.stored && res.Results[0].Error != task.ErrBulkAborted.Error() {
+				t.Errorf("got error %q for a cancelled operation, want %q", res.Results[0].Error, task.ErrBulkAborted.Error())
+			}
+
+			u := f.get(updated)
+			if got := u.Title == "updated" && u.AssigneeID == developer; got != tt.stored {
+				t.Errorf("update stored: got %v, want %v", got, tt.stored)
+			}
+			if _, err := f.s.taskRepository.Get(f.ctx, deleted); (err != nil) != tt.stored {
+				t.Errorf("delete stored: got %v, want %v", err != nil, tt.stored)
+			}
+
+			page, err := f.s.taskRepository.List(f.ctx, task.Filter{}.With(task.Equals(task.FieldTitle, "created")), domain.PageRequest{Limit: 10})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := page.Total == 1; got != tt.stored {
+				t.Errorf("create stored: got %v, want %v", got, tt.stored)
+			}
+		})
+	}
+}
+
+func TestBulkTasksRejectsNonMemberAssignee(t *testing.T) {
+	f := newFixture(t)
+	manager, outsider := f.user(user.RoleManager), f.user(user.RoleDeveloper)
+	p := f.project(manager)
+	id := f.task(p, manager)
+
+	res, err := f.s.BulkTasks(f.ctx, task.BulkRequest{Operations: []task.BulkOperation{
+		{Op: task.OpUpdate, ID: id, Update: &task.PatchRequest{AssigneeID: domain.Nullable[string]{Set: true, Value: outsider}}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res.Failed != 1 {
+		t.Errorf("got %d failed, want 1", res.Failed)
+	}
+	if got := f.get(id).AssigneeID; got != "" {
+		t.Errorf("got assignee %q, want none", got)
+	}
+}
@@ -0,0 +1,156 @@
+package management
+
+import (
+	"context"
+	"errors"
+	"project-management/internal/domain"
+	"project-management/internal/domain/comment"
+	"project-management/pkg/log"
+	"time"
+)
+
+func (s *Service) ListComments(ctx context.Context, taskID string) (res []comment.Response, err error) {
+	logger := log.LoggerFromContext(ctx)
+
+	if _, err = s.taskRepository.Get(ctx, taskID); err != nil {
+		logger.Err(err).Stack().Msg("failed to list comments")
+		return
+	}
+
+	data, err := s.commentRepository.List(ctx, taskID)
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to list comments")
+		return
+	}
+
+	res = comment.ParseThreads(data)
+
+	return
+}
+
+// CreateComment adds a comment by the authenticated user, replies have to
+// answer a comment of the same task.
+func (s *Service) CreateComment(ctx context.Context, taskID string, req comment.Request) (id string, err error) {
+	logger := log.LoggerFromContext(ctx)
+
+	t, err := s.taskRepository.Get(ctx, taskID)
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to create comment")
+		return
+	}
+
+	actor, err := s.authorizeComment(ctx, t.ProjectID)
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to create comment")
+		return
+	}
+
+	if req.ParentID != "" {
+		var parent comment.Entity
+		parent, err = s.commentRepository.Get(ctx, req.ParentID)
+		if err == nil && parent.TaskID != taskID {
+			err = comment.ErrParentNotFound
+		}
+		if err != nil {
+			if errors.Is(err, comment.ErrNotFound) {
+				err = comment.ErrParentNotFound
+			}
+			logger.Err(err).Stack().Msg("failed to create comment")
+			return
+		}
+	}
+
+	now := time.Now().UTC()
+
+	data := comment.Entity{
+		ID:        domain.GenerateID(),
+		TaskID:    taskID,
+		ParentID:  req.ParentID,
+		AuthorID:  actor.UserID(),
+		Body:      req.Body,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	id, err = s.commentRepository.Create(ctx, data)
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to create comment")
+		return
+	}
+
+	return
+}
+
+func (s *Service) UpdateComment(ctx context.Context, taskID, id string, req comment.UpdateRequest) (err error) {
+	logger := log.LoggerFromContext(ctx)
+
+	current, err := s.taskComment(ctx, taskID, id)
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to update comment")
+		return
+	}
+
+	if current.DeletedAt != nil {
+		err = comment.ErrDeleted
+		logger.Err(err).Stack().Msg("failed to update comment")
+		return
+	}
+
+	if _, err = s.authorizeCommentEdit(ctx, current); err != nil {
+		logger.Err(err).Stack().Msg("failed to update comment")
+		return
+	}
+
+	if err = s.commentRepository.Update(ctx, id, req.Body); err != nil {
+		logger.Err(err).Stack().Msg("failed to update comment")
+		return
+	}
+
+	return
+}
+
+func (s *Service) DeleteComment(ctx context.Context, taskID, id string) (err error) {
+	logger := log.LoggerFromContext(ctx)
+
+	current, err := s.taskComment(ctx, taskID, id)
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to delete comment")
+		return
+	}
+
+	if current.DeletedAt != nil {
+		err = comment.ErrDeleted
+		logger.Err(err).Stack().Msg("failed to delete comment")
+		return
+	}
+
+	if _, err = s.authorizeCommentEdit(ctx, current); err != nil {
+		logger.Err(err).Stack().Msg("failed to delete comment")
+		return
+	}
+
+	if err = s.commentRepository.Delete(ctx, id); err != nil {
+		logger.Err(err).Stack().Msg("failed to delete comment")
+		return
+	}
+
+	return
+}
+
+// taskComment returns the comment when it belongs to a visible task.
+func (s *Service) taskComment(ctx context.Context, taskID, id string) (c comment.Entity, err error) {
+	if _, err = s.taskRepository.Get(ctx, taskID); err != nil {
+		return
+	}
+
+	c, err = s.commentRepository.Get(ctx, id)
+	if err != nil {
+		return
+	}
+
+	if c.TaskID != taskID {
+		err = comment.ErrNotFound
+	}
+
+	return
+}
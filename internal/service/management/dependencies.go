@@ -0,0 +1,172 @@
+package management
+
+import (
+	"context"
+	"project-management/internal/domain"
+	"project-management/internal/domain/audit"
+	"project-management/internal/domain/task"
+	"project-management/pkg/log"
+)
+
+func (s *Service) GetTaskDependencies(ctx context.Context, taskID string) (res task.DependenciesResponse, err error) {
+	logger := log.LoggerFromContext(ctx)
+
+	if _, err = s.taskRepository.Get(ctx, taskID); err != nil {
+		logger.Err(err).Stack().Msg("failed to get task dependencies")
+		return
+	}
+
+	blockers, err := s.dependencyRepository.Blockers(ctx, taskID)
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to get task dependencies")
+		return
+	}
+
+	dependents, err := s.dependencyRepository.Dependents(ctx, taskID)
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to get task dependencies")
+		return
+	}
+
+	res = task.DependenciesResponse{
+		BlockedBy: task.ParseFromEntities(blockers),
+		Blocks:    task.ParseFromEntities(dependents),
+	}
+
+	return
+}
+
+// AddTaskDependency records that the task is blocked by req.BlockerID. The
+// dependency is refused when the task already blocks the blocker, directly or
+// through other tasks.
+func (s *Service) AddTaskDependency(ctx context.Context, taskID string, req task.DependencyRequest) (err error) {
+	logger := log.LoggerFromContext(ctx)
+
+	if req.BlockerID == taskID {
+		err = task.ErrSelfDependency
+		logger.Err(err).Stack().Msg("failed to add task dependency")
+		return
+	}
+
+	t, err := s.taskRepository.Get(ctx, taskID)
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to add task dependency")
+		return
+	}
+
+	if _, err = s.authorizeTaskEdit(ctx, t.ProjectID); err != nil {
+		logger.Err(err).Stack().Msg("failed to add task dependency")
+		return
+	}
+
+	// the check and the insert run under the same locks, otherwise A->B and
+	// B->A added at once would both find no cycle
+	err = s.withinTx(ctx, func(ctx context.Context) (err error) {
+		if err = s.dependencyRepository.Lock(ctx, lockOrder(taskID, req.BlockerID)...); err != nil {
+			return
+		}
+
+		cycle, err := s.blocks(ctx, taskID, req.BlockerID)
+		if err != nil {
+			return
+		}
+
+		if cycle {
+			return task.ErrDependencyCycle
+		}
+
+		if err = s.dependencyRepository.AddDependency(ctx, req.BlockerID, taskID); err != nil {
+			return
+		}
+
+		return s.recordChanges(ctx, audit.EntityTask, taskID, audit.ActionUpdate, audit.Changes{"blocked_by": {New: req.BlockerID}})
+	})
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to add task dependency")
+		return
+	}
+
+	return
+}
+
+func (s *Service) RemoveTaskDependency(ctx context.Context, taskID, blockerID string) (err error) {
+	logger := log.LoggerFromContext(ctx)
+
+	t, err := s.taskRepository.Get(ctx, taskID)
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to remove task dependency")
+		return
+	}
+
+	if _, err = s.authorizeTaskEdit(ctx, t.ProjectID); err != nil {
+		logger.Err(err).Stack().Msg("failed to remove task dependency")
+		return
+	}
+
+	err = s.withinTx(ctx, func(ctx context.Context) (err error) {
+		if err = s.dependencyRepository.RemoveDependency(ctx, blockerID, taskID); err != nil {
+			return
+		}
+
+		return s.recordChanges(ctx, audit.EntityTask, taskID, audit.ActionUpdate, audit.Changes{"blocked_by": {Old: blockerID}})
+	})
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to remove task dependency")
+		return
+	}
+
+	return
+}
+
+// blocks reports whether from blocks to, directly or through other tasks. The
+// walk goes through tasks in the trash too, they keep their dependencies and
+// would close the cycle once restored.
+func (s *Service) blocks(ctx context.Context, from, to string) (bool, error) {
+	ctx = domain.WithDeletedScope(ctx, domain.IncludeDeleted)
+
+	visited := map[string]bool{from: true}
+	queue := []string{from}
+
+	for len(queue) > 0 {
+		dependents, err := s.dependencyRepository.Dependents(ctx, queue[0])
+		if err != nil {
+			return false, err
+		}
+		queue = queue[1:]
+
+		for _, t := range dependents {
+			if t.ID == to {
+				return true, nil
+			}
+
+			if !visited[t.ID] {
+				visited[t.ID] = true
+				queue = append(queue, t.ID)
+			}
+		}
+	}
+
+	return false, nil
+}
+
+// requireBlockersDone fails with ErrBlocked while a blocker of the task is not
+// in a terminal status of its own project workflow.
+func (s *Service) requireBlockersDone(ctx context.Context, taskID string) error {
+	blockers, err := s.dependencyRepository.Blockers(ctx, taskID)
+	if err != nil {
+		return err
+	}
+
+	for _, b := range blockers {
+		w, err := s.projectWorkflow(ctx, b.ProjectID)
+		if err != nil {
+			return err
+		}
+
+		if !w.IsTerminal(b.Status) {
+			return task.ErrBlocked
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,91 @@
+package management
+
+import (
+	"errors"
+	"testing"
+
+	"project-management/internal/domain/task"
+	"project-management/internal/domain/user"
+)
+
+func TestAddTaskDependency(t *testing.T) {
+	f := newFixture(t)
+	manager := f.user(user.RoleManager)
+	p := f.project(manager)
+
+	// a blocks b, b blocks c
+	a, b, c, d := f.task(p, manager), f.task(p, manager), f.task(p, manager), f.task(p, manager)
+	for _, dep := range [][2]string{{a, b}, {b, c}} {
+		if err := f.s.AddTaskDependency(f.ctx, dep[1], task.DependencyRequest{BlockerID: dep[0]}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		blocked string
+		blocker string
+		err     error
+	}{
+		{name: "self", blocked: a, blocker: a, err: task.ErrSelfDependency},
+		{name: "direct cycle", blocked: a, blocker: b, err: task.ErrDependencyCycle},
+		{name: "cycle through another task", blocked: a, blocker: c, err: task.ErrDependencyCycle},
+		{name: "existing dependency", blocked: b, blocker: a, err: task.ErrDependencyExists},
+		{name: "unknown blocker", blocked: a, blocker: "unknown", err: task.ErrNotFound},
+		{name: "shortcut along the chain", blocked: c, blocker: a},
+		{name: "unrelated task", blocked: d, blocker: c},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := f.s.AddTaskDependency(f.ctx, tt.blocked, task.DependencyRequest{BlockerID: tt.blocker})
+			if !errors.Is(err, tt.err) {
+				t.Errorf("got error %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestBlocks(t *testing.T) {
+	f := newFixture(t)
+	manager := f.user(user.RoleManager)
+	p := f.project(manager)
+
+	// a blocks b and c, c blocks d, d blocks g in the trash, which blocks h,
+	// e stands apart
+	a, b, c, d, e := f.task(p, manager), f.task(p, manager), f.task(p, manager), f.task(p, manager), f.task(p, manager)
+	g, h := f.task(p, manager), f.task(p, manager)
+	for _, dep := range [][2]string{{a, b}, {a, c}, {c, d}, {d, g}, {g, h}} {
+		if err := f.s.dependencyRepository.AddDependency(f.ctx, dep[0], dep[1]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := f.s.taskRepository.Delete(f.ctx, g, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		from, to string
+		want     bool
+	}{
+		{name: "direct", from: a, to: b, want: true},
+		{name: "transitive", from: a, to: d, want: true},
+		{name: "reverse", from: d, to: a},
+		{name: "siblings", from: b, to: c},
+		{name: "unrelated", from: a, to: e},
+		{name: "through a deleted task", from: d, to: h, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := f.s.blocks(f.ctx, tt.from, tt.to)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,245 @@
+package management
+
+import (
+	"context"
+	"project-management/internal/domain"
+	"project-management/internal/domain/audit"
+	"project-management/internal/domain/label"
+	"project-management/internal/domain/task"
+	"project-management/pkg/log"
+)
+
+func (s *Service) ListLabels(ctx context.Context, projectID string) (res []label.Response, err error) {
+	logger := log.LoggerFromContext(ctx)
+
+	if _, err = s.projectRepository.Get(ctx, projectID); err != nil {
+		logger.Err(err).Stack().Msg("failed to list labels")
+		return
+	}
+
+	data, err := s.labelRepository.List(ctx, projectID)
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to list labels")
+		return
+	}
+
+	res = label.ParseFromEntities(data)
+
+	return
+}
+
+func (s *Service) CreateLabel(ctx context.Context, projectID string, req label.Request) (id string, err error) {
+	logger := log.LoggerFromContext(ctx)
+
+	p, err := s.projectRepository.Get(ctx, projectID)
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to create label")
+		return
+	}
+
+	if _, err = s.authorizeProjectEdit(ctx, p); err != nil {
+		logger.Err(err).Stack().Msg("failed to create label")
+		return
+	}
+
+	data := label.Entity{
+		ID:        domain.GenerateID(),
+		ProjectID: projectID,
+		Name:      req.Name,
+		Color:     req.Color,
+	}
+
+	if data.Color == "" {
+		data.Color = label.DefaultColor
+	}
+
+	err = s.withinTx(ctx, func(ctx context.Context) (err error) {
+		if id, err = s.labelRepository.Create(ctx, data); err != nil {
+			return
+		}
+
+		return s.recordChanges(ctx, audit.EntityProject, projectID, audit.ActionUpdate, audit.Changes{"label": {New: label.ParseFromEntity(data)}})
+	})
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to create label")
+		return
+	}
+
+	return
+}
+
+func (s *Service) UpdateLabel(ctx context.Context, projectID, id string, req label.UpdateRequest) (err error) {
+	logger := log.LoggerFromContext(ctx)
+
+	current, err := s.projectLabel(ctx, projectID, id)
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to update label")
+		return
+	}
+
+	data := label.Entity{
+		Name:  req.Name,
+		Color: req.Color,
+	}
+
+	err = s.withinTx(ctx, func(ctx context.Context) (err error) {
+		if err = s.labelRepository.Update(ctx, id, data); err != nil {
+			return
+		}
+
+		updated, err := s.labelRepository.Get(ctx, id)
+		if err != nil || updated == current {
+			return
+		}
+
+		return s.recordChanges(ctx, audit.EntityProject, projectID, audit.ActionUpdate, audit.Changes{"label": {Old: label.ParseFromEntity(current), New: label.ParseFromEntity(updated)}})
+	})
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to update label")
+		return
+	}
+
+	return
+}
+
+// DeleteLabel removes the label from the project and every task carrying it.
+func (s *Service) DeleteLabel(ctx context.Context, projectID, id string) (err error) {
+	logger := log.LoggerFromContext(ctx)
+
+	current, err := s.projectLabel(ctx, projectID, id)
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to delete label")
+		return
+	}
+
+	err = s.withinTx(ctx, func(ctx context.Context) (err error) {
+		if err = s.labelRepository.Delete(ctx, id); err != nil {
+			return
+		}
+
+		return s.recordChanges(ctx, audit.EntityProject, projectID, audit.ActionUpdate, audit.Changes{"label": {Old: label.ParseFromEntity(current)}})
+	})
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to delete label")
+		return
+	}
+
+	return
+}
+
+func (s *Service) ListTaskLabels(ctx context.Context, taskID string) (res []label.Response, err error) {
+	logger := log.LoggerFromContext(ctx)
+
+	if _, err = s.taskRepository.Get(ctx, taskID); err != nil {
+		logger.Err(err).Stack().Msg("failed to list task labels")
+		return
+	}
+
+	data, err := s.labelRepository.TaskLabels(ctx, taskID)
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to list task labels")
+		return
+	}
+
+	res = label.ParseFromEntities(data)
+
+	return
+}
+
+// AttachLabel puts a label of the task's project on the task.
+func (s *Service) AttachLabel(ctx context.Context, taskID, labelID string) (err error) {
+	logger := log.LoggerFromContext(ctx)
+
+	t, l, err := s.taskLabel(ctx, taskID, labelID)
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to attach label")
+		return
+	}
+
+	err = s.withinTx(ctx, func(ctx context.Context) (err error) {
+		if err = s.labelRepository.Attach(ctx, t.ID, l.ID); err != nil {
+			return
+		}
+
+		return s.recordChanges(ctx, audit.EntityTask, taskID, audit.ActionUpdate, audit.Changes{"label": {New: l.Name}})
+	})
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to attach label")
+		return
+	}
+
+	return
+}
+
+func (s *Service) DetachLabel(ctx context.Context, taskID, labelID string) (err error) {
+	logger := log.LoggerFromContext(ctx)
+
+	t, l, err := s.taskLabel(ctx, taskID, labelID)
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to detach label")
+		return
+	}
+
+	err = s.withinTx(ctx, func(ctx context.Context) (err error) {
+		if err = s.labelRepository.Detach(ctx, t.ID, l.ID); err != nil {
+			return
+		}
+
+		return s.recordChanges(ctx, audit.EntityTask, taskID, audit.ActionUpdate, audit.Changes{"label": {Old: l.Name}})
+	})
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to detach label")
+		return
+	}
+
+	return
+}
+
+// projectLabel returns the label of the project after checking that the
+// authenticated user may change the project.
+func (s *Service) projectLabel(ctx context.Context, projectID, id string) (l label.Entity, err error) {
+	p, err := s.projectRepository.Get(ctx, projectID)
+	if err != nil {
+		return
+	}
+
+	if _, err = s.authorizeProjectEdit(ctx, p); err != nil {
+		return
+	}
+
+	l, err = s.labelRepository.Get(ctx, id)
+	if err != nil {
+		return
+	}
+
+	if l.ProjectID != projectID {
+		err = label.ErrNotFound
+	}
+
+	return
+}
+
+// taskLabel returns the task and the label after checking that the
+// authenticated user may change the task and that the label belongs to its
+// project.
+func (s *Service) taskLabel(ctx context.Context, taskID, labelID string) (t task.Entity, l label.Entity, err error) {
+	t, err = s.taskRepository.Get(ctx, taskID)
+	if err != nil {
+		return
+	}
+
+	if _, err = s.authorizeTaskEdit(ctx, t.ProjectID); err != nil {
+		return
+	}
+
+	l, err = s.labelRepository.Get(ctx, labelID)
+	if err != nil {
+		return
+	}
+
+	if l.ProjectID != t.ProjectID {
+		err = label.ErrOtherProject
+	}
+
+	return
+}
@@ -0,0 +1,151 @@
+package management
+
+import (
+	"context"
+	"errors"
+	"project-management/internal/domain/audit"
+	"project-management/internal/domain/project"
+	"project-management/pkg/log"
+	"time"
+)
+
+func (s *Service) ListProjectMembers(ctx context.Context, projectID string) (res []project.MemberResponse, err error) {
+	logger := log.LoggerFromContext(ctx)
+
+	if _, err = s.projectRepository.Get(ctx, projectID); err != nil {
+		logger.Err(err).Stack().Msg("failed to list project members")
+		return
+	}
+
+	data, err := s.memberRepository.ListMembers(ctx, projectID)
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to list project members")
+		return
+	}
+
+	res = project.ParseFromMembers(data)
+
+	return
+}
+
+func (s *Service) AddProjectMember(ctx context.Context, projectID string, req project.MemberRequest) (err error) {
+	logger := log.LoggerFromContext(ctx)
+
+	p, err := s.projectRepository.Get(ctx, projectID)
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to add project member")
+		return
+	}
+
+	if _, err = s.authorizeProjectEdit(ctx, p); err != nil {
+		logger.Err(err).Stack().Msg("failed to add project member")
+		return
+	}
+
+	if _, err = s.userRepostitory.Get(ctx, req.UserID); err != nil {
+		logger.Err(err).Stack().Msg("failed to add project member")
+		return
+	}
+
+	data := project.Member{
+		ProjectID: projectID,
+		UserID:    req.UserID,
+		Role:      req.Role,
+		JoinedAt:  time.Now().UTC(),
+	}
+
+	err = s.withinTx(ctx, func(ctx context.Context) (err error) {
+		if err = s.memberRepository.AddMember(ctx, data); err != nil {
+			return
+		}
+
+		return s.record(ctx, audit.EntityProject, projectID, audit.ActionAddMember, nil, project.ParseFromMember(data))
+	})
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to add project member")
+		return
+	}
+
+	return
+}
+
+func (s *Service) RemoveProjectMember(ctx context.Context, projectID, userID string) (err error) {
+	logger := log.LoggerFromContext(ctx)
+
+	p, err := s.projectRepository.Get(ctx, projectID)
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to remove project member")
+		return
+	}
+
+	if _, err = s.authorizeProjectEdit(ctx, p); err != nil {
+		logger.Err(err).Stack().Msg("failed to remove project member")
+		return
+	}
+
+	if p.ManagerID == userID {
+		err = project.ErrRemoveManager
+		logger.Err(err).Stack().Msg("failed to remove project member")
+		return
+	}
+
+	m, err := s.memberRepository.GetMember(ctx, projectID, userID)
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to remove project member")
+		return
+	}
+
+	err = s.withinTx(ctx, func(ctx context.Context) (err error) {
+		if err = s.memberRepository.RemoveMember(ctx, projectID, userID); err != nil {
+			return
+		}
+
+		return s.record(ctx, audit.EntityProject, projectID, audit.ActionRemoveMember, project.ParseFromMember(m), nil)
+	})
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to remove project member")
+		return
+	}
+
+	return
+}
+
+// ensureManagerMembership makes the manager of a project one of its members,
+// a member in another role becomes a manager so that the manager may run the
+// transitions reserved to managers.
+func (s *Service) ensureManagerMembership(ctx context.Context, projectID, managerID string) error {
+	return s.memberRepository.SetMember(ctx, project.Member{
+		ProjectID: projectID,
+		UserID:    managerID,
+		Role:      project.MemberRoleManager,
+		JoinedAt:  time.Now().UTC(),
+	})
+}
+
+// ensureMembership adds the user to the project in the given role unless the
+// user is a member already.
+func (s *Service) ensureMembership(ctx context.Context, projectID, userID, role string) error {
+	m := project.Member{
+		ProjectID: projectID,
+		UserID:    userID,
+		Role:      role,
+		JoinedAt:  time.Now().UTC(),
+	}
+
+	if err := s.memberRepository.AddMember(ctx, m); err != nil && !errors.Is(err, project.ErrMemberExists) {
+		return err
+	}
+
+	return nil
+}
+
+// requireMember fails with project.ErrNotMember unless the user belongs to the
+// project in any role.
+func (s *Service) requireMember(ctx context.Context, projectID, userID string) error {
+	_, err := s.memberRepository.GetMember(ctx, projectID, userID)
+	if errors.Is(err, project.ErrMemberNotFound) {
+		return project.ErrNotMember
+	}
+
+	return err
+}
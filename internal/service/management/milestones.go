@@ -0,0 +1,293 @@
+package management
+
+import (
+	"context"
+	"project-management/internal/domain"
+	"project-management/internal/domain/audit"
+	"project-management/internal/domain/milestone"
+	"project-management/internal/domain/task"
+	"project-management/pkg/log"
+	"time"
+)
+
+// ListMilestones returns the milestones of the project with the progress of
+// their tasks.
+func (s *Service) ListMilestones(ctx context.Context, projectID string) (res []milestone.Response, err error) {
+	logger := log.LoggerFromContext(ctx)
+
+	if _, err = s.projectRepository.Get(ctx, projectID); err != nil {
+		logger.Err(err).Stack().Msg("failed to list milestones")
+		return
+	}
+
+	data, err := s.milestoneRepository.List(ctx, projectID)
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to list milestones")
+		return
+	}
+
+	res, err = s.parseMilestones(ctx, projectID, data)
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to list milestones")
+		return
+	}
+
+	return
+}
+
+func (s *Service) GetMilestone(ctx context.Context, projectID, id string) (res milestone.Response, err error) {
+	logger := log.LoggerFromContext(ctx)
+
+	if _, err = s.projectRepository.Get(ctx, projectID); err != nil {
+		logger.Err(err).Stack().Msg("failed to get milestone")
+		return
+	}
+
+	data, err := s.milestoneRepository.Get(ctx, id)
+	if err == nil && data.ProjectID != projectID {
+		err = milestone.ErrNotFound
+	}
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to get milestone")
+		return
+	}
+
+	parsed, err := s.parseMilestones(ctx, projectID, []milestone.Entity{data})
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to get milestone")
+		return
+	}
+
+	res = parsed[0]
+
+	return
+}
+
+func (s *Service) CreateMilestone(ctx context.Context, projectID string, req milestone.Request) (id string, err error) {
+	logger := log.LoggerFromContext(ctx)
+
+	p, err := s.projectRepository.Get(ctx, projectID)
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to create milestone")
+		return
+	}
+
+	if _, err = s.authorizeProjectEdit(ctx, p); err != nil {
+		logger.Err(err).Stack().Msg("failed to create milestone")
+		return
+	}
+
+	data := milestone.Entity{
+		ID:          domain.GenerateID(),
+		ProjectID:   projectID,
+		Title:       req.Title,
+		Description: req.Description,
+		DueDate:     domain.OnlyDate(req.DueDate),
+	}
+
+	err = s.withinTx(ctx, func(ctx context.Context) (err error) {
+		if id, err = s.milestoneRepository.Create(ctx, data); err != nil {
+			return
+		}
+
+		return s.recordChanges(ctx, audit.EntityProject, projectID, audit.ActionUpdate, audit.Changes{"milestone": {New: milestone.ParseFromEntity(data)}})
+	})
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to create milestone")
+		return
+	}
+
+	return
+}
+
+func (s *Service) UpdateMilestone(ctx context.Context, projectID, id string, req milestone.UpdateRequest) (err error) {
+	logger := log.LoggerFromContext(ctx)
+
+	current, err := s.projectMilestone(ctx, projectID, id)
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to update milestone")
+		return
+	}
+
+	data := milestone.Entity{
+		Title:       req.Title,
+		Description: req.Description,
+		DueDate:     domain.OnlyDate(req.DueDate),
+	}
+
+	err = s.withinTx(ctx, func(ctx context.Context) (err error) {
+		if err = s.milestoneRepository.Update(ctx, id, data); err != nil {
+			return
+		}
+
+		updated, err := s.milestoneRepository.Get(ctx, id)
+		if err != nil || updated == current {
+			return
+		}
+
+		return s.recordChanges(ctx, audit.EntityProject, projectID, audit.ActionUpdate, audit.Changes{"milestone": {Old: milestone.ParseFromEntity(current), New: milestone.ParseFromEntity(updated)}})
+	})
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to update milestone")
+		return
+	}
+
+	return
+}
+
+// DeleteMilestone removes the milestone, its tasks stay in the project.
+func (s *Service) DeleteMilestone(ctx context.Context, projectID, id string) (err error) {
+	logger := log.LoggerFromContext(ctx)
+
+	current, err := s.projectMilestone(ctx, projectID, id)
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to delete milestone")
+		return
+	}
+
+	err = s.withinTx(ctx, func(ctx context.Context) (err error) {
+		if err = s.milestoneRepository.Delete(ctx, id); err != nil {
+			return
+		}
+
+		return s.recordChanges(ctx, audit.EntityProject, projectID, audit.ActionUpdate, audit.Changes{"milestone": {Old: milestone.ParseFromEntity(current)}})
+	})
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to delete milestone")
+		return
+	}
+
+	return
+}
+
+// LinkTask puts the task on a milestone of its project, an empty milestone
+// unlinks it.
+func (s *Service) LinkTask(ctx context.Context, taskID string, req milestone.LinkRequest) (err error) {
+	logger := log.LoggerFromContext(ctx)
+
+	current, err := s.taskRepository.Get(ctx, taskID)
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to link task")
+		return
+	}
+
+	actor, err := s.authorizeTaskEdit(ctx, current.ProjectID)
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to link task")
+		return
+	}
+
+	if req.MilestoneID != "" {
+		if err = s.requireMilestone(ctx, current.ProjectID, req.MilestoneID); err != nil {
+			logger.Err(err).Stack().Msg("failed to link task")
+			return
+		}
+	}
+
+	var events []task.Event
+	if e, ok := task.MilestoneEvent(current, req.MilestoneID, actor.UserID(), time.Now().UTC()); ok {
+		events = append(events, e)
+	}
+
+	updated := current
+	updated.MilestoneID = req.MilestoneID
+
+	err = s.withinTx(ctx, func(ctx context.Context) (err error) {
+		if err = s.milestoneRepository.Link(ctx, taskID, req.MilestoneID, events...); err != nil {
+			return
+		}
+
+		return s.record(ctx, audit.EntityTask, taskID, audit.ActionUpdate, task.ParseFromEntity(current), task.ParseFromEntity(updated))
+	})
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to link task")
+		return
+	}
+
+	return
+}
+
+// requireMilestone checks that the milestone belongs to the project.
+func (s *Service) requireMilestone(ctx context.Context, projectID, id string) (err error) {
+	m, err := s.milestoneRepository.Get(ctx, id)
+	if err != nil {
+		return
+	}
+
+	if m.ProjectID != projectID {
+		err = milestone.ErrOtherProject
+	}
+
+	return
+}
+
+// projectMilestone returns the milestone of the project after checking that
+// the authenticated user may change the project.
+func (s *Service) projectMilestone(ctx context.Context, projectID, id string) (m milestone.Entity, err error) {
+	p, err := s.projectRepository.Get(ctx, projectID)
+	if err != nil {
+		return
+	}
+
+	if _, err = s.authorizeProjectEdit(ctx, p); err != nil {
+		return
+	}
+
+	m, err = s.milestoneRepository.Get(ctx, id)
+	if err != nil {
+		return
+	}
+
+	if m.ProjectID != projectID {
+		err = milestone.ErrNotFound
+	}
+
+	return
+}
+
+// parseMilestones adds the progress of the linked tasks to the milestones of
+// the project, a task counts as done once it is in a terminal status of the
+// project workflow.
+func (s *Service) parseMilestones(ctx context.Context, projectID string, data []milestone.Entity) (res []milestone.Response, err error) {
+	res = make([]milestone.Response, 0, len(data))
+	if len(data) == 0 {
+		return
+	}
+
+	ids := make([]string, len(data))
+	for i, m := range data {
+		ids[i] = m.ID
+	}
+
+	w, err := s.projectWorkflow(ctx, projectID)
+	if err != nil {
+		return
+	}
+
+	tasks, err := s.allTasks(ctx, task.Filter{}.With(
+		task.Equals(task.FieldProjectID, projectID),
+		task.Condition{Field: task.FieldMilestoneID, Operator: task.OpIn, Values: ids},
+	))
+	if err != nil {
+		return
+	}
+
+	total, done := map[string]int{}, map[string]int{}
+	for _, t := range tasks {
+		total[t.MilestoneID]++
+		if w.IsTerminal(t.Status) {
+			done[t.MilestoneID]++
+		}
+	}
+
+	now := time.Now().UTC()
+	for _, m := range data {
+		progress := milestone.Evaluate(m.DueDate, total[m.ID], done[m.ID], now)
+
+		r := milestone.ParseFromEntity(m)
+		r.Progress = &progress
+		res = append(res, r)
+	}
+
+	return
+}
@@ -0,0 +1,162 @@
+package management
+
+import (
+	"context"
+	"errors"
+	"project-management/internal/domain/auth"
+	"project-management/internal/domain/comment"
+	"project-management/internal/domain/project"
+	"project-management/internal/domain/user"
+	"project-management/pkg/token"
+)
+
+// The rules below are checked by every mutating service method before it
+// touches a repository. Reading is allowed to any authenticated user.
+
+// authorizeUserManagement allows only admins to create, change and delete users.
+func (s *Service) authorizeUserManagement(ctx context.Context) (actor token.Claims, err error) {
+	actor, err = s.actor(ctx)
+	if err != nil {
+		return
+	}
+
+	if actor.Role != user.RoleAdmin {
+		err = auth.ErrForbidden
+	}
+
+	return
+}
+
+// authorizeProjectCreation allows admins to create projects for anyone and
+// managers to create projects they manage themselves.
+func (s *Service) authorizeProjectCreation(ctx context.Context, managerID string) (actor token.Claims, err error) {
+	actor, err = s.actor(ctx)
+	if err != nil {
+		return
+	}
+
+	switch {
+	case actor.Role == user.RoleAdmin:
+	case actor.Role == user.RoleManager && (managerID == "" || managerID == actor.UserID()):
+	default:
+		err = auth.ErrForbidden
+	}
+
+	return
+}
+
+// authorizeProjectEdit allows admins and the manager of the project to change
+// or delete it.
+func (s *Service) authorizeProjectEdit(ctx context.Context, p project.Entity) (actor token.Claims, err error) {
+	actor, err = s.actor(ctx)
+	if err != nil {
+		return
+	}
+
+	if actor.Role != user.RoleAdmin && p.ManagerID != actor.UserID() {
+		err = auth.ErrForbidden
+	}
+
+	return
+}
+
+// authorizeTaskEdit allows admins to change any task and everybody else only
+// tasks in projects they belong to.
+func (s *Service) authorizeTaskEdit(ctx context.Context, projectID string) (actor token.Claims, err error) {
+	actor, err = s.actor(ctx)
+	if err != nil {
+		return
+	}
+
+	if actor.Role == user.RoleAdmin {
+		return
+	}
+
+	ok, err := s.belongsTo(ctx, actor.UserID(), projectID)
+	if err != nil {
+		return
+	}
+
+	if !ok {
+		err = auth.ErrForbidden
+	}
+
+	return
+}
+
+// belongsTo reports whether the user is a member of the project with a role
+// that may change its tasks.
+func (s *Service) belongsTo(ctx context.Context, userID, projectID string) (bool, error) {
+	m, err := s.memberRepository.GetMember(ctx, projectID, userID)
+	if err != nil {
+		if errors.Is(err, project.ErrMemberNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return m.CanEditTasks(), nil
+}
+
+// authorizeComment allows admins and every member of the project, viewers
+// included, to comment on its tasks.
+func (s *Service) authorizeComment(ctx context.Context, projectID string) (actor token.Claims, err error) {
+	actor, err = s.actor(ctx)
+	if err != nil {
+		return
+	}
+
+	if actor.Role == user.RoleAdmin {
+		return
+	}
+
+	if _, err = s.memberRepository.GetMember(ctx, projectID, actor.UserID()); err != nil {
+		if errors.Is(err, project.ErrMemberNotFound) {
+			err = auth.ErrForbidden
+		}
+	}
+
+	return
+}
+
+// authorizeCommentEdit allows only the author to change or delete a comment.
+func (s *Service) authorizeCommentEdit(ctx context.Context, c comment.Entity) (actor token.Claims, err error) {
+	actor, err = s.actor(ctx)
+	if err != nil {
+		return
+	}
+
+	if c.AuthorID != actor.UserID() {
+		err = comment.ErrNotAuthor
+	}
+
+	return
+}
+
+// authorizeAudit allows only admins to read the audit log.
+func (s *Service) authorizeAudit(ctx context.Context) (actor token.Claims, err error) {
+	actor, err = s.actor(ctx)
+	if err != nil {
+		return
+	}
+
+	if actor.Role != user.RoleAdmin {
+		err = auth.ErrForbidden
+	}
+
+	return
+}
+
+// authorizeTrash allows only admins to read soft deleted rows.
+func (s *Service) authorizeTrash(ctx context.Context) (actor token.Claims, err error) {
+	actor, err = s.actor(ctx)
+	if err != nil {
+		return
+	}
+
+	if actor.Role != user.RoleAdmin {
+		err = auth.ErrForbidden
+	}
+
+	return
+}
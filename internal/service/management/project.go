@@ -3,6 +3,7 @@ package management
 import (
 	"context"
 	"project-management/internal/domain"
+	"project-management/internal/domain/audit"
 	"project-management/internal/domain/project"
 	"project-management/pkg/log"
 )
@@ -10,6 +11,12 @@ import (
 func (s *Service) CreateProject(ctx context.Context, req project.Request) (id string, err error) {
 	logger := log.LoggerFromContext(ctx)
 
+	actor, err := s.authorizeProjectCreation(ctx, req.ManagerID)
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to create project")
+		return
+	}
+
 	data := project.Entity{
 		ID:          domain.GenerateID(),
 		Title:       req.Title,
@@ -19,7 +26,24 @@ func (s *Service) CreateProject(ctx context.Context, req project.Request) (id st
 		FinishedAt:  domain.OnlyDate(req.FinishedAt),
 	}
 
-	id, err = s.projectRepository.Create(ctx, data)
+	// the authenticated user manages the project unless the request names someone else
+	if data.ManagerID == "" {
+		data.ManagerID = actor.UserID()
+	}
+
+	err = s.withinTx(ctx, func(ctx context.Context) (err error) {
+		if id, err = s.projectRepository.Create(ctx, data); err != nil {
+			return
+		}
+
+		if data.ManagerID != "" {
+			if err = s.ensureManagerMembership(ctx, id, data.ManagerID); err != nil {
+				return
+			}
+		}
+
+		return s.record(ctx, audit.EntityProject, id, audit.ActionCreate, nil, project.ParseFromEntity(data))
+	})
 	if err != nil {
 		logger.Err(err).Stack().Msg("failed to create project")
 		return
@@ -42,17 +66,63 @@ func (s *Service) GetProject(ctx context.Context, id string) (p project.Response
 	return
 }
 
-func (s *Service) UpdateProject(ctx context.Context, id string, req project.UpdateRequest) (err error) {
+// UpdateProject applies the request to the given version of the project,
+// zero applies it to whatever version is current.
+func (s *Service) UpdateProject(ctx context.Context, id string, version int64, req project.UpdateRequest) (err error) {
+	return s.updateProject(ctx, id, version, req, nil)
+}
+
+// PatchProject applies a merge patch to the given version of the project,
+// unlike UpdateProject it can clear the optional fields.
+func (s *Service) PatchProject(ctx context.Context, id string, version int64, req project.PatchRequest) (err error) {
+	return s.updateProject(ctx, id, version, req.Update(), req.Clear())
+}
+
+func (s *Service) updateProject(ctx context.Context, id string, version int64, req project.UpdateRequest, clear []project.Field) (err error) {
 	logger := log.LoggerFromContext(ctx)
 
+	current, err := s.projectRepository.Get(ctx, id)
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to update project")
+		return
+	}
+
+	if err = requireVersion(current.Version, version); err != nil {
+		logger.Err(err).Stack().Msg("failed to update project")
+		return
+	}
+
+	if _, err = s.authorizeProjectEdit(ctx, current); err != nil {
+		logger.Err(err).Stack().Msg("failed to update project")
+		return
+	}
+
 	data := project.Entity{
 		Title:       req.Title,
 		Description: req.Description,
 		ManagerID:   req.ManagerID,
 		FinishedAt:  domain.OnlyDate(req.FinishedAt),
+		Version:     current.Version,
 	}
 
-	err = s.projectRepository.Update(ctx, id, data)
+	err = s.withinTx(ctx, func(ctx context.Context) (err error) {
+		if err = s.projectRepository.Patch(ctx, id, data, clear); err != nil {
+			return
+		}
+
+		if data.ManagerID != "" {
+			if err = s.ensureManagerMembership(ctx, id, data.ManagerID); err != nil {
+				return
+			}
+		}
+
+		updated, err := s.projectRepository.Get(ctx, id)
+		if err != nil {
+			return
+		}
+
+		return s.record(ctx, audit.EntityProject, id, audit.ActionUpdate, project.ParseFromEntity(current), project.ParseFromEntity(updated))
+	})
 	if err != nil {
 		logger.Err(err).Stack().Msg("failed to update project")
 		return
@@ -61,10 +131,32 @@ func (s *Service) UpdateProject(ctx context.Context, id string, req project.Upda
 	return
 }
 
-func (s *Service) DeleteProject(ctx context.Context, id string) (err error) {
+func (s *Service) DeleteProject(ctx context.Context, id string, version int64) (err error) {
 	logger := log.LoggerFromContext(ctx)
 
-	err = s.projectRepository.Delete(ctx, id)
+	current, err := s.projectRepository.Get(ctx, id)
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to delete project")
+		return
+	}
+
+	if err = requireVersion(current.Version, version); err != nil {
+		logger.Err(err).Stack().Msg("failed to delete project")
+		return
+	}
+
+	if _, err = s.authorizeProjectEdit(ctx, current); err != nil {
+		logger.Err(err).Stack().Msg("failed to delete project")
+		return
+	}
+
+	err = s.withinTx(ctx, func(ctx context.Context) (err error) {
+		if err = s.projectRepository.Delete(ctx, id, current.Version); err != nil {
+			return
+		}
+
+		return s.record(ctx, audit.EntityProject, id, audit.ActionDelete, project.ParseFromEntity(current), nil)
+	})
 	if err != nil {
 		logger.Err(err).Stack().Msg("failed to delete project")
 		return
@@ -73,21 +165,21 @@ func (s *Service) DeleteProject(ctx context.Context, id string) (err error) {
 	return
 }
 
-func (s *Service) ListProjects(ctx context.Context) (res []project.Response, err error) {
+func (s *Service) ListProjects(ctx context.Context, page domain.PageRequest) (res domain.Page[project.Response], err error) {
 	logger := log.LoggerFromContext(ctx)
 
-	data, err := s.projectRepository.List(ctx)
+	data, err := s.projectRepository.List(ctx, page)
 	if err != nil {
 		logger.Err(err).Stack().Msg("failed to list projects")
-		return nil, project.ErrNotFound
+		return res, project.ErrNotFound
 	}
 
-	res = project.ParseFromEntities(data)
+	res = domain.MapPage(data, project.ParseFromEntity)
 
 	return
 }
 
-func (s *Service) SearchProjects(ctx context.Context, filter, value string) (res []project.Response, err error) {
+func (s *Service) SearchProjects(ctx context.Context, filter, value string, page domain.PageRequest) (res domain.Page[project.Response], err error) {
 	logger := log.LoggerFromContext(ctx)
 
 	if value == "" || !project.IsValidFilter(filter) {
@@ -96,13 +188,13 @@ func (s *Service) SearchProjects(ctx context.Context, filter, value string) (res
 		return
 	}
 
-	data, err := s.projectRepository.Search(ctx, filter, value)
+	data, err := s.projectRepository.Search(ctx, filter, value, page)
 	if err != nil {
 		logger.Err(err).Stack().Msg("failed to search projects")
 		return
 	}
 
-	res = project.ParseFromEntities(data)
+	res = domain.MapPage(data, project.ParseFromEntity)
 
 	return
 }
@@ -0,0 +1,89 @@
+package management
+
+import (
+	"context"
+	"project-management/internal/domain/search"
+	"project-management/pkg/log"
+	"sort"
+)
+
+// Search runs a full-text search over every requested entity type and merges
+// the hits into a single list ordered by rank.
+func (s *Service) Search(ctx context.Context, req search.Request) (res search.Response, err error) {
+	logger := log.LoggerFromContext(ctx)
+
+	res = search.Response{
+		Query:   req.Query,
+		Counts:  map[string]int{},
+		Results: []search.Result{},
+	}
+
+	if req.Includes(search.TypeTask) {
+		tasks, err := s.taskRepository.FullTextSearch(ctx, req.Query, req.Limit)
+		if err != nil {
+			logger.Err(err).Stack().Msg("failed to search tasks")
+			return res, err
+		}
+
+		for _, t := range tasks {
+			res.Results = append(res.Results, search.Result{
+				Type:        search.TypeTask,
+				ID:          t.Entity.ID,
+				Title:       t.Entity.Title,
+				Description: t.Entity.Description,
+				Rank:        t.Rank,
+			})
+		}
+	}
+
+	if req.Includes(search.TypeProject) {
+		projects, err := s.projectRepository.FullTextSearch(ctx, req.Query, req.Limit)
+		if err != nil {
+			logger.Err(err).Stack().Msg("failed to search projects")
+			return res, err
+		}
+
+		for _, p := range projects {
+			res.Results = append(res.Results, search.Result{
+				Type:        search.TypeProject,
+				ID:          p.Entity.ID,
+				Title:       p.Entity.Title,
+				Description: p.Entity.Description,
+				Rank:        p.Rank,
+			})
+		}
+	}
+
+	if req.Includes(search.TypeUser) {
+		users, err := s.userRepostitory.FullTextSearch(ctx, req.Query, req.Limit)
+		if err != nil {
+			logger.Err(err).Stack().Msg("failed to search users")
+			return res, err
+		}
+
+		for _, u := range users {
+			res.Results = append(res.Results, search.Result{
+				Type:        search.TypeUser,
+				ID:          u.Entity.ID,
+				Title:       u.Entity.Name,
+				Description: u.Entity.Email,
+				Rank:        u.Rank,
+			})
+		}
+	}
+
+	sort.SliceStable(res.Results, func(i, j int) bool {
+		return res.Results[i].Rank > res.Results[j].Rank
+	})
+
+	if len(res.Results) > req.Limit {
+		res.Results = res.Results[:req.Limit]
+	}
+
+	for _, r := range res.Results {
+		res.Counts[r.Type]++
+	}
+	res.Total = len(res.Results)
+
+	return
+}
@@ -1,15 +1,39 @@
 package management
 
 import (
+	"project-management/internal/domain"
+	"project-management/internal/domain/attachment"
+	"project-management/internal/domain/audit"
+	"project-management/internal/domain/comment"
+	"project-management/internal/domain/label"
+	"project-management/internal/domain/milestone"
 	"project-management/internal/domain/project"
+	"project-management/internal/domain/sprint"
 	"project-management/internal/domain/task"
 	"project-management/internal/domain/user"
+	"project-management/pkg/token"
 )
 
 type Service struct {
-	userRepostitory   user.Repository
-	taskRepository    task.Repository
-	projectRepository project.Repository
+	userRepostitory      user.Repository
+	taskRepository       task.Repository
+	projectRepository    project.Repository
+	memberRepository     project.MemberRepository
+	workflowRepository   task.WorkflowRepository
+	dependencyRepository task.DependencyRepository
+	auditRepository      audit.Repository
+	commentRepository    comment.Repository
+	labelRepository      label.Repository
+	sprintRepository     sprint.Repository
+	milestoneRepository  milestone.Repository
+
+	txManager domain.TxManager
+
+	attachmentRepository attachment.Repository
+	blobStore            attachment.BlobStore
+	attachmentLimits     attachment.Limits
+
+	tokenManager *token.Manager
 }
 
 type Configuration func(s *Service) error
@@ -44,3 +68,88 @@ func WithProjectRepository(projectRepository project.Repository) Configuration {
 		return nil
 	}
 }
+
+func WithProjectMemberRepository(memberRepository project.MemberRepository) Configuration {
+	return func(s *Service) error {
+		s.memberRepository = memberRepository
+		return nil
+	}
+}
+
+func WithTaskWorkflowRepository(workflowRepository task.WorkflowRepository) Configuration {
+	return func(s *Service) error {
+		s.workflowRepository = workflowRepository
+		return nil
+	}
+}
+
+func WithTaskDependencyRepository(dependencyRepository task.DependencyRepository) Configuration {
+	return func(s *Service) error {
+		s.dependencyRepository = dependencyRepository
+		return nil
+	}
+}
+
+func WithAuditRepository(auditRepository audit.Repository) Configuration {
+	return func(s *Service) error {
+		s.auditRepository = auditRepository
+		return nil
+	}
+}
+
+func WithCommentRepository(commentRepository comment.Repository) Configuration {
+	return func(s *Service) error {
+		s.commentRepository = commentRepository
+		return nil
+	}
+}
+
+func WithLabelRepository(labelRepository label.Repository) Configuration {
+	return func(s *Service) error {
+		s.labelRepository = labelRepository
+		return nil
+	}
+}
+
+func WithSprintRepository(sprintRepository sprint.Repository) Configuration {
+	return func(s *Service) error {
+		s.sprintRepository = sprintRepository
+		return nil
+	}
+}
+
+func WithMilestoneRepository(milestoneRepository milestone.Repository) Configuration {
+	return func(s *Service) error {
+		s.milestoneRepository = milestoneRepository
+		return nil
+	}
+}
+
+func WithTxManager(txManager domain.TxManager) Configuration {
+	return func(s *Service) error {
+		s.txManager = txManager
+		return nil
+	}
+}
+
+func WithAttachmentRepository(attachmentRepository attachment.Repository) Configuration {
+	return func(s *Service) error {
+		s.attachmentRepository = attachmentRepository
+		return nil
+	}
+}
+
+func WithBlobStore(blobStore attachment.BlobStore, limits attachment.Limits) Configuration {
+	return func(s *Service) error {
+		s.blobStore = blobStore
+		s.attachmentLimits = limits
+		return nil
+	}
+}
+
+func WithTokenManager(tokenManager *token.Manager) Configuration {
+	return func(s *Service) error {
+		s.tokenManager = tokenManager
+		return nil
+	}
+}
@@ -0,0 +1,114 @@
+package management
+
+import (
+	"context"
+	"testing"
+
+	"project-management/internal/domain"
+	"project-management/internal/domain/project"
+	"project-management/internal/domain/task"
+	"project-management/internal/domain/user"
+	"project-management/internal/repository/memory"
+	"project-management/pkg/token"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// fixture is a service on the memory store along with a context acting as an
+// admin.
+type fixture struct {
+	t   *testing.T
+	s   *Service
+	ctx context.Context
+}
+
+func newFixture(t *testing.T) *fixture {
+	db := memory.New()
+
+	s := New(
+		WithProjectRepository(memory.NewProjectRepository(db)),
+		WithTaskRepository(memory.NewTaskRepository(db)),
+		WithUserRepository(memory.NewUserRepository(db)),
+		WithProjectMemberRepository(memory.NewProjectMemberRepository(db)),
+		WithTaskWorkflowRepository(memory.NewTaskWorkflowRepository(db)),
+		WithTaskDependencyRepository(memory.NewTaskDependencyRepository(db)),
+		WithAuditRepository(memory.NewAuditRepository(db)),
+		WithCommentRepository(memory.NewCommentRepository(db)),
+		WithLabelRepository(memory.NewLabelRepository(db)),
+		WithSprintRepository(memory.NewSprintRepository(db)),
+		WithMilestoneRepository(memory.NewMilestoneRepository(db)),
+		WithTxManager(memory.NewTxManager(db)),
+	)
+
+	f := &fixture{t: t, s: s}
+	admin := f.user(user.RoleAdmin)
+	f.ctx = token.WithClaims(context.Background(), token.Claims{
+		Role:             user.RoleAdmin,
+		Kind:             token.KindAccess,
+		RegisteredClaims: jwt.RegisteredClaims{Subject: admin},
+	})
+
+	return f
+}
+
+func (f *fixture) user(role string) string {
+	id := domain.GenerateID()
+
+	_, err := f.s.userRepostitory.Create(context.Background(), user.Entity{
+		ID:               id,
+		Name:             id,
+		Email:            id + "@example.com",
+		RegistrationDate: "2024-01-01",
+		Role:             role,
+	})
+	if err != nil {
+		f.t.Fatal(err)
+	}
+
+	return id
+}
+
+// project creates a project managed by managerID, the members join it as
+// developers.
+func (f *fixture) project(managerID string, members ...string) string {
+	id := domain.GenerateID()
+
+	_, err := f.s.projectRepository.Create(f.ctx, project.Entity{ID: id, Title: id, StartedAt: "2024-01-01", ManagerID: managerID})
+	if err != nil {
+		f.t.Fatal(err)
+	}
+
+	if err = f.s.memberRepository.AddMember(f.ctx, project.Member{ProjectID: id, UserID: managerID, Role: project.MemberRoleManager}); err != nil {
+		f.t.Fatal(err)
+	}
+	for _, m := range members {
+		if err = f.s.memberRepository.AddMember(f.ctx, project.Member{ProjectID: id, UserID: m, Role: project.MemberRoleDeveloper}); err != nil {
+			f.t.Fatal(err)
+		}
+	}
+
+	return id
+}
+
+func (f *fixture) task(projectID, authorID string) string {
+	id, err := f.s.CreateTask(f.ctx, task.Request{
+		Title:     "task",
+		Priority:  "low",
+		ProjectID: projectID,
+		AuthorID:  authorID,
+	})
+	if err != nil {
+		f.t.Fatal(err)
+	}
+
+	return id
+}
+
+func (f *fixture) get(id string) task.Entity {
+	t, err := f.s.taskRepository.Get(f.ctx, id)
+	if err != nil {
+		f.t.Fatal(err)
+	}
+
+	return t
+}
@@ -0,0 +1,439 @@
+package management
+
+import (
+	"context"
+	"project-management/internal/domain"
+	"project-management/internal/domain/audit"
+	"project-management/internal/domain/sprint"
+	"project-management/internal/domain/task"
+	"project-management/pkg/log"
+	"time"
+)
+
+func (s *Service) ListSprints(ctx context.Context, projectID string) (res []sprint.Response, err error) {
+	logger := log.LoggerFromContext(ctx)
+
+	if _, err = s.projectRepository.Get(ctx, projectID); err != nil {
+		logger.Err(err).Stack().Msg("failed to list sprints")
+		return
+	}
+
+	data, err := s.sprintRepository.List(ctx, projectID)
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to list sprints")
+		return
+	}
+
+	res = sprint.ParseFromEntities(data)
+
+	return
+}
+
+func (s *Service) GetSprint(ctx context.Context, projectID, id string) (res sprint.Response, err error) {
+	logger := log.LoggerFromContext(ctx)
+
+	if _, err = s.projectRepository.Get(ctx, projectID); err != nil {
+		logger.Err(err).Stack().Msg("failed to get sprint")
+		return
+	}
+
+	data, err := s.sprintRepository.Get(ctx, id)
+	if err == nil && data.ProjectID != projectID {
+		err = sprint.ErrNotFound
+	}
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to get sprint")
+		return
+	}
+
+	res = sprint.ParseFromEntity(data)
+
+	return
+}
+
+// CreateSprint plans a new sprint of the project.
+func (s *Service) CreateSprint(ctx context.Context, projectID string, req sprint.Request) (id string, err error) {
+	logger := log.LoggerFromContext(ctx)
+
+	p, err := s.projectRepository.Get(ctx, projectID)
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to create sprint")
+		return
+	}
+
+	if _, err = s.authorizeProjectEdit(ctx, p); err != nil {
+		logger.Err(err).Stack().Msg("failed to create sprint")
+		return
+	}
+
+	data := sprint.Entity{
+		ID:        domain.GenerateID(),
+		ProjectID: projectID,
+		Name:      req.Name,
+		Goal:      req.Goal,
+		StartAt:   domain.OnlyDate(req.StartAt),
+		EndAt:     domain.OnlyDate(req.EndAt),
+		State:     sprint.StatePlanned,
+	}
+
+	err = s.withinTx(ctx, func(ctx context.Context) (err error) {
+		if id, err = s.sprintRepository.Create(ctx, data); err != nil {
+			return
+		}
+
+		return s.recordChanges(ctx, audit.EntityProject, projectID, audit.ActionUpdate, audit.Changes{"sprint": {New: sprint.ParseFromEntity(data)}})
+	})
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to create sprint")
+		return
+	}
+
+	return
+}
+
+func (s *Service) UpdateSprint(ctx context.Context, projectID, id string, req sprint.UpdateRequest) (err error) {
+	logger := log.LoggerFromContext(ctx)
+
+	current, err := s.projectSprint(ctx, projectID, id)
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to update sprint")
+		return
+	}
+
+	if current.State == sprint.StateClosed {
+		err = sprint.ErrClosed
+		logger.Err(err).Stack().Msg("failed to update sprint")
+		return
+	}
+
+	data := sprint.Entity{
+		Name:    req.Name,
+		Goal:    req.Goal,
+		StartAt: domain.OnlyDate(req.StartAt),
+		EndAt:   domain.OnlyDate(req.EndAt),
+	}
+
+	// the dates are validated one by one, together they must stay in order
+	start, end := current.StartAt, current.EndAt
+	if data.StartAt != "" {
+		start = data.StartAt
+	}
+	if data.EndAt != "" {
+		end = data.EndAt
+	}
+	if end < start {
+		err = sprint.ErrInvalidDates
+		logger.Err(err).Stack().Msg("failed to update sprint")
+		return
+	}
+
+	err = s.withinTx(ctx, func(ctx context.Context) (err error) {
+		if err = s.sprintRepository.Update(ctx, id, data); err != nil {
+			return
+		}
+
+		return s.recordSprint(ctx, current)
+	})
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to update sprint")
+		return
+	}
+
+	return
+}
+
+// DeleteSprint removes the sprint, its tasks go back to the backlog.
+func (s *Service) DeleteSprint(ctx context.Context, projectID, id string) (err error) {
+	logger := log.LoggerFromContext(ctx)
+
+	current, err := s.projectSprint(ctx, projectID, id)
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to delete sprint")
+		return
+	}
+
+	err = s.withinTx(ctx, func(ctx context.Context) (err error) {
+		if err = s.sprintRepository.Delete(ctx, id); err != nil {
+			return
+		}
+
+		return s.recordChanges(ctx, audit.EntityProject, projectID, audit.ActionUpdate, audit.Changes{"sprint": {Old: sprint.ParseFromEntity(current)}})
+	})
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to delete sprint")
+		return
+	}
+
+	return
+}
+
+// StartSprint activates a planned sprint, the project must not have another
+// active sprint.
+func (s *Service) StartSprint(ctx context.Context, projectID, id string) (err error) {
+	logger := log.LoggerFromContext(ctx)
+
+	current, err := s.projectSprint(ctx, projectID, id)
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to start sprint")
+		return
+	}
+
+	if current.State != sprint.StatePlanned {
+		err = sprint.ErrNotPlanned
+		logger.Err(err).Stack().Msg("failed to start sprint")
+		return
+	}
+
+	err = s.withinTx(ctx, func(ctx context.Context) (err error) {
+		if err = s.sprintRepository.Update(ctx, id, sprint.Entity{State: sprint.StateActive}); err != nil {
+			return
+		}
+
+		return s.recordSprint(ctx, current)
+	})
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to start sprint")
+		return
+	}
+
+	return
+}
+
+// CloseSprint closes the active sprint. Its unfinished tasks, those not in a
+// terminal status of the project workflow, move to the planned sprint named
+// by the request or back to the backlog.
+func (s *Service) CloseSprint(ctx context.Context, projectID, id string, req sprint.CloseRequest) (res sprint.CloseResponse, err error) {
+	logger := log.LoggerFromContext(ctx)
+
+	if _, err = s.projectSprint(ctx, projectID, id); err != nil {
+		logger.Err(err).Stack().Msg("failed to close sprint")
+		return
+	}
+
+	actor, err := s.actor(ctx)
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to close sprint")
+		return
+	}
+
+	// with the sprint locked no task joins it, with its tasks locked none of
+	// them changes its status before the open ones are moved
+	err = s.withinTx(ctx, func(ctx context.Context) (err error) {
+		if err = s.sprintRepository.Lock(ctx, id); err != nil {
+			return
+		}
+
+		current, err := s.sprintRepository.Get(ctx, id)
+		if err != nil {
+			return
+		}
+
+		if current.State != sprint.StateActive {
+			return sprint.ErrNotActive
+		}
+
+		// the next sprint is planned while only this one of the project is
+		// active, so no other closing unit of work locks the two the other
+		// way round
+		if req.NextSprintID != "" {
+			if err = s.requirePlannedSprint(ctx, projectID, req.NextSprintID); err != nil {
+				return
+			}
+		}
+
+		w, err := s.projectWorkflow(ctx, projectID)
+		if err != nil {
+			return
+		}
+
+		filter := task.Filter{}.With(task.Equals(task.FieldSprintID, id))
+
+		tasks, err := s.allTasks(ctx, filter)
+		if err != nil {
+			return
+		}
+
+		ids := make([]string, len(tasks))
+		for i, t := range tasks {
+			ids[i] = t.ID
+		}
+
+		if err = s.taskRepository.Lock(ctx, ids...); err != nil {
+			return
+		}
+
+		// read again, a task may have changed before it was locked
+		if tasks, err = s.allTasks(ctx, filter); err != nil {
+			return
+		}
+
+		res = sprint.CloseResponse{NextSprintID: req.NextSprintID, MovedTaskIDs: []string{}}
+
+		var events []task.Event
+		now := time.Now().UTC()
+		for _, t := range tasks {
+			if w.IsTerminal(t.Status) {
+				continue
+			}
+
+			res.MovedTaskIDs = append(res.MovedTaskIDs, t.ID)
+			if e, ok := task.ScheduleEvent(t, req.NextSprintID, actor.UserID(), now); ok {
+				events = append(events, e)
+			}
+		}
+
+		if err = s.sprintRepository.Close(ctx, id, req.NextSprintID, res.MovedTaskIDs, events...); err != nil {
+			return
+		}
+
+		return s.recordSprint(ctx, current)
+	})
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to close sprint")
+		return sprint.CloseResponse{}, err
+	}
+
+	return
+}
+
+// ScheduleTask puts the task into a sprint of its project that is not closed
+// yet, an empty sprint moves it back to the backlog.
+func (s *Service) ScheduleTask(ctx context.Context, taskID string, req sprint.ScheduleRequest) (err error) {
+	logger := log.LoggerFromContext(ctx)
+
+	current, err := s.taskRepository.Get(ctx, taskID)
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to schedule task")
+		return
+	}
+
+	actor, err := s.authorizeTaskEdit(ctx, current.ProjectID)
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to schedule task")
+		return
+	}
+
+	var events []task.Event
+	if e, ok := task.ScheduleEvent(current, req.SprintID, actor.UserID(), time.Now().UTC()); ok {
+		events = append(events, e)
+	}
+
+	updated := current
+	updated.SprintID = req.SprintID
+
+	err = s.withinTx(ctx, func(ctx context.Context) (err error) {
+		if req.SprintID != "" {
+			if err = s.requireOpenSprint(ctx, current.ProjectID, req.SprintID); err != nil {
+				return
+			}
+		}
+
+		if err = s.sprintRepository.Schedule(ctx, taskID, req.SprintID, events...); err != nil {
+			return
+		}
+
+		return s.record(ctx, audit.EntityTask, taskID, audit.ActionUpdate, task.ParseFromEntity(current), task.ParseFromEntity(updated))
+	})
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to schedule task")
+		return
+	}
+
+	return
+}
+
+// requireOpenSprint checks that the sprint belongs to the project and can
+// still take tasks. The sprint stays locked until the unit of work ctx runs in
+// ends, so it is not closed before the task has joined it.
+func (s *Service) requireOpenSprint(ctx context.Context, projectID, id string) (err error) {
+	if err = s.sprintRepository.Lock(ctx, id); err != nil {
+		return
+	}
+
+	sp, err := s.sprintRepository.Get(ctx, id)
+	if err != nil {
+		return
+	}
+
+	switch {
+	case sp.ProjectID != projectID:
+		err = sprint.ErrOtherProject
+	case sp.State == sprint.StateClosed:
+		err = sprint.ErrClosed
+	}
+
+	return
+}
+
+// requirePlannedSprint checks that the sprint is a planned one of the project
+// and locks it like requireOpenSprint.
+func (s *Service) requirePlannedSprint(ctx context.Context, projectID, id string) (err error) {
+	if err = s.sprintRepository.Lock(ctx, id); err != nil {
+		return sprint.ErrInvalidTarget
+	}
+
+	next, err := s.sprintRepository.Get(ctx, id)
+	if err != nil || next.ProjectID != projectID || next.State != sprint.StatePlanned {
+		return sprint.ErrInvalidTarget
+	}
+
+	return
+}
+
+// projectSprint returns the sprint of the project after checking that the
+// authenticated user may change the project.
+func (s *Service) projectSprint(ctx context.Context, projectID, id string) (sp sprint.Entity, err error) {
+	p, err := s.projectRepository.Get(ctx, projectID)
+	if err != nil {
+		return
+	}
+
+	if _, err = s.authorizeProjectEdit(ctx, p); err != nil {
+		return
+	}
+
+	sp, err = s.sprintRepository.Get(ctx, id)
+	if err != nil {
+		return
+	}
+
+	if sp.ProjectID != projectID {
+		err = sprint.ErrNotFound
+	}
+
+	return
+}
+
+// recordSprint writes the change of the sprint to the audit log of its
+// project.
+func (s *Service) recordSprint(ctx context.Context, current sprint.Entity) error {
+	updated, err := s.sprintRepository.Get(ctx, current.ID)
+	if err != nil {
+		return err
+	}
+
+	if updated == current {
+		return nil
+	}
+
+	return s.recordChanges(ctx, audit.EntityProject, current.ProjectID, audit.ActionUpdate, audit.Changes{"sprint": {Old: sprint.ParseFromEntity(current), New: sprint.ParseFromEntity(updated)}})
+}
+
+// allTasks returns every task matching the filter, page by page.
+func (s *Service) allTasks(ctx context.Context, filter task.Filter) (tasks []task.Entity, err error) {
+	page := domain.PageRequest{Limit: domain.MaxPageLimit}
+
+	for {
+		data, err := s.taskRepository.List(ctx, filter, page)
+		if err != nil {
+			return nil, err
+		}
+
+		tasks = append(tasks, data.Items...)
+		if data.NextCursor == "" {
+			return tasks, nil
+		}
+
+		page.Cursor = data.NextCursor
+	}
+}
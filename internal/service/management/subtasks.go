@@ -0,0 +1,114 @@
+package management
+
+import (
+	"context"
+	"errors"
+	"project-management/internal/domain"
+	"project-management/internal/domain/task"
+	"project-management/pkg/log"
+	"slices"
+)
+
+// GetSubtasks returns the task with all of its subtasks nested below it and
+// the completion rolled up from the leaves.
+func (s *Service) GetSubtasks(ctx context.Context, id string) (res task.TreeResponse, err error) {
+	logger := log.LoggerFromContext(ctx)
+
+	root, err := s.taskRepository.Get(ctx, id)
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to get subtasks")
+		return
+	}
+
+	descendants, err := s.taskRepository.Subtasks(ctx, id)
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to get subtasks")
+		return
+	}
+
+	// subtasks share the project of their root, so one workflow decides
+	w, err := s.projectWorkflow(ctx, root.ProjectID)
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to get subtasks")
+		return
+	}
+
+	res = task.ParseTree(root, descendants, func(t task.Entity) bool {
+		return w.IsTerminal(t.Status)
+	})
+
+	return
+}
+
+// requireParent checks that parentID can be the parent of the task id in the
+// project projectID. The parent has to live in the same project and must not
+// be the task itself or one of its subtasks. id is empty for new tasks.
+func (s *Service) requireParent(ctx context.Context, id, parentID, projectID string) (err error) {
+	parent, err := s.taskRepository.Get(ctx, parentID)
+	if errors.Is(err, task.ErrNotFound) {
+		return task.ErrParentNotFound
+	}
+	if err != nil {
+		return
+	}
+
+	if parent.ProjectID != projectID {
+		return task.ErrParentProject
+	}
+
+	if id == "" {
+		return
+	}
+
+	// walk up from the new parent, ancestors in the trash still count
+	ctx = domain.WithDeletedScope(ctx, domain.IncludeDeleted)
+	seen := map[string]bool{}
+
+	for current := parent; ; {
+		if current.ID == id {
+			return task.ErrParentCycle
+		}
+
+		if current.ParentID == "" || seen[current.ID] {
+			return
+		}
+		seen[current.ID] = true
+
+		if current, err = s.taskRepository.Get(ctx, current.ParentID); err != nil {
+			return
+		}
+	}
+}
+
+// moveTask checks the place of the task in the hierarchy after the update,
+// which may clear the parent. A task with subtasks stays in its project, and
+// a subtask only moves along with a new parent in the target project.
+func (s *Service) moveTask(ctx context.Context, current, data task.Entity, clear []task.Field) (err error) {
+	projectID, parentID := current.ProjectID, current.ParentID
+	if data.ProjectID != "" {
+		projectID = data.ProjectID
+	}
+	if data.ParentID != "" {
+		parentID = data.ParentID
+	}
+	if slices.Contains(clear, task.FieldParentID) {
+		parentID = ""
+	}
+
+	if projectID != current.ProjectID {
+		subtasks, err := s.taskRepository.Subtasks(ctx, current.ID)
+		if err != nil {
+			return err
+		}
+
+		if len(subtasks) > 0 {
+			return task.ErrSubtasksMoved
+		}
+	}
+
+	if parentID == "" || (parentID == current.ParentID && projectID == current.ProjectID) {
+		return
+	}
+
+	return s.requireParent(ctx, current.ID, parentID, projectID)
+}
@@ -3,31 +3,124 @@ package management
 import (
 	"context"
 	"project-management/internal/domain"
+	"project-management/internal/domain/audit"
 	"project-management/internal/domain/task"
 	"project-management/pkg/log"
+	"project-management/pkg/token"
+	"time"
 )
 
 func (s *Service) CreateTask(ctx context.Context, req task.Request) (id string, err error) {
 	logger := log.LoggerFromContext(ctx)
 
-	data := task.Entity{
+	err = s.withinTx(ctx, func(ctx context.Context) (err error) {
+		data, err := s.prepareTask(ctx, req)
+		if err != nil {
+			return
+		}
+
+		if id, err = s.taskRepository.Create(ctx, data); err != nil {
+			return
+		}
+
+		return s.record(ctx, audit.EntityTask, id, audit.ActionCreate, nil, task.ParseFromEntity(data))
+	})
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to create task")
+		return
+	}
+
+	return
+}
+
+// prepareTask checks a new task and fills in the defaults of its project.
+func (s *Service) prepareTask(ctx context.Context, req task.Request) (data task.Entity, err error) {
+	actor, err := s.authorizeTaskEdit(ctx, req.ProjectID)
+	if err != nil {
+		return
+	}
+
+	// tasks can not be added to a project in the trash
+	if _, err = s.projectRepository.Get(ctx, req.ProjectID); err != nil {
+		return
+	}
+
+	data = task.Entity{
 		ID:          domain.GenerateID(),
 		Title:       req.Title,
 		Description: req.Description,
 		Priority:    req.Priority,
 		Status:      req.Status,
 		CreatedAt:   domain.OnlyDate(req.CreatedAt),
-		DoneAt:      domain.OnlyDate(req.DoneAt),
+		DueDate:     domain.OnlyDate(req.DueDate),
 		AuthorID:    req.AuthorID,
+		AssigneeID:  req.AssigneeID,
 		ProjectID:   req.ProjectID,
+		ParentID:    req.ParentID,
+		SprintID:    req.SprintID,
+		MilestoneID: req.MilestoneID,
+
+		EstimateHours: req.EstimateHours,
 	}
 
-	id, err = s.taskRepository.Create(ctx, data)
+	if data.CreatedAt == "" {
+		data.CreatedAt = domain.OnlyDate(time.Now().UTC().Format(domain.DateLayout))
+	}
+
+	if data.SprintID != "" {
+		if err = s.requireOpenSprint(ctx, data.ProjectID, data.SprintID); err != nil {
+			return
+		}
+	}
+
+	if data.MilestoneID != "" {
+		if err = s.requireMilestone(ctx, data.ProjectID, data.MilestoneID); err != nil {
+			return
+		}
+	}
+
+	if data.ParentID != "" {
+		if err = s.requireParent(ctx, "", data.ParentID, data.ProjectID); err != nil {
+			return
+		}
+	}
+
+	// the authenticated user is the author unless the request names someone else
+	if data.AuthorID == "" {
+		data.AuthorID = actor.UserID()
+	}
+
+	if err = s.requireMember(ctx, data.ProjectID, data.AuthorID); err != nil {
+		return
+	}
+
+	if data.AssigneeID != "" {
+		if err = s.requireMember(ctx, data.ProjectID, data.AssigneeID); err != nil {
+			return
+		}
+	}
+
+	// new tasks start in the initial status of the project workflow unless
+	// the request names another status of it
+	w, err := s.lockedWorkflow(ctx, data.ProjectID)
 	if err != nil {
-		logger.Err(err).Stack().Msg("failed to create task")
 		return
 	}
 
+	if data.Status == "" {
+		data.Status = w.Initial
+	}
+
+	if !w.HasStatus(data.Status) {
+		err = task.ErrUnknownStatus
+		return
+	}
+
+	if w.IsTerminal(data.Status) {
+		now := time.Now().UTC()
+		data.CompletedAt = &now
+	}
+
 	return
 }
 
@@ -45,32 +138,247 @@ func (s *Service) GetTask(ctx context.Context, id string) (res task.Response, er
 	return
 }
 
-func (s *Service) UpdateTask(ctx context.Context, id string, req task.UpdateRequest) (err error) {
+// UpdateTask applies the request to the given version of the task, zero
+// applies it to whatever version is current.
+func (s *Service) UpdateTask(ctx context.Context, id string, version int64, req task.UpdateRequest) (err error) {
+	return s.updateTask(ctx, id, version, req, nil)
+}
+
+// PatchTask applies a merge patch to the given version of the task, unlike
+// UpdateTask it can clear the optional fields.
+func (s *Service) PatchTask(ctx context.Context, id string, version int64, req task.PatchRequest) (err error) {
+	return s.updateTask(ctx, id, version, req.Update(), req.Clear())
+}
+
+func (s *Service) updateTask(ctx context.Context, id string, version int64, req task.UpdateRequest, clear []task.Field) (err error) {
 	logger := log.LoggerFromContext(ctx)
 
+	err = s.withinTx(ctx, func(ctx context.Context) (err error) {
+		current, w, err := s.prepareUpdate(ctx, id, version, req, clear)
+		if err != nil {
+			return
+		}
+
+		if err = s.taskRepository.Patch(ctx, id, w.Entity, w.Clear, w.Events...); err != nil {
+			return
+		}
+
+		// labels, sprints and milestones belong to a project and do not follow
+		// the task
+		if w.Entity.ProjectID != "" && w.Entity.ProjectID != current.ProjectID {
+			if err = s.detachFromProject(ctx, current); err != nil {
+				return
+			}
+		}
+
+		return s.recordTaskUpdate(ctx, current)
+	})
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to update task")
+		return
+	}
+
+	return
+}
+
+// detachFromProject drops the labels, sprint and milestone of a task moved to
+// another project.
+func (s *Service) detachFromProject(ctx context.Context, current task.Entity) (err error) {
+	id := current.ID
+	claims, _ := token.ClaimsFromContext(ctx)
+
+	if err = s.labelRepository.DetachAll(ctx, id); err != nil {
+		return
+	}
+
+	if e, ok := task.ScheduleEvent(current, "", claims.UserID(), time.Now().UTC()); ok {
+		if err = s.sprintRepository.Schedule(ctx, id, "", e); err != nil {
+			return
+		}
+	}
+
+	if e, ok := task.MilestoneEvent(current, "", claims.UserID(), time.Now().UTC()); ok {
+		err = s.milestoneRepository.Link(ctx, id, "", e)
+	}
+
+	return
+}
+
+// prepareUpdate checks an update of the given version of the task and
+// returns the task as it is now along with the write of the update.
+func (s *Service) prepareUpdate(ctx context.Context, id string, version int64, req task.UpdateRequest, clear []task.Field) (current task.Entity, w task.Write, err error) {
+	current, err = s.taskRepository.Get(ctx, id)
+	if err != nil {
+		return
+	}
+
+	if err = requireVersion(current.Version, version); err != nil {
+		return
+	}
+
+	actor, err := s.authorizeTaskEdit(ctx, current.ProjectID)
+	if err != nil {
+		return
+	}
+
+	// moving a task requires access to the target project as well
+	if req.ProjectID != "" && req.ProjectID != current.ProjectID {
+		if _, err = s.authorizeTaskEdit(ctx, req.ProjectID); err != nil {
+			return
+		}
+	}
+
 	data := task.Entity{
 		Title:       req.Title,
 		Description: req.Description,
 		Priority:    req.Priority,
 		Status:      req.Status,
-		DoneAt:      domain.OnlyDate(req.DoneAt),
+		DueDate:     domain.OnlyDate(req.DueDate),
 		AuthorID:    req.AuthorID,
-		ProjectID:   req.AuthorID,
+		ProjectID:   req.ProjectID,
+		ParentID:    req.ParentID,
+
+		EstimateHours: req.EstimateHours,
+		Version:       current.Version,
+	}
+
+	if err = s.moveTask(ctx, current, data, clear); err != nil {
+		return
+	}
+
+	if err = s.transitionTask(ctx, current, &data); err != nil {
+		return
+	}
+
+	authorID, projectID := current.AuthorID, current.ProjectID
+	if req.AuthorID != "" {
+		authorID = req.AuthorID
+	}
+	if req.ProjectID != "" {
+		projectID = req.ProjectID
+	}
+
+	// the author has to stay a member of the project the task ends up in
+	if req.AuthorID != "" || req.ProjectID != "" {
+		if err = s.requireMember(ctx, projectID, authorID); err != nil {
+			return
+		}
+	}
+
+	// a task is only handed to members of its project, unassigning goes
+	// through clear
+	if req.AssigneeID != "" {
+		if err = s.requireMember(ctx, projectID, req.AssigneeID); err != nil {
+			return
+		}
+		data.AssigneeID = req.AssigneeID
 	}
 
-	err = s.taskRepository.Update(ctx, id, data)
+	now := time.Now().UTC()
+	w = task.Write{
+		Op:     task.OpUpdate,
+		ID:     id,
+		Entity: data,
+		Clear:  clear,
+		Events: append(task.Diff(current, data, actor.UserID(), now), task.ClearEvents(current, clear, actor.UserID(), now)...),
+	}
+
+	return
+}
+
+// recordTaskUpdate writes the change of the task to the audit log.
+func (s *Service) recordTaskUpdate(ctx context.Context, current task.Entity) error {
+	updated, err := s.taskRepository.Get(ctx, current.ID)
 	if err != nil {
-		logger.Err(err).Stack().Msg("failed to update task")
+		return err
+	}
+
+	return s.record(ctx, audit.EntityTask, current.ID, audit.ActionUpdate, task.ParseFromEntity(current), task.ParseFromEntity(updated))
+}
+
+// AssignTask hands the task to another member of its project, an empty
+// assignee unassigns it.
+func (s *Service) AssignTask(ctx context.Context, id string, req task.AssignRequest) (err error) {
+	logger := log.LoggerFromContext(ctx)
+
+	current, err := s.taskRepository.Get(ctx, id)
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to assign task")
+		return
+	}
+
+	actor, err := s.authorizeTaskEdit(ctx, current.ProjectID)
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to assign task")
+		return
+	}
+
+	if req.AssigneeID != "" {
+		if err = s.requireMember(ctx, current.ProjectID, req.AssigneeID); err != nil {
+			logger.Err(err).Stack().Msg("failed to assign task")
+			return
+		}
+	}
+
+	var events []task.Event
+	if e, ok := task.AssignEvent(current, req.AssigneeID, actor.UserID(), time.Now().UTC()); ok {
+		events = append(events, e)
+	}
+
+	updated := current
+	updated.AssigneeID = req.AssigneeID
+
+	err = s.withinTx(ctx, func(ctx context.Context) (err error) {
+		if err = s.taskRepository.Assign(ctx, id, req.AssigneeID, events...); err != nil {
+			return
+		}
+
+		return s.record(ctx, audit.EntityTask, id, audit.ActionUpdate, task.ParseFromEntity(current), task.ParseFromEntity(updated))
+	})
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to assign task")
+		return
+	}
+
+	return
+}
+
+// GetTaskHistory returns the changes of the task, oldest first.
+func (s *Service) GetTaskHistory(ctx context.Context, id string) (res []task.EventResponse, err error) {
+	logger := log.LoggerFromContext(ctx)
+
+	if _, err = s.taskRepository.Get(ctx, id); err != nil {
+		logger.Err(err).Stack().Msg("failed to get task history")
+		return
+	}
+
+	data, err := s.taskRepository.History(ctx, id)
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to get task history")
 		return
 	}
 
+	res = task.ParseFromEvents(data)
+
 	return
 }
 
-func (s *Service) DeleteTask(ctx context.Context, id string) (err error) {
+func (s *Service) DeleteTask(ctx context.Context, id string, version int64) (err error) {
 	logger := log.LoggerFromContext(ctx)
 
-	err = s.taskRepository.Delete(ctx, id)
+	current, err := s.prepareDelete(ctx, id, version)
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to delete task")
+		return
+	}
+
+	err = s.withinTx(ctx, func(ctx context.Context) (err error) {
+		if err = s.taskRepository.Delete(ctx, id, current.Version); err != nil {
+			return
+		}
+
+		return s.record(ctx, audit.EntityTask, id, audit.ActionDelete, task.ParseFromEntity(current), nil)
+	})
 	if err != nil {
 		logger.Err(err).Stack().Msg("failed to delete task")
 		return
@@ -79,36 +387,53 @@ func (s *Service) DeleteTask(ctx context.Context, id string) (err error) {
 	return
 }
 
-func (s *Service) ListTasks(ctx context.Context) (res []task.Response, err error) {
+// prepareDelete checks the deletion of the given version of the task and
+// returns the task as it is now.
+func (s *Service) prepareDelete(ctx context.Context, id string, version int64) (current task.Entity, err error) {
+	current, err = s.taskRepository.Get(ctx, id)
+	if err != nil {
+		return
+	}
+
+	if err = requireVersion(current.Version, version); err != nil {
+		return
+	}
+
+	_, err = s.authorizeTaskEdit(ctx, current.ProjectID)
+
+	return
+}
+
+func (s *Service) ListTasks(ctx context.Context, filter task.Filter, page domain.PageRequest) (res domain.Page[task.Response], err error) {
 	logger := log.LoggerFromContext(ctx)
 
-	data, err := s.taskRepository.List(ctx)
+	data, err := s.taskRepository.List(ctx, filter, page)
 	if err != nil {
 		logger.Err(err).Stack().Msg("failed to get tasks")
 		return
 	}
 
-	res = task.ParseFromEntities(data)
+	res = domain.MapPage(data, task.ParseFromEntity)
 
 	return
 }
 
-func (s *Service) SearchTasks(ctx context.Context, filter, value string) (res []task.Response, err error) {
+func (s *Service) SearchTasks(ctx context.Context, filter task.Filter, page domain.PageRequest) (res domain.Page[task.Response], err error) {
 	logger := log.LoggerFromContext(ctx)
 
-	if value == "" || !task.IsValidFilter(filter) {
+	if filter.IsEmpty() {
 		err = task.ErrSearch
 		logger.Err(err).Stack().Msg("failed to search tasks")
 		return
 	}
 
-	data, err := s.taskRepository.Search(ctx, filter, value)
+	data, err := s.taskRepository.Search(ctx, filter, page)
 	if err != nil {
 		logger.Err(err).Stack().Msg("failed to search tasks")
 		return
 	}
 
-	res = task.ParseFromEntities(data)
+	res = domain.MapPage(data, task.ParseFromEntity)
 
 	return
 }
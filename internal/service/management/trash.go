@@ -0,0 +1,208 @@
+package management
+
+import (
+	"context"
+	"errors"
+	"project-management/internal/domain"
+	"project-management/internal/domain/attachment"
+	"project-management/internal/domain/audit"
+	"project-management/internal/domain/project"
+	"project-management/internal/domain/task"
+	"project-management/pkg/log"
+	"time"
+)
+
+// ScopeDeleted returns a context whose reads also or only return soft deleted
+// rows. Only admins may look into the trash.
+func (s *Service) ScopeDeleted(ctx context.Context, scope domain.DeletedScope) (context.Context, error) {
+	if scope == domain.ExcludeDeleted {
+		return ctx, nil
+	}
+
+	if _, err := s.authorizeTrash(ctx); err != nil {
+		return ctx, err
+	}
+
+	return domain.WithDeletedScope(ctx, scope), nil
+}
+
+func (s *Service) RestoreUser(ctx context.Context, id string) (err error) {
+	logger := log.LoggerFromContext(ctx)
+
+	if _, err = s.authorizeUserManagement(ctx); err != nil {
+		logger.Err(err).Stack().Msg("failed to restore user")
+		return
+	}
+
+	current, err := s.userRepostitory.Get(domain.WithDeletedScope(ctx, domain.IncludeDeleted), id)
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to restore user")
+		return
+	}
+
+	if current.DeletedAt == nil {
+		err = domain.ErrNotDeleted
+		logger.Err(err).Stack().Msg("failed to restore user")
+		return
+	}
+
+	err = s.withinTx(ctx, func(ctx context.Context) (err error) {
+		if err = s.userRepostitory.Restore(ctx, id); err != nil {
+			return
+		}
+
+		return s.record(ctx, audit.EntityUser, id, audit.ActionRestore, nil, nil)
+	})
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to restore user")
+		return
+	}
+
+	return
+}
+
+// RestoreProject brings the project back together with the tasks that were
+// deleted along with it.
+func (s *Service) RestoreProject(ctx context.Context, id string) (err error) {
+	logger := log.LoggerFromContext(ctx)
+
+	current, err := s.projectRepository.Get(domain.WithDeletedScope(ctx, domain.IncludeDeleted), id)
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to restore project")
+		return
+	}
+
+	if _, err = s.authorizeProjectEdit(ctx, current); err != nil {
+		logger.Err(err).Stack().Msg("failed to restore project")
+		return
+	}
+
+	if current.DeletedAt == nil {
+		err = domain.ErrNotDeleted
+		logger.Err(err).Stack().Msg("failed to restore project")
+		return
+	}
+
+	err = s.withinTx(ctx, func(ctx context.Context) (err error) {
+		if err = s.projectRepository.Restore(ctx, id); err != nil {
+			return
+		}
+
+		return s.record(ctx, audit.EntityProject, id, audit.ActionRestore, nil, nil)
+	})
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to restore project")
+		return
+	}
+
+	return
+}
+
+// RestoreTask brings a task back, its project has to be restored first.
+func (s *Service) RestoreTask(ctx context.Context, id string) (err error) {
+	logger := log.LoggerFromContext(ctx)
+
+	current, err := s.taskRepository.Get(domain.WithDeletedScope(ctx, domain.IncludeDeleted), id)
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to restore task")
+		return
+	}
+
+	if _, err = s.authorizeTaskEdit(ctx, current.ProjectID); err != nil {
+		logger.Err(err).Stack().Msg("failed to restore task")
+		return
+	}
+
+	if current.DeletedAt == nil {
+		err = domain.ErrNotDeleted
+		logger.Err(err).Stack().Msg("failed to restore task")
+		return
+	}
+
+	if _, err = s.projectRepository.Get(ctx, current.ProjectID); err != nil {
+		if errors.Is(err, project.ErrNotFound) {
+			err = task.ErrProjectDeleted
+		}
+		logger.Err(err).Stack().Msg("failed to restore task")
+		return
+	}
+
+	err = s.withinTx(ctx, func(ctx context.Context) (err error) {
+		if err = s.taskRepository.Restore(ctx, id); err != nil {
+			return
+		}
+
+		return s.record(ctx, audit.EntityTask, id, audit.ActionRestore, nil, nil)
+	})
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to restore task")
+		return
+	}
+
+	return
+}
+
+// PurgeDeleted removes users, projects and tasks that have been in the trash
+// for longer than retention. Tasks deleted along with a project share its
+// deletion time and are purged in the same run.
+func (s *Service) PurgeDeleted(ctx context.Context, retention time.Duration) (err error) {
+	logger := log.LoggerFromContext(ctx)
+
+	before := time.Now().UTC().Add(-retention)
+
+	var expired []attachment.Entity
+	var tasks, projects, users int64
+
+	// a run removes all of the rows or none of them, a failed one is left
+	// for the next run as a whole
+	err = s.withinTx(ctx, func(ctx context.Context) (err error) {
+		// the attachment rows go with their tasks, their content is removed
+		// once the tasks are gone
+		if expired, err = s.attachmentRepository.Expired(ctx, before); err != nil {
+			return
+		}
+
+		if tasks, err = s.taskRepository.Purge(ctx, before); err != nil {
+			return
+		}
+
+		if projects, err = s.projectRepository.Purge(ctx, before); err != nil {
+			return
+		}
+
+		users, err = s.userRepostitory.Purge(ctx, before)
+
+		return
+	})
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to purge deleted rows")
+		return
+	}
+
+	for _, a := range expired {
+		s.deleteBlob(ctx, a.Key())
+	}
+
+	if tasks+projects+users > 0 {
+		logger.Info().Int64("tasks", tasks).Int64("projects", projects).Int64("users", users).Msg("purged deleted rows")
+	}
+
+	return
+}
+
+// RunPurge calls PurgeDeleted every interval until ctx is done.
+func (s *Service) RunPurge(ctx context.Context, retention, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		// failures are logged by PurgeDeleted and retried on the next tick
+		_ = s.PurgeDeleted(ctx, retention)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
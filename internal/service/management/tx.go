@@ -0,0 +1,14 @@
+package management
+
+import "context"
+
+// withinTx runs fn as one unit of work, the repositories it calls with the
+// context it is given either all keep their changes or none does. A service
+// configured without a TxManager runs fn as it is.
+func (s *Service) withinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if s.txManager == nil {
+		return fn(ctx)
+	}
+
+	return s.txManager.WithinTx(ctx, fn)
+}
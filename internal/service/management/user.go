@@ -2,21 +2,27 @@ package management
 
 import (
 	"context"
+	"errors"
 	"project-management/internal/domain"
+	"project-management/internal/domain/audit"
+	"project-management/internal/domain/project"
+	"project-management/internal/domain/task"
 	"project-management/internal/domain/user"
 	"project-management/pkg/log"
+	"slices"
+	"time"
 )
 
-func (s *Service) ListUsers(ctx context.Context) (res []user.Response, err error) {
+func (s *Service) ListUsers(ctx context.Context, page domain.PageRequest) (res domain.Page[user.Response], err error) {
 	logger := log.LoggerFromContext(ctx)
 
-	data, err := s.userRepostitory.List(ctx)
+	data, err := s.userRepostitory.List(ctx, page)
 	if err != nil {
 		logger.Err(err).Stack().Msg("failed to get users")
 		return
 	}
 
-	res = user.ParseFromEntities(data)
+	res = domain.MapPage(data, user.ParseFromEntity)
 
 	return
 }
@@ -24,15 +30,33 @@ func (s *Service) ListUsers(ctx context.Context) (res []user.Response, err error
 func (s *Service) CreateUser(ctx context.Context, req user.Request) (id string, err error) {
 	logger := log.LoggerFromContext(ctx)
 
+	if _, err = s.authorizeUserManagement(ctx); err != nil {
+		logger.Err(err).Stack().Msg("failed to create user")
+		return
+	}
+
+	hash, err := hashPassword(req.Password)
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to hash password")
+		return
+	}
+
 	data := user.Entity{
 		ID:               domain.GenerateID(),
 		Name:             req.Name,
 		Email:            req.Email,
 		RegistrationDate: domain.OnlyDate(req.RegistrationDate),
 		Role:             req.Role,
+		PasswordHash:     hash,
 	}
 
-	id, err = s.userRepostitory.Create(ctx, data)
+	err = s.withinTx(ctx, func(ctx context.Context) (err error) {
+		if id, err = s.userRepostitory.Create(ctx, data); err != nil {
+			return
+		}
+
+		return s.record(ctx, audit.EntityUser, id, audit.ActionCreate, nil, user.ParseFromEntity(data))
+	})
 	if err != nil {
 		logger.Err(err).Stack().Msg("failed to create user")
 		return
@@ -55,16 +79,64 @@ func (s *Service) GetUser(ctx context.Context, id string) (res user.Response, er
 	return
 }
 
-func (s *Service) UpdateUser(ctx context.Context, id string, req user.UpdateRequest) (err error) {
+// UpdateUser applies the request to the given version of the user, zero
+// applies it to whatever version is current.
+func (s *Service) UpdateUser(ctx context.Context, id string, version int64, req user.UpdateRequest) (err error) {
 	logger := log.LoggerFromContext(ctx)
 
+	// users may change their own profile and password, but not their role
+	actor, err := s.actor(ctx)
+	if err == nil && (actor.UserID() != id || req.Role != "") {
+		_, err = s.authorizeUserManagement(ctx)
+	}
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to update user")
+		return
+	}
+
+	current, err := s.userRepostitory.Get(ctx, id)
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to update user")
+		return
+	}
+
+	if err = requireVersion(current.Version, version); err != nil {
+		logger.Err(err).Stack().Msg("failed to update user")
+		return
+	}
+
 	data := user.Entity{
-		Name:  req.Name,
-		Email: req.Email,
-		Role:  req.Role,
+		Name:    req.Name,
+		Email:   req.Email,
+		Role:    req.Role,
+		Version: current.Version,
 	}
 
-	err = s.userRepostitory.Update(ctx, id, data)
+	if req.Password != "" {
+		data.PasswordHash, err = hashPassword(req.Password)
+		if err != nil {
+			logger.Err(err).Stack().Msg("failed to hash password")
+			return
+		}
+	}
+
+	err = s.withinTx(ctx, func(ctx context.Context) (err error) {
+		if err = s.userRepostitory.Update(ctx, id, data); err != nil {
+			return
+		}
+
+		updated, err := s.userRepostitory.Get(ctx, id)
+		if err != nil {
+			return
+		}
+
+		after := auditedUser{Response: user.ParseFromEntity(updated)}
+		if req.Password != "" {
+			after.Password = "changed"
+		}
+
+		return s.record(ctx, audit.EntityUser, id, audit.ActionUpdate, auditedUser{Response: user.ParseFromEntity(current)}, after)
+	})
 	if err != nil {
 		logger.Err(err).Stack().Msg("failed to update user")
 		return
@@ -73,19 +145,173 @@ func (s *Service) UpdateUser(ctx context.Context, id string, req user.UpdateRequ
 	return
 }
 
-func (s *Service) DeleteUser(ctx context.Context, id string) (err error) {
+// PatchUser applies a merge patch to the given version of the user. None of
+// the fields of a user can be cleared, so it amounts to UpdateUser.
+func (s *Service) PatchUser(ctx context.Context, id string, version int64, req user.PatchRequest) (err error) {
+	return s.UpdateUser(ctx, id, version, req.Update())
+}
+
+// auditedUser marks password changes in the audit log, the hash itself never
+// gets there.
+type auditedUser struct {
+	user.Response
+	Password string `json:"password,omitempty"`
+}
+
+// DeleteUser moves the user to the trash. The live projects the user manages
+// and the tasks they author or are assigned to pass to reassignTo in the same
+// unit of work, which makes reassignTo a member of their projects if needed.
+// Without reassignTo a user who still manages a project or owns an open task
+// can not be deleted.
+func (s *Service) DeleteUser(ctx context.Context, id string, version int64, reassignTo string) (err error) {
 	logger := log.LoggerFromContext(ctx)
 
-	err = s.userRepostitory.Delete(ctx, id)
+	actor, err := s.authorizeUserManagement(ctx)
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to delete user")
+		return
+	}
+
+	current, err := s.userRepostitory.Get(ctx, id)
 	if err != nil {
 		logger.Err(err).Stack().Msg("failed to delete user")
 		return
 	}
 
+	if err = requireVersion(current.Version, version); err != nil {
+		logger.Err(err).Stack().Msg("failed to delete user")
+		return
+	}
+
+	if reassignTo == id {
+		err = user.ErrReassignTarget
+		logger.Err(err).Stack().Msg("failed to delete user")
+		return
+	}
+
+	var changes []reassignment
+	err = s.withinTx(ctx, func(ctx context.Context) (err error) {
+		// with both users locked no project or task can be handed to the user
+		// until it is deleted, which keeps the work read below complete. The
+		// locks are taken in the order of the ids so that two deletions
+		// reassigning to each other do not wait on one another.
+		for _, lockID := range lockOrder(id, reassignTo) {
+			if err = s.userRepostitory.Lock(ctx, lockID); err != nil {
+				if lockID == reassignTo && errors.Is(err, user.ErrNotFound) {
+					err = user.ErrReassignTarget
+				}
+				return
+			}
+		}
+
+		projects, err := s.projectRepository.ManagedBy(ctx, id)
+		if err != nil {
+			return
+		}
+
+		tasks, err := s.taskRepository.OwnedBy(ctx, id)
+		if err != nil {
+			return
+		}
+
+		if reassignTo == "" {
+			open := slices.ContainsFunc(tasks, func(t task.Entity) bool { return t.CompletedAt == nil })
+			if len(projects) > 0 || open {
+				return user.ErrOwnsWork
+			}
+		} else {
+			changes, err = s.reassignWork(ctx, id, reassignTo, actor.UserID(), projects, tasks)
+			if err != nil {
+				return
+			}
+		}
+
+		if err = s.userRepostitory.Delete(ctx, id, current.Version); err != nil {
+			return
+		}
+
+		for _, c := range changes {
+			if err = s.record(ctx, c.entityType, c.id, audit.ActionUpdate, c.old, c.new); err != nil {
+				return
+			}
+		}
+
+		return s.record(ctx, audit.EntityUser, id, audit.ActionDelete, user.ParseFromEntity(current), nil)
+	})
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to delete user")
+		return
+	}
+
+	return
+}
+
+// lockOrder returns the given ids sorted, without the empty ones.
+func lockOrder(ids ...string) []string {
+	ids = slices.DeleteFunc(ids, func(id string) bool { return id == "" })
+	slices.Sort(ids)
+
+	return ids
+}
+
+// reassignment is a change of reassignWork for the audit log.
+type reassignment struct {
+	entityType string
+	id         string
+	old, new   any
+}
+
+// reassignWork hands the projects managed by and the tasks owned by one user
+// to another one.
+func (s *Service) reassignWork(ctx context.Context, fromID, toID, actorID string, projects []project.Entity, tasks []task.Entity) (changes []reassignment, err error) {
+	for _, p := range projects {
+		data := project.Entity{ManagerID: toID, Version: p.Version}
+		if err = s.projectRepository.Update(ctx, p.ID, data); err != nil {
+			return
+		}
+
+		if err = s.ensureManagerMembership(ctx, p.ID, toID); err != nil {
+			return
+		}
+
+		updated := p
+		updated.ManagerID = toID
+		changes = append(changes, reassignment{audit.EntityProject, p.ID, project.ParseFromEntity(p), project.ParseFromEntity(updated)})
+	}
+
+	now := time.Now().UTC()
+	for _, t := range tasks {
+		// authors and assignees have to be members of the project of the task
+		if err = s.ensureMembership(ctx, t.ProjectID, toID, project.MemberRoleDeveloper); err != nil {
+			return
+		}
+
+		data := task.Entity{Version: t.Version}
+		if t.AuthorID == fromID {
+			data.AuthorID = toID
+		}
+		if t.AssigneeID == fromID {
+			data.AssigneeID = toID
+		}
+
+		if err = s.taskRepository.Update(ctx, t.ID, data, task.Diff(t, data, actorID, now)...); err != nil {
+			return
+		}
+
+		updated := t
+		if data.AuthorID != "" {
+			updated.AuthorID = toID
+		}
+		if data.AssigneeID != "" {
+			updated.AssigneeID = toID
+		}
+		changes = append(changes, reassignment{audit.EntityTask, t.ID, task.ParseFromEntity(t), task.ParseFromEntity(updated)})
+	}
+
 	return
 }
 
-func (s *Service) SearchUsers(ctx context.Context, filter, value string) (res []user.Response, err error) {
+func (s *Service) SearchUsers(ctx context.Context, filter, value string, page domain.PageRequest) (res domain.Page[user.Response], err error) {
 	logger := log.LoggerFromContext(ctx)
 
 	if value == "" || !user.IsValidFilter(filter) {
@@ -94,13 +320,13 @@ func (s *Service) SearchUsers(ctx context.Context, filter, value string) (res []
 		return
 	}
 
-	data, err := s.userRepostitory.Search(ctx, filter, value)
+	data, err := s.userRepostitory.Search(ctx, filter, value, page)
 	if err != nil {
 		logger.Err(err).Stack().Msg("failed to search users")
 		return
 	}
 
-	res = user.ParseFromEntities(data)
+	res = domain.MapPage(data, user.ParseFromEntity)
 
 	return
 }
@@ -0,0 +1,144 @@
+package management
+
+import (
+	"errors"
+	"testing"
+
+	"project-management/internal/domain"
+	"project-management/internal/domain/project"
+	"project-management/internal/domain/user"
+)
+
+func TestDeleteUser(t *testing.T) {
+	// leaving manages the project, authors one task and is assigned one by
+	// author, idle owns nothing
+	type users struct{ leaving, idle, author, target, gone string }
+
+	tests := []struct {
+		name       string
+		id         func(u users) string
+		reassignTo func(u users) string
+		version    int64
+		err        error
+		reassigned bool
+		// member makes the target a developer of the project beforehand
+		member bool
+	}{
+		{
+			name:       "owning work without a target",
+			id:         func(u users) string { return u.leaving },
+			reassignTo: func(u users) string { return "" },
+			err:        user.ErrOwnsWork,
+		},
+		{
+			name:       "owning nothing without a target",
+			id:         func(u users) string { return u.idle },
+			reassignTo: func(u users) string { return "" },
+		},
+		{
+			name:       "reassigning to the user itself",
+			id:         func(u users) string { return u.leaving },
+			reassignTo: func(u users) string { return u.leaving },
+			err:        user.ErrReassignTarget,
+		},
+		{
+			name:       "reassigning to an unknown user",
+			id:         func(u users) string { return u.leaving },
+			reassignTo: func(u users) string { return "unknown" },
+			err:        user.ErrReassignTarget,
+		},
+		{
+			name:       "reassigning to a deleted user",
+			id:         func(u users) string { return u.leaving },
+			reassignTo: func(u users) string { return u.gone },
+			err:        user.ErrReassignTarget,
+		},
+		{
+			name:       "stale version",
+			id:         func(u users) string { return u.leaving },
+			reassignTo: func(u users) string { return u.target },
+			version:    42,
+			err:        domain.ErrVersionConflict,
+		},
+		{
+			name:       "reassigning the work",
+			id:         func(u users) string { return u.leaving },
+			reassignTo: func(u users) string { return u.target },
+			reassigned: true,
+		},
+		{
+			name:       "reassigning to a member of the project",
+			id:         func(u users) string { return u.leaving },
+			reassignTo: func(u users) string { return u.target },
+			reassigned: true,
+			member:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(t)
+			u := users{
+				leaving: f.user(user.RoleManager),
+				idle:    f.user(user.RoleDeveloper),
+				author:  f.user(user.RoleDeveloper),
+				target:  f.user(user.RoleDeveloper),
+				gone:    f.user(user.RoleDeveloper),
+			}
+			if err := f.s.userRepostitory.Delete(f.ctx, u.gone, 0); err != nil {
+				t.Fatal(err)
+			}
+
+			members := []string{u.author}
+			if tt.member {
+				members = append(members, u.target)
+			}
+
+			p := f.project(u.leaving, members...)
+			authored := f.task(p, u.leaving)
+			assigned := f.task(p, u.author)
+			if err := f.s.taskRepository.Assign(f.ctx, assigned, u.leaving); err != nil {
+				t.Fatal(err)
+			}
+
+			id := tt.id(u)
+			err := f.s.DeleteUser(f.ctx, id, tt.version, tt.reassignTo(u))
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("got error %v, want %v", err, tt.err)
+			}
+
+			if _, err = f.s.userRepostitory.Get(f.ctx, id); (err == nil) != (tt.err != nil) {
+				t.Errorf("user kept: got %v, want %v", err == nil, tt.err != nil)
+			}
+
+			owner := u.leaving
+			if tt.reassigned {
+				owner = u.target
+			}
+
+			pr, err := f.s.projectRepository.Get(f.ctx, p)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if pr.ManagerID != owner {
+				t.Errorf("got manager %q, want %q", pr.ManagerID, owner)
+			}
+			if got := f.get(authored).AuthorID; got != owner {
+				t.Errorf("got author %q, want %q", got, owner)
+			}
+			if got := f.get(assigned); got.AssigneeID != owner || got.AuthorID != u.author {
+				t.Errorf("got author %q and assignee %q, want %q and %q", got.AuthorID, got.AssigneeID, u.author, owner)
+			}
+
+			if tt.reassigned {
+				m, err := f.s.memberRepository.GetMember(f.ctx, p, u.target)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if m.Role != project.MemberRoleManager {
+					t.Errorf("got role %q for the new manager, want %q", m.Role, project.MemberRoleManager)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,14 @@
+package management
+
+import "project-management/internal/domain"
+
+// requireVersion checks the version a client based its write on against the
+// version just read, zero accepts any version. The repositories check the
+// version read once more when writing, so a write in between still fails.
+func requireVersion(current, expected int64) error {
+	if expected != 0 && expected != current {
+		return domain.ErrVersionConflict
+	}
+
+	return nil
+}
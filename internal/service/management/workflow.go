@@ -0,0 +1,197 @@
+package management
+
+import (
+	"context"
+	"errors"
+	"project-management/internal/domain"
+	"project-management/internal/domain/audit"
+	"project-management/internal/domain/project"
+	"project-management/internal/domain/task"
+	"project-management/internal/domain/user"
+	"project-management/pkg/log"
+	"time"
+)
+
+func (s *Service) GetProjectWorkflow(ctx context.Context, projectID string) (res task.Workflow, err error) {
+	logger := log.LoggerFromContext(ctx)
+
+	if _, err = s.projectRepository.Get(ctx, projectID); err != nil {
+		logger.Err(err).Stack().Msg("failed to get project workflow")
+		return
+	}
+
+	res, err = s.projectWorkflow(ctx, projectID)
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to get project workflow")
+		return
+	}
+
+	return
+}
+
+// UpdateProjectWorkflow replaces the workflow of the project. Statuses that
+// are still used by tasks of the project can not be dropped, tasks in the
+// trash count as well since restoring them brings the status back.
+func (s *Service) UpdateProjectWorkflow(ctx context.Context, projectID string, req task.Workflow) (err error) {
+	logger := log.LoggerFromContext(ctx)
+
+	p, err := s.projectRepository.Get(ctx, projectID)
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to update project workflow")
+		return
+	}
+
+	if _, err = s.authorizeProjectEdit(ctx, p); err != nil {
+		logger.Err(err).Stack().Msg("failed to update project workflow")
+		return
+	}
+
+	// the lock waits for the task writes that checked a status against the
+	// current workflow and keeps new ones out until the new workflow is
+	// stored, see lockedWorkflow, so the check sees every status in use
+	err = s.withinTx(ctx, func(ctx context.Context) (err error) {
+		if err = s.projectRepository.Lock(ctx, projectID); err != nil {
+			return
+		}
+
+		current, err := s.projectWorkflow(ctx, projectID)
+		if err != nil {
+			return
+		}
+
+		var dropped []string
+		for _, status := range current.Statuses {
+			if !req.HasStatus(status) {
+				dropped = append(dropped, status)
+			}
+		}
+
+		if len(dropped) > 0 {
+			filter := task.Filter{}.
+				With(task.Equals(task.FieldProjectID, projectID)).
+				With(task.Condition{Field: task.FieldStatus, Operator: task.OpIn, Values: dropped})
+
+			inUse, err := s.taskRepository.List(domain.WithDeletedScope(ctx, domain.IncludeDeleted), filter, domain.PageRequest{Limit: 1})
+			if err != nil {
+				return err
+			}
+
+			if inUse.Total > 0 {
+				return task.ErrStatusInUse
+			}
+		}
+
+		if err = s.workflowRepository.SaveWorkflow(ctx, projectID, req); err != nil {
+			return
+		}
+
+		return s.recordChanges(ctx, audit.EntityProject, projectID, audit.ActionUpdate, audit.Changes{"workflow": {Old: current, New: req}})
+	})
+	if err != nil {
+		logger.Err(err).Stack().Msg("failed to update project workflow")
+		return
+	}
+
+	return
+}
+
+// projectWorkflow returns the workflow configured for the project or the
+// default one.
+func (s *Service) projectWorkflow(ctx context.Context, projectID string) (task.Workflow, error) {
+	w, err := s.workflowRepository.GetWorkflow(ctx, projectID)
+	if errors.Is(err, task.ErrWorkflowNotFound) {
+		return task.DefaultWorkflow(), nil
+	}
+
+	return w, err
+}
+
+// lockedWorkflow returns the workflow of the project and keeps it from being
+// replaced until the unit of work ctx runs in ends, so that a status checked
+// against it stays valid until the task is stored.
+func (s *Service) lockedWorkflow(ctx context.Context, projectID string) (task.Workflow, error) {
+	if err := s.projectRepository.LockShared(ctx, projectID); err != nil {
+		return task.Workflow{}, err
+	}
+
+	return s.projectWorkflow(ctx, projectID)
+}
+
+// transitionTask checks the status change of a task against the workflow of
+// the project it ends up in. Entering a terminal status sets the completion
+// time of the task and leaving it clears it again. A task can only enter a
+// terminal status once all of its blockers are done.
+func (s *Service) transitionTask(ctx context.Context, current task.Entity, data *task.Entity) (err error) {
+	projectID, status := current.ProjectID, current.Status
+	if data.ProjectID != "" {
+		projectID = data.ProjectID
+	}
+	if data.Status != "" {
+		status = data.Status
+	}
+
+	if projectID == current.ProjectID && status == current.Status {
+		return
+	}
+
+	w, err := s.lockedWorkflow(ctx, projectID)
+	if err != nil {
+		return
+	}
+
+	// moving a task to another project keeps its status, which the target
+	// workflow has to know about
+	if status == current.Status {
+		if !w.HasStatus(status) {
+			err = task.ErrUnknownStatus
+		}
+		return
+	}
+
+	manager, err := s.managesProject(ctx, projectID)
+	if err != nil {
+		return
+	}
+
+	if err = w.CanTransition(current.Status, status, manager); err != nil {
+		return
+	}
+
+	switch done := w.IsTerminal(status); {
+	case done && current.CompletedAt == nil:
+		if err = s.requireBlockersDone(ctx, current.ID); err != nil {
+			return
+		}
+
+		now := time.Now().UTC()
+		data.CompletedAt = &now
+	case !done && current.CompletedAt != nil:
+		// the zero time tells the repository to clear it
+		data.CompletedAt = &time.Time{}
+	}
+
+	return
+}
+
+// managesProject reports whether the authenticated user is an admin or a
+// manager of the project.
+func (s *Service) managesProject(ctx context.Context, projectID string) (bool, error) {
+	actor, err := s.actor(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	if actor.Role == user.RoleAdmin {
+		return true, nil
+	}
+
+	m, err := s.memberRepository.GetMember(ctx, projectID, actor.UserID())
+	if err != nil {
+		if errors.Is(err, project.ErrMemberNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return m.Role == project.MemberRoleManager, nil
+}
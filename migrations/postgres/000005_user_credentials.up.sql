@@ -0,0 +1,2 @@
+-- users created before authentication existed can not log in until a password is set
+ALTER TABLE users ADD COLUMN IF NOT EXISTS password_hash VARCHAR NOT NULL DEFAULT '';
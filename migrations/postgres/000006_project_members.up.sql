@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS project_members (
+	project_id VARCHAR(24) REFERENCES projects(id) ON DELETE CASCADE,
+	user_id VARCHAR(24) REFERENCES users(id) ON DELETE CASCADE,
+	role VARCHAR NOT NULL CHECK (role IN ('manager', 'developer', 'viewer')),
+	joined_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	PRIMARY KEY (project_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS project_members_user_idx ON project_members(user_id);
+
+-- every existing manager becomes a member of the project they manage
+INSERT INTO project_members (project_id, user_id, role)
+SELECT id, manager_id, 'manager' FROM projects WHERE manager_id IS NOT NULL
+ON CONFLICT DO NOTHING;
+
+-- task authors keep access to the projects they work in
+INSERT INTO project_members (project_id, user_id, role)
+SELECT DISTINCT project_id, author_id, 'developer' FROM tasks
+WHERE project_id IS NOT NULL AND author_id IS NOT NULL
+ON CONFLICT DO NOTHING;
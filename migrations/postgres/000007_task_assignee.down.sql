@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS tasks_assignee_idx;
+
+ALTER TABLE tasks DROP COLUMN IF EXISTS assignee_id;
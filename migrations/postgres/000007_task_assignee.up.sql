@@ -0,0 +1,9 @@
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS assignee_id VARCHAR(24) REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS tasks_assignee_idx ON tasks(assignee_id);
+
+-- the column may predate the migration, its assignees need a membership too
+INSERT INTO project_members (project_id, user_id, role)
+SELECT DISTINCT project_id, assignee_id, 'developer' FROM tasks
+WHERE project_id IS NOT NULL AND assignee_id IS NOT NULL
+ON CONFLICT DO NOTHING;
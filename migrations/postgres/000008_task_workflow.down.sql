@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS project_workflows;
+
+UPDATE tasks SET status = 'in_progress' WHERE status = 'review';
+UPDATE tasks SET status = 'active' WHERE status NOT IN ('active', 'in_progress', 'done');
+
+ALTER TABLE tasks ALTER COLUMN status DROP NOT NULL;
+ALTER TABLE tasks ADD CONSTRAINT tasks_status_check CHECK (status IN ('active', 'in_progress', 'done'));
@@ -0,0 +1,12 @@
+-- statuses are defined by the project workflow now, the old check also
+-- misspelled in_progress
+ALTER TABLE tasks DROP CONSTRAINT IF EXISTS tasks_status_check;
+UPDATE tasks SET status = 'in_progress' WHERE status = 'in_proccess';
+UPDATE tasks SET status = 'active' WHERE status IS NULL;
+ALTER TABLE tasks ALTER COLUMN status SET NOT NULL;
+
+CREATE TABLE IF NOT EXISTS project_workflows (
+	project_id VARCHAR(24) PRIMARY KEY REFERENCES projects(id) ON DELETE CASCADE,
+	workflow JSONB NOT NULL,
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
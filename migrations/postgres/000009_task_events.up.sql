@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS task_events (
+	id BIGSERIAL PRIMARY KEY,
+	task_id VARCHAR(24) NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+	field VARCHAR(32) NOT NULL,
+	old_value TEXT NOT NULL DEFAULT '',
+	new_value TEXT NOT NULL DEFAULT '',
+	actor_id VARCHAR(24) REFERENCES users(id) ON DELETE SET NULL,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS task_events_task_idx ON task_events(task_id, created_at);
@@ -0,0 +1,14 @@
+-- entries outlive the entities and users they mention, so there are no
+-- foreign keys
+CREATE TABLE IF NOT EXISTS audit_log (
+	id BIGSERIAL PRIMARY KEY,
+	actor_id VARCHAR(24) NOT NULL DEFAULT '',
+	entity_type VARCHAR(16) NOT NULL,
+	entity_id VARCHAR(24) NOT NULL,
+	action VARCHAR(16) NOT NULL,
+	changes JSONB NOT NULL DEFAULT '{}',
+	created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS audit_log_entity_idx ON audit_log(entity_type, entity_id);
+CREATE INDEX IF NOT EXISTS audit_log_created_idx ON audit_log(created_at);
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS task_comments (
+	id VARCHAR(24) PRIMARY KEY,
+	task_id VARCHAR(24) NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+	parent_id VARCHAR(24) REFERENCES task_comments(id) ON DELETE CASCADE,
+	author_id VARCHAR(24) REFERENCES users(id) ON DELETE SET NULL,
+	body TEXT NOT NULL DEFAULT '',
+	created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	deleted_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS task_comments_task_idx ON task_comments(task_id, created_at);
+CREATE INDEX IF NOT EXISTS task_comments_parent_idx ON task_comments(parent_id);
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS task_attachments (
+	id VARCHAR(24) PRIMARY KEY,
+	task_id VARCHAR(24) NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+	name VARCHAR(255) NOT NULL,
+	content_type VARCHAR(255) NOT NULL,
+	size BIGINT NOT NULL,
+	uploader_id VARCHAR(24) REFERENCES users(id) ON DELETE SET NULL,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS task_attachments_task_idx ON task_attachments(task_id, created_at);
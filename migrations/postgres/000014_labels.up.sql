@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS labels (
+	id VARCHAR(24) PRIMARY KEY,
+	project_id VARCHAR(24) NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+	name VARCHAR(32) NOT NULL,
+	color CHAR(7) NOT NULL,
+	UNIQUE (project_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS task_labels (
+	task_id VARCHAR(24) NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+	label_id VARCHAR(24) NOT NULL REFERENCES labels(id) ON DELETE CASCADE,
+	PRIMARY KEY (task_id, label_id)
+);
+
+CREATE INDEX IF NOT EXISTS task_labels_label_idx ON task_labels(label_id);
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS task_dependencies (
+	blocker_id VARCHAR(24) NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+	blocked_id VARCHAR(24) NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	PRIMARY KEY (blocker_id, blocked_id),
+	CHECK (blocker_id <> blocked_id)
+);
+
+CREATE INDEX IF NOT EXISTS task_dependencies_blocked_idx ON task_dependencies(blocked_id);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS tasks_sprint_idx;
+
+ALTER TABLE tasks DROP COLUMN IF EXISTS sprint_id;
+
+DROP TABLE IF EXISTS sprints;
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS sprints (
+	id VARCHAR(24) PRIMARY KEY,
+	project_id VARCHAR(24) NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+	name VARCHAR(100) NOT NULL,
+	goal VARCHAR(500) NOT NULL DEFAULT '',
+	start_at DATE NOT NULL,
+	end_at DATE NOT NULL,
+	state VARCHAR(16) NOT NULL DEFAULT 'planned' CHECK (state IN ('planned', 'active', 'closed'))
+);
+
+CREATE INDEX IF NOT EXISTS sprints_project_idx ON sprints(project_id, start_at);
+
+-- a project has at most one active sprint
+CREATE UNIQUE INDEX IF NOT EXISTS sprints_active_idx ON sprints(project_id) WHERE state = 'active';
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS sprint_id VARCHAR(24) REFERENCES sprints(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS tasks_sprint_idx ON tasks(sprint_id);
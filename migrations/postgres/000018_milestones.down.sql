@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS tasks_milestone_idx;
+
+ALTER TABLE tasks DROP COLUMN IF EXISTS milestone_id;
+
+DROP TABLE IF EXISTS milestones;
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS milestones (
+	id VARCHAR(24) PRIMARY KEY,
+	project_id VARCHAR(24) NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+	title VARCHAR(100) NOT NULL,
+	description VARCHAR(500) NOT NULL DEFAULT '',
+	due_date DATE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS milestones_project_idx ON milestones(project_id, due_date);
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS milestone_id VARCHAR(24) REFERENCES milestones(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS tasks_milestone_idx ON tasks(milestone_id);
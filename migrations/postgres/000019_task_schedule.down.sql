@@ -0,0 +1,9 @@
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS done_at DATE;
+UPDATE tasks SET done_at = COALESCE(completed_at::date, due_date, created_at);
+ALTER TABLE tasks ALTER COLUMN done_at SET NOT NULL;
+
+DROP INDEX IF EXISTS tasks_due_date_idx;
+
+ALTER TABLE tasks DROP COLUMN IF EXISTS completed_at;
+ALTER TABLE tasks DROP COLUMN IF EXISTS estimate_hours;
+ALTER TABLE tasks DROP COLUMN IF EXISTS due_date;
@@ -0,0 +1,17 @@
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS due_date DATE;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS estimate_hours NUMERIC(7, 2) CHECK (estimate_hours >= 0);
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS completed_at TIMESTAMPTZ;
+
+-- done_at had to be made up by clients, it only means something for tasks
+-- in a terminal status of their workflow
+UPDATE tasks t SET completed_at = t.done_at::timestamptz
+WHERE t.status IN (
+	SELECT jsonb_array_elements_text(w.workflow->'terminal')
+	FROM project_workflows w WHERE w.project_id = t.project_id
+) OR (
+	t.status = 'done' AND NOT EXISTS (SELECT 1 FROM project_workflows w WHERE w.project_id = t.project_id)
+);
+
+ALTER TABLE tasks DROP COLUMN IF EXISTS done_at;
+
+CREATE INDEX IF NOT EXISTS tasks_due_date_idx ON tasks(due_date) WHERE completed_at IS NULL;
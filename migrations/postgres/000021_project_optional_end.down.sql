@@ -0,0 +1,2 @@
+UPDATE projects SET finished_at = started_at WHERE finished_at IS NULL;
+ALTER TABLE projects ALTER COLUMN finished_at SET NOT NULL;
@@ -0,0 +1,2 @@
+-- projects may run without a planned end
+ALTER TABLE projects ALTER COLUMN finished_at DROP NOT NULL;
@@ -0,0 +1,8 @@
+-- of the users sharing an email only the live one, or else the first deleted
+-- one, is kept
+DELETE FROM users u WHERE u.deleted_at IS NOT NULL AND EXISTS (
+	SELECT 1 FROM users o WHERE o.email = u.email AND o.id <> u.id AND (o.deleted_at IS NULL OR o.id < u.id)
+);
+
+DROP INDEX IF EXISTS users_email_live_idx;
+ALTER TABLE users ADD CONSTRAINT users_email_key UNIQUE (email);
@@ -0,0 +1,3 @@
+-- deleted users keep their email, a new user may take it meanwhile
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_email_key;
+CREATE UNIQUE INDEX IF NOT EXISTS users_email_live_idx ON users(email) WHERE deleted_at IS NULL;
@@ -0,0 +1 @@
+-- the earlier roles of the managers are not known, they stay managers
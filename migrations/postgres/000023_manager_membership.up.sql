@@ -0,0 +1,5 @@
+-- managers who were members of their project before they took it over kept
+-- their old role
+UPDATE project_members pm SET role = 'manager'
+FROM projects p
+WHERE p.id = pm.project_id AND p.manager_id = pm.user_id AND pm.role <> 'manager';
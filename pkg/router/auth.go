@@ -0,0 +1,32 @@
+package router
+
+import (
+	"net/http"
+	"strings"
+
+	"project-management/pkg/token"
+)
+
+// Authenticator rejects requests without a valid bearer access token and
+// stores the token claims in the request context.
+func Authenticator(tokens *token.Manager) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			raw, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || raw == "" {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			claims, err := tokens.Parse(raw, token.KindAccess)
+			if err != nil {
+				w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(token.WithClaims(r.Context(), claims)))
+		})
+	}
+}
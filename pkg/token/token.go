@@ -0,0 +1,101 @@
+package token
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type Kind string
+
+const (
+	KindAccess  Kind = "access"
+	KindRefresh Kind = "refresh"
+)
+
+var ErrInvalid = errors.New("invalid token")
+
+// Claims carry the user id as the standard subject claim.
+type Claims struct {
+	Role string `json:"role"`
+	Kind Kind   `json:"kind"`
+
+	jwt.RegisteredClaims
+}
+
+func (c Claims) UserID() string {
+	return c.Subject
+}
+
+// Manager issues and verifies HMAC signed JWTs.
+type Manager struct {
+	secret     []byte
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+}
+
+func New(secret string, accessTTL, refreshTTL time.Duration) *Manager {
+	if secret == "" {
+		panic("secret is required")
+	}
+
+	return &Manager{
+		secret:     []byte(secret),
+		accessTTL:  accessTTL,
+		refreshTTL: refreshTTL,
+	}
+}
+
+func (m *Manager) Issue(userID, role string, kind Kind) (token string, expiresAt time.Time, err error) {
+	ttl := m.accessTTL
+	if kind == KindRefresh {
+		ttl = m.refreshTTL
+	}
+
+	now := time.Now()
+	expiresAt = now.Add(ttl)
+
+	claims := Claims{
+		Role: role,
+		Kind: kind,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   userID,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+
+	token, err = jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(m.secret)
+
+	return
+}
+
+// Parse verifies the signature and expiry of token and makes sure it is of the
+// expected kind, so a refresh token can not be used to call the API.
+func (m *Manager) Parse(token string, kind Kind) (claims Claims, err error) {
+	_, err = jwt.ParseWithClaims(token, &claims, func(t *jwt.Token) (any, error) {
+		return m.secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+	if err != nil {
+		return Claims{}, ErrInvalid
+	}
+
+	if claims.Kind != kind || claims.Subject == "" {
+		return Claims{}, ErrInvalid
+	}
+
+	return
+}
+
+type ctxKey struct{}
+
+func WithClaims(ctx context.Context, claims Claims) context.Context {
+	return context.WithValue(ctx, ctxKey{}, claims)
+}
+
+func ClaimsFromContext(ctx context.Context) (claims Claims, ok bool) {
+	claims, ok = ctx.Value(ctxKey{}).(Claims)
+	return
+}